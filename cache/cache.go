@@ -0,0 +1,19 @@
+// Package cache provides the cache the repositories keep hot entities in. Values are opaque bytes, so the
+// in-process LRUCache can be swapped for a cache shared by every instance, such as Redis, by implementing
+// Cache.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache stores values under keys for a limited time. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored under key. ok is false when there is none or it has expired.
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// Set stores value under key until ttl has passed.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the values stored under keys, keys without a value are ignored.
+	Delete(ctx context.Context, keys ...string) error
+}
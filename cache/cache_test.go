@@ -0,0 +1,106 @@
+package cache_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/cache"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheTestSuite struct {
+	suite.Suite
+	now time.Time
+	sut *cache.LRUCache
+}
+
+func (s *CacheTestSuite) SetupTest() {
+	s.now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.sut = cache.NewLRUCache(cache.LRUCacheOptions{
+		Capacity: 2,
+		Now:      func() time.Time { return s.now },
+	})
+}
+
+func TestCache(t *testing.T) {
+	suite.Run(t, new(CacheTestSuite))
+}
+
+func (s *CacheTestSuite) get(key string) (string, bool) {
+	value, ok, err := s.sut.Get(context.Background(), key)
+	s.Require().NoError(err)
+	return string(value), ok
+}
+
+func (s *CacheTestSuite) TestSetGivenFullCacheShouldEvictLeastRecentlyUsedValue() {
+	ctx := context.Background()
+	s.Require().NoError(s.sut.Set(ctx, "a", []byte("1"), time.Minute))
+	s.Require().NoError(s.sut.Set(ctx, "b", []byte("2"), time.Minute))
+	s.get("a")
+	s.Require().NoError(s.sut.Set(ctx, "c", []byte("3"), time.Minute))
+
+	_, ok := s.get("b")
+	s.False(ok)
+	value, ok := s.get("a")
+	s.True(ok)
+	s.Equal("1", value)
+	s.Equal(2, s.sut.Len())
+}
+
+func (s *CacheTestSuite) TestGetGivenExpiredValueShouldMiss() {
+	s.Require().NoError(s.sut.Set(context.Background(), "a", []byte("1"), time.Minute))
+
+	s.now = s.now.Add(59 * time.Second)
+	_, ok := s.get("a")
+	s.True(ok)
+
+	s.now = s.now.Add(time.Second)
+	_, ok = s.get("a")
+	s.False(ok)
+	s.Equal(0, s.sut.Len())
+}
+
+func (s *CacheTestSuite) TestDeleteShouldRemoveValues() {
+	ctx := context.Background()
+	s.Require().NoError(s.sut.Set(ctx, "a", []byte("1"), time.Minute))
+
+	s.Require().NoError(s.sut.Delete(ctx, "a", "unknown"))
+
+	_, ok := s.get("a")
+	s.False(ok)
+}
+
+func (s *CacheTestSuite) TestGroupDoShouldCollapseConcurrentCalls() {
+	var calls int32
+	release := make(chan struct{})
+	joined := make(chan string)
+	group := cache.Group{OnJoin: func(key string) { joined <- key }}
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = group.Do("key", func() interface{} {
+				atomic.AddInt32(&calls, 1)
+				<-release
+				return "value"
+			})
+		}(i)
+	}
+
+	for i := 1; i < len(results); i++ {
+		s.Equal("key", <-joined)
+	}
+	close(release)
+	wg.Wait()
+
+	s.Equal(int32(1), atomic.LoadInt32(&calls))
+	for _, result := range results {
+		s.Equal("value", result)
+	}
+}
@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultLRUCacheCapacity = 10000
+
+type LRUCacheOptions struct {
+	// Capacity is the number of values kept, the least recently used value is evicted to make room for a
+	// new one. Defaults to 10000.
+	Capacity int
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+// LRUCache is an in-process Cache evicting the least recently used values once it is full.
+type LRUCache struct {
+	opts    *LRUCacheOptions
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// order holds the entries from the most to the least recently used.
+	order *list.List
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+func NewLRUCache(opts LRUCacheOptions) *LRUCache {
+	if opts.Capacity <= 0 {
+		opts.Capacity = defaultLRUCacheCapacity
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &LRUCache{
+		opts:    &opts,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+func (c *LRUCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !c.opts.Now().Before(entry.expiresAt) {
+		c.remove(element)
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+func (c *LRUCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.opts.Now().Add(ttl)
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	if c.order.Len() > c.opts.Capacity {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *LRUCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of values held, including expired values that have not been evicted yet.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRUCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruEntry).key)
+}
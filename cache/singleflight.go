@@ -0,0 +1,51 @@
+package cache
+
+import "sync"
+
+// Group collapses concurrent calls for the same key into one, so that a popular entry missing from the
+// cache is loaded once instead of once per request.
+type Group struct {
+	// OnJoin, when set, is called with the key whenever a call joins one that is already running, before it
+	// waits for it. Tests use it to know when callers are waiting.
+	OnJoin func(key string)
+
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	done  chan struct{}
+	value interface{}
+}
+
+// Do calls fn and returns its result, unless a call for key is already running, in which case it waits
+// for that call and returns its result instead. The result may go to several callers, who must not modify
+// it.
+func (g *Group) Do(key string, fn func() interface{}) interface{} {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*call{}
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		if g.OnJoin != nil {
+			g.OnJoin(key)
+		}
+		<-c.done
+		return c.value
+	}
+
+	c := &call{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+
+	c.value = fn()
+	return c.value
+}
@@ -1,67 +1,416 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"flag"
 	"fmt"
-	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	// Embeds the time zone database so timezone validation does not depend on the host.
+	_ "time/tzdata"
 
+	"github.com/SawitProRecruitment/UserService/cache"
+	"github.com/SawitProRecruitment/UserService/config"
 	"github.com/SawitProRecruitment/UserService/generated"
 	"github.com/SawitProRecruitment/UserService/handler"
+	"github.com/SawitProRecruitment/UserService/health"
+	"github.com/SawitProRecruitment/UserService/lifecycle"
+	"github.com/SawitProRecruitment/UserService/logging"
+	"github.com/SawitProRecruitment/UserService/metrics"
+	"github.com/SawitProRecruitment/UserService/migrations"
+	"github.com/SawitProRecruitment/UserService/publisher"
 	"github.com/SawitProRecruitment/UserService/repository"
 	"github.com/SawitProRecruitment/UserService/service"
+	"github.com/SawitProRecruitment/UserService/storage"
+	"github.com/SawitProRecruitment/UserService/tracing"
+	"github.com/SawitProRecruitment/UserService/webhook"
+	"github.com/SawitProRecruitment/UserService/worker"
 
 	"github.com/labstack/echo/v4"
 
 	_ "github.com/lib/pq"
 )
 
+const (
+	// blobStorePath is where the local blob store is served from.
+	blobStorePath = "/blobs"
+	// metricsPath is where Prometheus scrapes the metrics from.
+	metricsPath = "/metrics"
+)
+
 func main() {
-	e := echo.New()
+	cfg, err := config.Load(os.Args[1:], os.LookupEnv)
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		fatal(logging.Default(), "error loading configuration", err)
+	}
 
-	var server generated.ServerInterface = newServer()
+	// Validate has checked the level.
+	level, _ := logging.ParseLevel(cfg.Log.Level)
+	logger := logging.NewLogger(logging.LoggerOptions{
+		Output: os.Stdout,
+		Level:  level,
+	})
+	logging.SetDefault(logger)
+	logger.Info("effective configuration", "config", cfg.String())
 
-	generated.RegisterHandlers(e, server)
-	e.Logger.Fatal(e.Start(":1323"))
-}
+	db, err := newDatabase(cfg.Database)
+	if err != nil {
+		fatal(logger, "error connecting to the database", err)
+	}
+	logger.Info("connected to the database")
+
+	// Components are added in dependency order and stopped in reverse: the HTTP server drains first, then
+	// the workers finish what requests handed them, and the database pool closes last.
+	manager := lifecycle.NewManager(lifecycle.ManagerOptions{
+		ShutdownTimeout: time.Duration(cfg.Server.ShutdownTimeout),
+		DrainDelay:      time.Duration(cfg.Health.DrainDelay),
+		Logger:          logger,
+	})
+	manager.AddCloser("database", db.Close)
 
-func newServer() *handler.Server {
-	db, err := newDatabase()
+	spanExporter, closeSpanExporter, err := newSpanExporter(cfg.Tracing)
 	if err != nil {
-		log.Fatal(err.Error())
+		fatal(logger, "error creating span exporter", err)
+	}
+	if spanExporter != nil {
+		manager.AddCloser("span exporter", closeSpanExporter)
+
+		tracer := tracing.NewTracer(tracing.TracerOptions{
+			ServiceName: cfg.Tracing.ServiceName,
+			Exporter:    spanExporter,
+			QueueSize:   2048,
+			BatchSize:   512,
+			Interval:    5 * time.Second,
+			Logger:      logger,
+		})
+		tracing.SetDefault(tracer)
+		manager.AddWorker("tracer", tracer.Run)
 	}
 
-	userRepository := repository.NewUserRepository(repository.UserRepositoryImplOptions{
-		DB: db,
+	// Without replicas the router stays nil and every query goes to the primary.
+	var replicaRouter *repository.ReplicaRouter
+	if replicaURLs := cfg.Database.Replicas(); len(replicaURLs) > 0 {
+		replicas := make([]*sql.DB, 0, len(replicaURLs))
+		for _, replicaURL := range replicaURLs {
+			replica, err := openDatabase(replicaURL, cfg.Database)
+			if err != nil {
+				fatal(logger, "error opening database replica", err)
+			}
+			manager.AddCloser("database replica", replica.Close)
+			replicas = append(replicas, replica)
+		}
+
+		replicaRouter = repository.NewReplicaRouter(repository.ReplicaRouterOptions{
+			Replicas: replicas,
+			MaxLag:   time.Duration(cfg.Database.ReplicaMaxLag),
+			Logger:   logger,
+		})
+		manager.AddWorker("replica health checks", replicaRouter.Run)
+	}
+
+	// Validate has checked the keys.
+	phoneNumberKeyring, _ := cfg.Encryption.PhoneNumberKeyring()
+	phoneNumberIndex, _ := cfg.Encryption.PhoneNumberIndex()
+
+	// Read committed is enough: writes that depend on what was read are conditional updates or lock their
+	// rows, see the README.
+	transactionManager := repository.NewTransactionManagerImpl(repository.TransactionManagerImplOptions{
+		DB:         db,
+		Isolation:  sql.LevelReadCommitted,
+		MaxRetries: 3,
+	})
+	var userRepository repository.UserRepository = repository.NewUserRepository(repository.UserRepositoryImplOptions{
+		DB:                 db,
+		QueryTimeout:       time.Duration(cfg.Database.QueryTimeout),
+		PhoneNumberKeyring: phoneNumberKeyring,
+		PhoneNumberIndex:   phoneNumberIndex,
+		Replicas:           replicaRouter,
 	})
+	if cfg.UserCache.TTL > 0 {
+		userRepository = repository.NewCachedUserRepository(repository.CachedUserRepositoryOptions{
+			UserRepository: userRepository,
+			Cache:          cache.NewLRUCache(cache.LRUCacheOptions{Capacity: cfg.UserCache.Capacity}),
+			TTL:            time.Duration(cfg.UserCache.TTL),
+			LoadTimeout:    time.Duration(cfg.Database.QueryTimeout),
+		})
+	}
 	loginLogRepository := repository.NewLoginLogRepositoryImpl(repository.LoginLogRepositoryImplOptions{
-		DB: db,
+		DB:           db,
+		QueryTimeout: time.Duration(cfg.Database.QueryTimeout),
+		Replicas:     replicaRouter,
+	})
+	auditEventRepository := repository.NewAuditEventRepositoryImpl(repository.AuditEventRepositoryImplOptions{
+		DB:           db,
+		QueryTimeout: time.Duration(cfg.Database.QueryTimeout),
+		Replicas:     replicaRouter,
+	})
+	outboxRepository := repository.NewOutboxRepositoryImpl(repository.OutboxRepositoryImplOptions{
+		DB:           db,
+		QueryTimeout: time.Duration(cfg.Database.QueryTimeout),
+	})
+	webhookRepository := repository.NewWebhookRepositoryImpl(repository.WebhookRepositoryImplOptions{
+		DB:           db,
+		QueryTimeout: time.Duration(cfg.Database.QueryTimeout),
+	})
+	idempotencyRepository := repository.NewIdempotencyRepositoryImpl(repository.IdempotencyRepositoryImplOptions{
+		DB:           db,
+		QueryTimeout: time.Duration(cfg.Database.QueryTimeout),
+	})
+
+	blobStore, err := storage.NewLocalBlobStore(storage.LocalBlobStoreOptions{
+		Dir:     cfg.BlobStore.Dir,
+		BaseURL: cfg.BlobStore.BaseURL,
+	})
+	if err != nil {
+		fatal(logger, "error creating blob store", err)
+	}
+
+	tokenManager, err := service.NewJWTManager(cfg.Auth.PublicKeyPath, cfg.Auth.PrivateKeyPath, time.Duration(cfg.Auth.TokenTTL))
+	if err != nil {
+		fatal(logger, "error loading token keys", err)
+	}
+	loginLogRecorder := worker.NewLoginLogRecorder(worker.LoginLogRecorderOptions{
+		LoginLogRepository: loginLogRepository,
+		BufferSize:         1000,
+		Logger:             logger,
+	})
+	authService := service.NewAuthServiceImpl(userRepository, loginLogRecorder, auditEventRepository, transactionManager, tokenManager, cfg.Auth.BcryptCost)
+	profileService := service.NewProfileServiceImpl(userRepository, auditEventRepository, transactionManager, tokenManager, blobStore)
+	accountService := service.NewAccountServiceImpl(userRepository, loginLogRepository, auditEventRepository, transactionManager, tokenManager, blobStore, time.Duration(cfg.Account.DeletionGracePeriod))
+	auditService := service.NewAuditServiceImpl(auditEventRepository)
+	webhookService := service.NewWebhookServiceImpl(webhookRepository)
+	idempotencyService := service.NewIdempotencyServiceImpl(idempotencyRepository, time.Duration(cfg.Idempotency.TTL), time.Duration(cfg.Idempotency.Lease))
+	exportService := service.NewExportServiceImpl(userRepository, tokenManager,
+		service.NewProfileExportSection(blobStore),
+		service.NewLoginHistoryExportSection(loginLogRepository),
+		service.NewAuditEventsExportSection(auditEventRepository),
+	)
+
+	healthChecker := health.NewChecker(health.CheckerOptions{
+		Timeout: time.Duration(cfg.Health.CheckTimeout),
+	})
+	healthChecker.AddCheck("database", health.DatabaseCheck(db))
+	healthChecker.AddCheck("database_pool", health.PoolCheck(db.Stats))
+	healthChecker.AddCheck("migrations", health.MigrationCheck(db))
+	healthChecker.AddCheck("signing_key", tokenManager.CheckSigningKey)
+	manager.OnShutdown(healthChecker.SetDraining)
+
+	metrics.Default.RegisterDBStats(db.Stats)
+	metrics.Default.NewGaugeFunc("login_log_queue_depth", "Login logs waiting to be saved.", func() float64 {
+		return float64(loginLogRecorder.QueueDepth())
+	})
+
+	manager.AddWorker("login log recorder", loginLogRecorder.Run)
+
+	anonymizer := worker.NewAccountAnonymizer(worker.AccountAnonymizerOptions{
+		AccountService: accountService,
+		Interval:       time.Hour,
+		BatchSize:      100,
+		Logger:         logger,
+	})
+	manager.AddWorker("account anonymizer", anonymizer.Run)
+
+	phoneNumberReencryptor := worker.NewPhoneNumberReencryptor(worker.PhoneNumberReencryptorOptions{
+		UserRepository: userRepository,
+		Interval:       time.Hour,
+		BatchSize:      100,
+		Logger:         logger,
+	})
+	manager.AddWorker("phone number re-encryptor", phoneNumberReencryptor.Run)
+
+	idempotencyKeyCleaner := worker.NewIdempotencyKeyCleaner(worker.IdempotencyKeyCleanerOptions{
+		IdempotencyService: idempotencyService,
+		Interval:           time.Hour,
+		BatchSize:          1000,
+		Logger:             logger,
+	})
+	manager.AddWorker("idempotency key cleaner", idempotencyKeyCleaner.Run)
+
+	outboxPublisher, closeOutboxPublisher, err := newPublisher(cfg.Outbox)
+	if err != nil {
+		fatal(logger, "error creating outbox publisher", err)
+	}
+	manager.AddCloser("outbox publisher", closeOutboxPublisher)
+
+	outboxRelay := worker.NewOutboxRelay(worker.OutboxRelayOptions{
+		OutboxRepository: outboxRepository,
+		Publisher:        publisher.NewMultiPublisher(outboxPublisher, publisher.NewSubscriptionPublisher(webhookRepository)),
+		Interval:         time.Second,
+		BatchSize:        100,
+		Lease:            time.Minute,
+		MaxBackoff:       time.Hour,
+		Logger:           logger,
 	})
+	manager.AddWorker("outbox relay", outboxRelay.Run)
 
-	tokenManager := service.NewJWTManager(os.Getenv("PUBLIC_KEY_PATH"), os.Getenv("PRIVATE_KEY_PATH"))
-	authService := service.NewAuthServiceImpl(userRepository, loginLogRepository, tokenManager)
-	profileService := service.NewProfileServiceImpl(userRepository, tokenManager)
+	outboxCleaner := worker.NewOutboxCleaner(worker.OutboxCleanerOptions{
+		OutboxRepository: outboxRepository,
+		Retention:        time.Duration(cfg.Outbox.Retention),
+		Interval:         time.Hour,
+		BatchSize:        1000,
+		Logger:           logger,
+	})
+	manager.AddWorker("outbox cleaner", outboxCleaner.Run)
+
+	webhookDispatcher := worker.NewWebhookDispatcher(worker.WebhookDispatcherOptions{
+		WebhookRepository: webhookRepository,
+		Sender:            webhook.NewSender(webhook.SenderOptions{Timeout: 10 * time.Second}),
+		Interval:          time.Second,
+		BatchSize:         100,
+		Lease:             time.Minute,
+		RetryBackoff:      30 * time.Second,
+		MaxBackoff:        6 * time.Hour,
+		MaxAttempts:       10,
+		Logger:            logger,
+	})
+	manager.AddWorker("webhook dispatcher", webhookDispatcher.Run)
 
-	opts := handler.NewServerOptions{
+	var server generated.ServerInterface = handler.NewServer(handler.NewServerOptions{
 		AuthService:    authService,
 		ProfileService: profileService,
+		AccountService: accountService,
+		ExportService:  exportService,
+		AuditService:   auditService,
+		WebhookService: webhookService,
+		HealthChecker:  healthChecker,
+		AdminAPIKey:    cfg.Admin.APIKey,
+	})
+
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.HTTPErrorHandler = handler.HTTPErrorHandler
+	e.Server.ReadTimeout = time.Duration(cfg.Server.ReadTimeout)
+	e.Server.WriteTimeout = time.Duration(cfg.Server.WriteTimeout)
+	e.Server.IdleTimeout = time.Duration(cfg.Server.IdleTimeout)
+	e.Use(handler.TracingMiddleware())
+	e.Use(handler.MetricsMiddleware(metrics.Default))
+	e.Use(handler.RequestMetadataMiddleware(logger))
+	e.Use(handler.AccessLogMiddleware())
+	if cfg.Idempotency.TTL > 0 {
+		fingerprintKey, err := cfg.Idempotency.FingerprintKeyBytes()
+		if err != nil {
+			fatal(logger, "error decoding idempotency fingerprint key", err)
+		}
+		e.Use(handler.IdempotencyMiddleware(idempotencyService, fingerprintKey))
+	}
+	e.Use(handler.RequestTimeoutMiddleware(time.Duration(cfg.Server.RequestTimeout)))
+	generated.RegisterHandlers(e, server)
+	e.Static(blobStorePath, cfg.BlobStore.Dir)
+	manager.AddServer("http server", func() error {
+		logger.Info("http server started", "address", cfg.Server.Address)
+		return e.Start(cfg.Server.Address)
+	}, e.Shutdown)
+
+	if cfg.Server.MetricsAddress != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle(metricsPath, metrics.Default.Handler())
+		metricsServer := &http.Server{
+			Addr:              cfg.Server.MetricsAddress,
+			Handler:           metricsMux,
+			ReadHeaderTimeout: time.Duration(cfg.Server.ReadTimeout),
+		}
+		manager.AddServer("metrics server", func() error {
+			logger.Info("metrics server started", "address", cfg.Server.MetricsAddress)
+			return metricsServer.ListenAndServe()
+		}, metricsServer.Shutdown)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := manager.Run(ctx); err != nil {
+		fatal(logger, "error running the service", err)
 	}
-	return handler.NewServer(opts)
 }
 
-func newDatabase() (*sql.DB, error) {
-	dbDsn := os.Getenv("DATABASE_URL")
+// fatal logs err and exits, for errors the service cannot start or keep running with.
+func fatal(logger *logging.Logger, msg string, err error) {
+	logger.Error(msg, "error", err)
+	os.Exit(1)
+}
 
-	db, err := sql.Open("postgres", dbDsn)
+func newDatabase(cfg config.DatabaseConfig) (*sql.DB, error) {
+	db, err := openDatabase(cfg.URL, cfg)
 	if err != nil {
-		log.Fatal("error opening database:", err)
+		return nil, err
 	}
 
-	err = db.Ping()
-	if err != nil {
-		log.Fatal("error pinging database:", err)
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("error pinging database: %w", err)
+	}
+
+	if err := migrations.Migrate(context.Background(), db); err != nil {
+		return nil, fmt.Errorf("error migrating database: %w", err)
 	}
-	fmt.Println("connected to the database!")
 
 	return db, nil
 }
+
+// openDatabase opens a connection pool with the pool settings of cfg, it connects on first use.
+func openDatabase(dsn string, cfg config.DatabaseConfig) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %w", err)
+	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime))
+	db.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTime))
+	return db, nil
+}
+
+// newSpanExporter creates the span exporter picked by cfg.Exporter, which is nil for none. closeExporter
+// releases what the exporter holds once the tracer has stopped.
+func newSpanExporter(cfg config.TracingConfig) (exporter tracing.Exporter, closeExporter func() error, err error) {
+	noop := func() error { return nil }
+
+	switch cfg.Exporter {
+	case "stdout":
+		return tracing.NewWriterExporter(os.Stdout, cfg.ServiceName), noop, nil
+	case "file":
+		file, err := os.OpenFile(cfg.FilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error opening trace file: %w", err)
+		}
+		return tracing.NewWriterExporter(file, cfg.ServiceName), file.Close, nil
+	case "otlp":
+		return tracing.NewOTLPHTTPExporter(tracing.OTLPHTTPExporterOptions{
+			URL:         cfg.OTLPURL,
+			ServiceName: cfg.ServiceName,
+			Timeout:     10 * time.Second,
+		}), noop, nil
+	default:
+		return nil, noop, nil
+	}
+}
+
+// newPublisher creates the outbox publisher picked by cfg.Publisher, which the configuration has validated.
+func newPublisher(cfg config.OutboxConfig) (outboxPublisher publisher.Publisher, closePublisher func() error, err error) {
+	noop := func() error { return nil }
+
+	switch cfg.Publisher {
+	case "stdout":
+		return publisher.NewStdoutPublisher(), noop, nil
+	case "file":
+		filePublisher, err := publisher.NewFilePublisher(cfg.FilePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error opening outbox file: %w", err)
+		}
+		return filePublisher, filePublisher.Close, nil
+	case "webhook":
+		return publisher.NewWebhookPublisher(publisher.WebhookPublisherOptions{
+			URL:     cfg.WebhookURL,
+			Timeout: 10 * time.Second,
+		}), noop, nil
+	default:
+		return publisher.NewNopPublisher(), noop, nil
+	}
+}
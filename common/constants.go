@@ -4,4 +4,11 @@ type ContextKey string
 
 const (
 	KeyAccessToken ContextKey = "access_token"
+	KeyClientIP    ContextKey = "client_ip"
+	KeyRequestID   ContextKey = "request_id"
+	KeyTransaction ContextKey = "transaction"
+	// KeyReadYourWrites holds the flag set by MarkPrimaryWrite, see WithReadYourWrites.
+	KeyReadYourWrites ContextKey = "read_your_writes"
+	// KeyPrimaryReads marks a context whose reads must not go to a replica, see WithPrimaryReads.
+	KeyPrimaryReads ContextKey = "primary_reads"
 )
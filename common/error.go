@@ -9,12 +9,40 @@ const (
 	ErrEntityNotFound
 	ErrEntityAlreadyExists
 	ErrTooManyAttempts
+	ErrAccountPending
+	ErrAccountSuspended
+	ErrAccountLocked
+	ErrAccountDeleted
+	ErrInvalidStatusTransition
+	// ErrTransactionConflict means the database aborted a transaction because of a concurrent one,
+	// the operation can be retried as is.
+	ErrTransactionConflict
+	// ErrPreconditionFailed means the entity changed since the version the client based its request on.
+	ErrPreconditionFailed
+	ErrPayloadTooLarge
+	ErrUnsupportedMediaType
+	// ErrUnavailable means a dependency such as the database could not be reached, the request can be
+	// retried later.
+	ErrUnavailable
+	// ErrTimeout means the request or a dependency it waited for ran out of time.
+	ErrTimeout
+	// ErrIdempotencyKeyReused means an Idempotency-Key was sent again with a different request.
+	ErrIdempotencyKeyReused
+	// ErrIdempotencyKeyInUse means the first request sent with an Idempotency-Key is still being handled, the
+	// retry can be sent again later.
+	ErrIdempotencyKeyInUse
+	// ErrCanceled means the caller gave up on the operation, such as a client closing its connection, so
+	// nobody waits for its result.
+	ErrCanceled
 )
 
+// CustomError is an error clients may see. Message and Details are public, while Cause keeps the error
+// that led to it for logs and errors.Is or errors.As, and must never be sent to clients.
 type CustomError struct {
 	ErrType ErrType
 	Message string
 	Details []string
+	Cause   error
 }
 
 func NewCustomError(errType ErrType, message string, details ...string) *CustomError {
@@ -25,6 +53,24 @@ func NewCustomError(errType ErrType, message string, details ...string) *CustomE
 	}
 }
 
+// WrapError returns a CustomError caused by cause.
+func WrapError(errType ErrType, cause error, message string, details ...string) *CustomError {
+	return &CustomError{
+		ErrType: errType,
+		Message: message,
+		Details: details,
+		Cause:   cause,
+	}
+}
+
+// Error returns the message followed by the cause, so it may contain internals.
 func (c *CustomError) Error() string {
-	return c.Message
+	if c.Cause == nil {
+		return c.Message
+	}
+	return c.Message + ": " + c.Cause.Error()
+}
+
+func (c *CustomError) Unwrap() error {
+	return c.Cause
 }
@@ -0,0 +1,40 @@
+package common
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// WithReadYourWrites returns a context that remembers whether a write to the primary database was made with
+// it or a context derived from it. Reads made with the context after such a write go to the primary too,
+// instead of a replica that may not have the write yet.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, KeyReadYourWrites, new(atomic.Bool))
+}
+
+// MarkPrimaryWrite records a write to the primary database. It does nothing when ctx does not come from
+// WithReadYourWrites.
+func MarkPrimaryWrite(ctx context.Context) {
+	if wrote, ok := ctx.Value(KeyReadYourWrites).(*atomic.Bool); ok {
+		wrote.Store(true)
+	}
+}
+
+// WrotePrimary reports whether MarkPrimaryWrite has been called with ctx or a context sharing its flag.
+func WrotePrimary(ctx context.Context) bool {
+	wrote, ok := ctx.Value(KeyReadYourWrites).(*atomic.Bool)
+	return ok && wrote.Load()
+}
+
+// WithPrimaryReads returns a context whose reads go to the primary database. Lookups that decide whether a
+// caller may do something, like checking an access token against the status of its user, use it, since a
+// replica may still have the user as it was before a suspension or token revocation.
+func WithPrimaryReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, KeyPrimaryReads, true)
+}
+
+// ReadsFromPrimary reports whether ctx comes from WithPrimaryReads.
+func ReadsFromPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(KeyPrimaryReads).(bool)
+	return primary
+}
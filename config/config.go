@@ -0,0 +1,396 @@
+// Package config loads the service configuration. Every setting has a default that can be overridden by a
+// YAML or TOML file, then by environment variables and finally by command line flags.
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/encryption"
+	"github.com/SawitProRecruitment/UserService/logging"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// minFingerprintKeySize is the shortest key accepted for hashing idempotent requests.
+const minFingerprintKeySize = 32
+
+// Config is the complete service configuration. Field tags name the key in config files (yaml, toml), the
+// environment variable (env) and the flag, which is the dotted file key (e.g. -server.address). Settings
+// tagged secret are redacted when the configuration is printed.
+type Config struct {
+	Server      ServerConfig      `yaml:"server" toml:"server"`
+	Database    DatabaseConfig    `yaml:"database" toml:"database"`
+	Auth        AuthConfig        `yaml:"auth" toml:"auth"`
+	Admin       AdminConfig       `yaml:"admin" toml:"admin"`
+	Account     AccountConfig     `yaml:"account" toml:"account"`
+	BlobStore   BlobStoreConfig   `yaml:"blob_store" toml:"blob_store"`
+	Outbox      OutboxConfig      `yaml:"outbox" toml:"outbox"`
+	Health      HealthConfig      `yaml:"health" toml:"health"`
+	Tracing     TracingConfig     `yaml:"tracing" toml:"tracing"`
+	Log         LogConfig         `yaml:"log" toml:"log"`
+	UserCache   UserCacheConfig   `yaml:"user_cache" toml:"user_cache"`
+	Encryption  EncryptionConfig  `yaml:"encryption" toml:"encryption"`
+	Idempotency IdempotencyConfig `yaml:"idempotency" toml:"idempotency"`
+}
+
+type ServerConfig struct {
+	Address string `yaml:"address" toml:"address" env:"SERVER_ADDRESS" usage:"address the HTTP server listens on"`
+	// MetricsAddress is a listener of its own for /metrics, which is not authenticated and has to stay off
+	// the public network.
+	MetricsAddress string `yaml:"metrics_address" toml:"metrics_address" env:"SERVER_METRICS_ADDRESS" usage:"address the /metrics listener binds to, empty to disable it"`
+	// ShutdownTimeout bounds draining requests and stopping the workers on SIGINT or SIGTERM.
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" usage:"time to drain requests and stop workers on shutdown"`
+	ReadTimeout     Duration `yaml:"read_timeout" toml:"read_timeout" env:"SERVER_READ_TIMEOUT" usage:"time to read a request including its body, 0 for none"`
+	WriteTimeout    Duration `yaml:"write_timeout" toml:"write_timeout" env:"SERVER_WRITE_TIMEOUT" usage:"time from reading the request headers to writing the response, 0 for none"`
+	IdleTimeout     Duration `yaml:"idle_timeout" toml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT" usage:"time a keep-alive connection waits for the next request, 0 for the read timeout"`
+	// RequestTimeout is the deadline of the request context. It has to be shorter than WriteTimeout for the
+	// timeout error to reach the client.
+	RequestTimeout Duration `yaml:"request_timeout" toml:"request_timeout" env:"SERVER_REQUEST_TIMEOUT" usage:"time handlers get to answer a request, 0 for none"`
+}
+
+type DatabaseConfig struct {
+	URL          string `yaml:"url" toml:"url" env:"DATABASE_URL" secret:"true" usage:"PostgreSQL connection URL"`
+	MaxOpenConns int    `yaml:"max_open_conns" toml:"max_open_conns" env:"DATABASE_MAX_OPEN_CONNS" usage:"maximum number of open database connections"`
+	MaxIdleConns int    `yaml:"max_idle_conns" toml:"max_idle_conns" env:"DATABASE_MAX_IDLE_CONNS" usage:"maximum number of idle database connections"`
+	// ConnMaxLifetime lets connections be replaced now and then, so that they move to new servers behind a
+	// load balancer or after a failover.
+	ConnMaxLifetime Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime" env:"DATABASE_CONN_MAX_LIFETIME" usage:"time after which a database connection is closed, 0 for never"`
+	ConnMaxIdleTime Duration `yaml:"conn_max_idle_time" toml:"conn_max_idle_time" env:"DATABASE_CONN_MAX_IDLE_TIME" usage:"time after which an idle database connection is closed, 0 for never"`
+	QueryTimeout    Duration `yaml:"query_timeout" toml:"query_timeout" env:"DATABASE_QUERY_TIMEOUT" usage:"time each repository operation gets, 0 for none"`
+	// ReplicaURLs lists read replicas separated by commas. Replicas share the pool settings of the primary.
+	ReplicaURLs string `yaml:"replica_urls" toml:"replica_urls" env:"DATABASE_REPLICA_URLS" secret:"true" usage:"comma separated PostgreSQL connection URLs of read replicas"`
+	// ReplicaMaxLag is how far a replica may fall behind before reads avoid it.
+	ReplicaMaxLag Duration `yaml:"replica_max_lag" toml:"replica_max_lag" env:"DATABASE_REPLICA_MAX_LAG" usage:"replication lag at which reads stop using a replica, 0 for no limit"`
+}
+
+// Replicas returns the connection URLs of the read replicas.
+func (c DatabaseConfig) Replicas() []string {
+	var urls []string
+	for _, replicaURL := range strings.Split(c.ReplicaURLs, ",") {
+		if replicaURL = strings.TrimSpace(replicaURL); replicaURL != "" {
+			urls = append(urls, replicaURL)
+		}
+	}
+	return urls
+}
+
+type AuthConfig struct {
+	PrivateKeyPath string   `yaml:"private_key_path" toml:"private_key_path" env:"PRIVATE_KEY_PATH" usage:"PEM file of the RSA key signing access tokens"`
+	PublicKeyPath  string   `yaml:"public_key_path" toml:"public_key_path" env:"PUBLIC_KEY_PATH" usage:"PEM file of the RSA key verifying access tokens"`
+	TokenTTL       Duration `yaml:"token_ttl" toml:"token_ttl" env:"TOKEN_TTL" usage:"lifetime of access tokens"`
+	BcryptCost     int      `yaml:"bcrypt_cost" toml:"bcrypt_cost" env:"BCRYPT_COST" usage:"bcrypt cost of password hashes"`
+}
+
+type AdminConfig struct {
+	// APIKey guards the admin endpoints, they reject every request when it is empty.
+	APIKey string `yaml:"api_key" toml:"api_key" env:"ADMIN_API_KEY" secret:"true" usage:"key expected in the X-Admin-Key header"`
+}
+
+type AccountConfig struct {
+	DeletionGracePeriod Duration `yaml:"deletion_grace_period" toml:"deletion_grace_period" env:"ACCOUNT_DELETION_GRACE_PERIOD" usage:"time a deleted account can still be restored"`
+}
+
+type BlobStoreConfig struct {
+	Dir string `yaml:"dir" toml:"dir" env:"BLOB_STORE_DIR" usage:"directory of the local blob store"`
+	// BaseURL defaults to the path the service serves the blob store from.
+	BaseURL string `yaml:"base_url" toml:"base_url" env:"BLOB_STORE_BASE_URL" usage:"URL prefix of stored blobs"`
+}
+
+type OutboxConfig struct {
+	Publisher  string `yaml:"publisher" toml:"publisher" env:"OUTBOX_PUBLISHER" usage:"outbox publisher: none, stdout, file or webhook"`
+	FilePath   string `yaml:"file_path" toml:"file_path" env:"OUTBOX_FILE_PATH" usage:"file the file publisher appends to"`
+	WebhookURL string `yaml:"webhook_url" toml:"webhook_url" env:"OUTBOX_WEBHOOK_URL" usage:"URL the webhook publisher posts to"`
+	// Retention is how long published events are kept, e.g. to look into a delivery, before they are deleted.
+	Retention Duration `yaml:"retention" toml:"retention" env:"OUTBOX_RETENTION" usage:"time published outbox events are kept"`
+}
+
+type HealthConfig struct {
+	CheckTimeout Duration `yaml:"check_timeout" toml:"check_timeout" env:"HEALTH_CHECK_TIMEOUT" usage:"time each readiness check gets"`
+	// DrainDelay keeps serving after /readyz starts failing on shutdown, so load balancers stop routing to
+	// the instance before it stops accepting connections.
+	DrainDelay Duration `yaml:"drain_delay" toml:"drain_delay" env:"HEALTH_DRAIN_DELAY" usage:"time between failing readiness and stopping the HTTP server on shutdown"`
+}
+
+type TracingConfig struct {
+	Exporter    string `yaml:"exporter" toml:"exporter" env:"TRACING_EXPORTER" usage:"span exporter: none, stdout, file or otlp"`
+	FilePath    string `yaml:"file_path" toml:"file_path" env:"TRACING_FILE_PATH" usage:"file the file exporter appends OTLP/JSON to"`
+	OTLPURL     string `yaml:"otlp_url" toml:"otlp_url" env:"TRACING_OTLP_URL" usage:"OTLP/HTTP traces endpoint the otlp exporter posts to"`
+	ServiceName string `yaml:"service_name" toml:"service_name" env:"TRACING_SERVICE_NAME" usage:"service name reported with every span"`
+}
+
+type LogConfig struct {
+	Level string `yaml:"level" toml:"level" env:"LOG_LEVEL" usage:"lowest level logged: debug, info, warn or error"`
+}
+
+type UserCacheConfig struct {
+	Capacity int `yaml:"capacity" toml:"capacity" env:"USER_CACHE_CAPACITY" usage:"number of users kept in the in-process user cache"`
+	// TTL bounds how stale cached users get when an invalidation is missed, like a write by another instance.
+	TTL Duration `yaml:"ttl" toml:"ttl" env:"USER_CACHE_TTL" usage:"time users are cached for, 0 disables the cache"`
+}
+
+type EncryptionConfig struct {
+	// PhoneNumberKeys are comma separated id:key pairs of base64 encoded 32 byte keys. The first one encrypts
+	// and all of them decrypt, so a key is rotated by putting a new one first and removing the old one once
+	// the re-encryption job has moved every phone number off it.
+	PhoneNumberKeys string `yaml:"phone_number_keys" toml:"phone_number_keys" env:"PHONE_NUMBER_ENCRYPTION_KEYS" secret:"true" usage:"id:key pairs of the keys encrypting phone numbers, active key first"`
+	// PhoneNumberIndexKey keys the blind index phone numbers are looked up by. It cannot be rotated by the
+	// re-encryption job.
+	PhoneNumberIndexKey string `yaml:"phone_number_index_key" toml:"phone_number_index_key" env:"PHONE_NUMBER_INDEX_KEY" secret:"true" usage:"base64 encoded key of at least 32 bytes hashing phone numbers for lookup"`
+}
+
+type IdempotencyConfig struct {
+	TTL Duration `yaml:"ttl" toml:"ttl" env:"IDEMPOTENCY_KEY_TTL" usage:"time responses to requests with an Idempotency-Key are kept for retries, 0 ignores the header"`
+	// Lease is how long a request holds its key. A retry after that runs the request again, which covers
+	// requests lost without releasing their key. It has to be longer than requests can take.
+	Lease Duration `yaml:"lease" toml:"lease" env:"IDEMPOTENCY_KEY_LEASE" usage:"time a request holds its Idempotency-Key before a retry runs it again"`
+	// FingerprintKey keys the hashes of stored requests, whose bodies may hold passwords.
+	FingerprintKey string `yaml:"fingerprint_key" toml:"fingerprint_key" env:"IDEMPOTENCY_FINGERPRINT_KEY" secret:"true" usage:"base64 encoded key of at least 32 bytes hashing requests sent with an Idempotency-Key"`
+}
+
+// PhoneNumberKeyring returns the keyring encrypting phone numbers.
+func (c EncryptionConfig) PhoneNumberKeyring() (*encryption.Keyring, error) {
+	keys, err := encryption.ParseKeys(c.PhoneNumberKeys)
+	if err != nil {
+		return nil, err
+	}
+	return encryption.NewKeyring(keys)
+}
+
+// PhoneNumberIndex returns the blind index phone numbers are looked up by.
+func (c EncryptionConfig) PhoneNumberIndex() (*encryption.BlindIndex, error) {
+	key, err := base64.StdEncoding.DecodeString(c.PhoneNumberIndexKey)
+	if err != nil {
+		return nil, errors.New("key is not valid base64")
+	}
+	return encryption.NewBlindIndex(key)
+}
+
+// FingerprintKeyBytes returns the key hashing requests sent with an Idempotency-Key.
+func (c IdempotencyConfig) FingerprintKeyBytes() ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(c.FingerprintKey)
+	if err != nil {
+		return nil, errors.New("key is not valid base64")
+	}
+	if len(key) < minFingerprintKeySize {
+		return nil, fmt.Errorf("key must be at least %d bytes long", minFingerprintKeySize)
+	}
+	return key, nil
+}
+
+// Duration is a time.Duration written as a Go duration string such as "1h30m" in every source.
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	value, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(value)
+	return nil
+}
+
+// Default returns the configuration used for settings no source sets.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Address:         ":1323",
+			MetricsAddress:  ":9464",
+			ShutdownTimeout: Duration(30 * time.Second),
+			ReadTimeout:     Duration(30 * time.Second),
+			WriteTimeout:    Duration(30 * time.Second),
+			IdleTimeout:     Duration(2 * time.Minute),
+			RequestTimeout:  Duration(20 * time.Second),
+		},
+		Database: DatabaseConfig{
+			MaxOpenConns:    20,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: Duration(30 * time.Minute),
+			ConnMaxIdleTime: Duration(5 * time.Minute),
+			QueryTimeout:    Duration(5 * time.Second),
+			ReplicaMaxLag:   Duration(10 * time.Second),
+		},
+		Auth: AuthConfig{
+			TokenTTL:   Duration(time.Hour),
+			BcryptCost: bcrypt.DefaultCost,
+		},
+		Account: AccountConfig{
+			DeletionGracePeriod: Duration(30 * 24 * time.Hour),
+		},
+		BlobStore: BlobStoreConfig{
+			Dir:     "data/blobs",
+			BaseURL: "/blobs",
+		},
+		Outbox: OutboxConfig{
+			Publisher: "none",
+			Retention: Duration(7 * 24 * time.Hour),
+		},
+		Health: HealthConfig{
+			CheckTimeout: Duration(2 * time.Second),
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "user-service",
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		UserCache: UserCacheConfig{
+			Capacity: 10000,
+			TTL:      Duration(time.Minute),
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:   Duration(24 * time.Hour),
+			Lease: Duration(time.Minute),
+		},
+	}
+}
+
+// ValidationError lists every invalid setting of a configuration.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// Validate checks every setting and reports all problems at once.
+func (c Config) Validate() error {
+	problems := []string{}
+
+	if c.Server.Address == "" {
+		problems = append(problems, "server.address is required")
+	}
+	if c.Server.MetricsAddress != "" && c.Server.MetricsAddress == c.Server.Address {
+		problems = append(problems, "server.metrics_address must differ from server.address")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		problems = append(problems, "server.shutdown_timeout must be positive")
+	}
+	if c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 || c.Server.RequestTimeout < 0 {
+		problems = append(problems, "server timeouts must not be negative")
+	}
+	if c.Server.WriteTimeout > 0 && (c.Server.RequestTimeout == 0 || c.Server.RequestTimeout >= c.Server.WriteTimeout) {
+		problems = append(problems, "server.request_timeout must be shorter than server.write_timeout")
+	}
+
+	if c.Database.URL == "" {
+		problems = append(problems, "database.url is required")
+	}
+	if c.Database.MaxOpenConns < 1 {
+		problems = append(problems, "database.max_open_conns must be positive")
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		problems = append(problems, "database.max_idle_conns must be between 0 and database.max_open_conns")
+	}
+	if c.Database.ConnMaxLifetime < 0 || c.Database.ConnMaxIdleTime < 0 || c.Database.QueryTimeout < 0 || c.Database.ReplicaMaxLag < 0 {
+		problems = append(problems, "database timeouts must not be negative")
+	}
+	for i, replicaURL := range c.Database.Replicas() {
+		// The URL itself is not quoted, it may hold a password.
+		if parsed, err := url.Parse(replicaURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			problems = append(problems, fmt.Sprintf("database.replica_urls[%d] must be an absolute URL", i))
+		}
+	}
+
+	if c.Auth.PrivateKeyPath == "" {
+		problems = append(problems, "auth.private_key_path is required")
+	}
+	if c.Auth.PublicKeyPath == "" {
+		problems = append(problems, "auth.public_key_path is required")
+	}
+	if c.Auth.TokenTTL <= 0 {
+		problems = append(problems, "auth.token_ttl must be positive")
+	}
+	if c.Auth.BcryptCost < bcrypt.MinCost || c.Auth.BcryptCost > bcrypt.MaxCost {
+		problems = append(problems, fmt.Sprintf("auth.bcrypt_cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost))
+	}
+
+	if c.Account.DeletionGracePeriod < 0 {
+		problems = append(problems, "account.deletion_grace_period must not be negative")
+	}
+
+	if c.BlobStore.Dir == "" {
+		problems = append(problems, "blob_store.dir is required")
+	}
+
+	switch c.Outbox.Publisher {
+	case "none", "stdout":
+	case "file":
+		if c.Outbox.FilePath == "" {
+			problems = append(problems, "outbox.file_path is required by the file publisher")
+		}
+	case "webhook":
+		if parsed, err := url.Parse(c.Outbox.WebhookURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			problems = append(problems, "outbox.webhook_url must be an absolute URL for the webhook publisher")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("outbox.publisher %q is not one of none, stdout, file or webhook", c.Outbox.Publisher))
+	}
+	if c.Outbox.Retention <= 0 {
+		problems = append(problems, "outbox.retention must be positive")
+	}
+
+	if c.Health.CheckTimeout <= 0 {
+		problems = append(problems, "health.check_timeout must be positive")
+	}
+	if c.Health.DrainDelay < 0 {
+		problems = append(problems, "health.drain_delay must not be negative")
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "file":
+		if c.Tracing.FilePath == "" {
+			problems = append(problems, "tracing.file_path is required by the file exporter")
+		}
+	case "otlp":
+		if parsed, err := url.Parse(c.Tracing.OTLPURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			problems = append(problems, "tracing.otlp_url must be an absolute URL for the otlp exporter")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("tracing.exporter %q is not one of none, stdout, file or otlp", c.Tracing.Exporter))
+	}
+
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		problems = append(problems, fmt.Sprintf("log.level %q is not one of debug, info, warn or error", c.Log.Level))
+	}
+
+	if c.UserCache.Capacity < 1 {
+		problems = append(problems, "user_cache.capacity must be positive")
+	}
+	if c.UserCache.TTL < 0 {
+		problems = append(problems, "user_cache.ttl must not be negative")
+	}
+
+	if _, err := c.Encryption.PhoneNumberKeyring(); err != nil {
+		problems = append(problems, fmt.Sprintf("encryption.phone_number_keys: %s", err))
+	}
+	if _, err := c.Encryption.PhoneNumberIndex(); err != nil {
+		problems = append(problems, fmt.Sprintf("encryption.phone_number_index_key: %s", err))
+	}
+
+	if c.Idempotency.TTL < 0 {
+		problems = append(problems, "idempotency.ttl must not be negative")
+	}
+	if c.Idempotency.TTL > 0 && c.Idempotency.Lease <= c.Server.RequestTimeout {
+		problems = append(problems, "idempotency.lease must be longer than server.request_timeout")
+	}
+	if _, err := c.Idempotency.FingerprintKeyBytes(); c.Idempotency.TTL > 0 && err != nil {
+		problems = append(problems, fmt.Sprintf("idempotency.fingerprint_key: %s", err))
+	}
+
+	if len(problems) != 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[REDACTED]"
+
+// setting is a single configurable value of Config.
+type setting struct {
+	// key is the dotted path of the value in config files, it is also the name of its flag.
+	key    string
+	env    string
+	usage  string
+	secret bool
+	value  reflect.Value
+}
+
+// Load builds the configuration from the defaults, the file named by -config or CONFIG_FILE, the
+// environment and the flags in args, each source overriding the previous one. All invalid settings are
+// reported together in a *ValidationError.
+func Load(args []string, lookupEnv func(key string) (string, bool)) (Config, error) {
+	cfg := Default()
+	settings := collectSettings(&cfg)
+
+	flags := flag.NewFlagSet("user-service", flag.ContinueOnError)
+	configPath := flags.String("config", "", "YAML or TOML configuration file (env CONFIG_FILE)")
+	flagValues := map[string]*string{}
+	for _, s := range settings {
+		flagValues[s.key] = flags.String(s.key, "", fmt.Sprintf("%s (env %s)", s.usage, s.env))
+	}
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	if *configPath == "" {
+		*configPath, _ = lookupEnv("CONFIG_FILE")
+	}
+	if *configPath != "" {
+		if err := loadFile(*configPath, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+
+	problems := []string{}
+	for _, s := range settings {
+		if value, ok := lookupEnv(s.env); ok {
+			if err := setValue(s.value, value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: invalid value of %s: %s", s.key, s.env, err.Error()))
+			}
+		}
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.key == f.Name {
+				if err := setValue(s.value, *flagValues[s.key]); err != nil {
+					problems = append(problems, fmt.Sprintf("%s: invalid value of -%s: %s", s.key, s.key, err.Error()))
+				}
+			}
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		problems = append(problems, err.(*ValidationError).Problems...)
+	}
+	if len(problems) != 0 {
+		return Config{}, &ValidationError{Problems: problems}
+	}
+	return cfg, nil
+}
+
+// loadFile decodes a YAML or TOML file, picked by its extension. Unknown keys are rejected so that typos
+// do not silently fall back to defaults.
+func loadFile(path string, cfg *Config) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		err = decoder.Decode(cfg)
+	case ".toml":
+		decoder := toml.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(cfg)
+	default:
+		return fmt.Errorf("config file %s: unsupported extension %q, use .yaml, .yml or .toml", path, ext)
+	}
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// String lists the effective settings one per line, with secrets redacted.
+func (c Config) String() string {
+	var builder strings.Builder
+	for _, s := range collectSettings(&c) {
+		value := formatValue(s.value)
+		if s.secret && value != "" {
+			value = redacted
+		}
+		fmt.Fprintf(&builder, "%s = %s\n", s.key, value)
+	}
+	return builder.String()
+}
+
+func collectSettings(cfg *Config) []setting {
+	return appendSettings(nil, "", reflect.ValueOf(cfg).Elem())
+}
+
+func appendSettings(settings []setting, prefix string, value reflect.Value) []setting {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		key := prefix + field.Tag.Get("yaml")
+
+		if field.Type.Kind() == reflect.Struct {
+			settings = appendSettings(settings, key+".", value.Field(i))
+			continue
+		}
+
+		settings = append(settings, setting{
+			key:    key,
+			env:    field.Tag.Get("env"),
+			usage:  field.Tag.Get("usage"),
+			secret: field.Tag.Get("secret") == "true",
+			value:  value.Field(i),
+		})
+	}
+	return settings
+}
+
+func setValue(value reflect.Value, text string) error {
+	if unmarshaler, ok := value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(text))
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(text)
+	case reflect.Int:
+		parsed, err := strconv.Atoi(text)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(parsed))
+	default:
+		return fmt.Errorf("unsupported setting type %s", value.Type())
+	}
+	return nil
+}
+
+func formatValue(value reflect.Value) string {
+	if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return err.Error()
+		}
+		return string(text)
+	}
+	return fmt.Sprint(value.Interface())
+}
@@ -0,0 +1,33 @@
+package encryption
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+)
+
+// MinBlindIndexKeySize is the smallest key a blind index accepts.
+const MinBlindIndexKeySize = 32
+
+// BlindIndex derives a keyed HMAC-SHA256 of a value. Equal values get equal indexes, so an index column can
+// be searched and kept unique without storing the value, and without the key the indexes cannot be matched
+// against guessed values.
+type BlindIndex struct {
+	key []byte
+}
+
+func NewBlindIndex(key []byte) (*BlindIndex, error) {
+	if len(key) < MinBlindIndexKeySize {
+		return nil, fmt.Errorf("blind index key must be at least %d bytes long", MinBlindIndexKeySize)
+	}
+	return &BlindIndex{
+		key: key,
+	}, nil
+}
+
+// Compute returns the index of value. Values have to be normalized first, e.g. phone numbers to E.164.
+func (b *BlindIndex) Compute(value string) []byte {
+	mac := hmac.New(sha256.New, b.key)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,140 @@
+package encryption_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/encryption"
+	"github.com/stretchr/testify/suite"
+)
+
+type EncryptionTestSuite struct {
+	suite.Suite
+	oldKey encryption.Key
+	newKey encryption.Key
+}
+
+func (s *EncryptionTestSuite) SetupTest() {
+	s.oldKey = encryption.Key{ID: "2024-01", Secret: bytes.Repeat([]byte{1}, encryption.KeySize)}
+	s.newKey = encryption.Key{ID: "2024-06", Secret: bytes.Repeat([]byte{2}, encryption.KeySize)}
+}
+
+func TestEncryption(t *testing.T) {
+	suite.Run(t, new(EncryptionTestSuite))
+}
+
+func (s *EncryptionTestSuite) newKeyring(keys ...encryption.Key) *encryption.Keyring {
+	keyring, err := encryption.NewKeyring(keys)
+	s.Require().NoError(err)
+	return keyring
+}
+
+func (s *EncryptionTestSuite) TestDecryptShouldReturnEncryptedValue() {
+	keyring := s.newKeyring(s.oldKey)
+
+	ciphertext, err := keyring.Encrypt([]byte("+628111111111"), []byte("user-1"))
+	s.Require().NoError(err)
+	s.NotContains(string(ciphertext), "628111111111")
+
+	plaintext, err := keyring.Decrypt(ciphertext, []byte("user-1"))
+	s.Require().NoError(err)
+	s.Equal("+628111111111", string(plaintext))
+}
+
+func (s *EncryptionTestSuite) TestEncryptGivenSameValueTwiceShouldReturnDifferentCiphertexts() {
+	keyring := s.newKeyring(s.oldKey)
+
+	first, err := keyring.Encrypt([]byte("+628111111111"), nil)
+	s.Require().NoError(err)
+	second, err := keyring.Encrypt([]byte("+628111111111"), nil)
+	s.Require().NoError(err)
+
+	s.NotEqual(first, second)
+}
+
+func (s *EncryptionTestSuite) TestDecryptGivenRotatedKeysShouldOpenValuesOfEveryKey() {
+	ciphertext, err := s.newKeyring(s.oldKey).Encrypt([]byte("+628111111111"), nil)
+	s.Require().NoError(err)
+
+	rotated := s.newKeyring(s.newKey, s.oldKey)
+	plaintext, err := rotated.Decrypt(ciphertext, nil)
+
+	s.Require().NoError(err)
+	s.Equal("+628111111111", string(plaintext))
+	s.Equal("2024-06", rotated.ActiveKeyID())
+}
+
+func (s *EncryptionTestSuite) TestDecryptGivenRemovedKeyShouldFail() {
+	ciphertext, err := s.newKeyring(s.oldKey).Encrypt([]byte("+628111111111"), nil)
+	s.Require().NoError(err)
+
+	_, err = s.newKeyring(s.newKey).Decrypt(ciphertext, nil)
+
+	s.EqualError(err, `key "2024-01" is not configured`)
+}
+
+func (s *EncryptionTestSuite) TestDecryptGivenOtherAssociatedDataShouldFail() {
+	keyring := s.newKeyring(s.oldKey)
+	ciphertext, err := keyring.Encrypt([]byte("+628111111111"), []byte("user-1"))
+	s.Require().NoError(err)
+
+	_, err = keyring.Decrypt(ciphertext, []byte("user-2"))
+
+	s.Error(err)
+}
+
+func (s *EncryptionTestSuite) TestDecryptGivenTamperedCiphertextShouldFail() {
+	keyring := s.newKeyring(s.oldKey)
+	ciphertext, err := keyring.Encrypt([]byte("+628111111111"), nil)
+	s.Require().NoError(err)
+
+	ciphertext[len(ciphertext)-1] ^= 1
+	_, err = keyring.Decrypt(ciphertext, nil)
+	s.Error(err)
+
+	_, err = keyring.Decrypt(ciphertext[:10], nil)
+	s.Error(err)
+}
+
+func (s *EncryptionTestSuite) TestNewKeyringGivenInvalidKeysShouldFail() {
+	_, err := encryption.NewKeyring(nil)
+	s.Error(err)
+
+	_, err = encryption.NewKeyring([]encryption.Key{{ID: "short", Secret: []byte("too short")}})
+	s.EqualError(err, `key "short" must be 32 bytes long`)
+
+	_, err = encryption.NewKeyring([]encryption.Key{s.oldKey, s.oldKey})
+	s.EqualError(err, `key "2024-01" is listed twice`)
+}
+
+func (s *EncryptionTestSuite) TestParseKeysShouldDecodeIDKeyPairs() {
+	encoded := base64.StdEncoding.EncodeToString(s.newKey.Secret)
+
+	keys, err := encryption.ParseKeys(" 2024-06:" + encoded + ", 2024-01:" + base64.StdEncoding.EncodeToString(s.oldKey.Secret))
+
+	s.Require().NoError(err)
+	s.Equal([]encryption.Key{s.newKey, s.oldKey}, keys)
+}
+
+func (s *EncryptionTestSuite) TestParseKeysGivenInvalidKeyShouldNotReportIt() {
+	_, err := encryption.ParseKeys("2024-06:not base64!")
+	s.EqualError(err, `key "2024-06" is not valid base64`)
+
+	_, err = encryption.ParseKeys("no-id")
+	s.EqualError(err, "key 0 is not an id:key pair")
+}
+
+func (s *EncryptionTestSuite) TestBlindIndexShouldDependOnValueAndKey() {
+	index, err := encryption.NewBlindIndex(s.oldKey.Secret)
+	s.Require().NoError(err)
+	otherIndex, err := encryption.NewBlindIndex(s.newKey.Secret)
+	s.Require().NoError(err)
+
+	s.Equal(index.Compute("+628111111111"), index.Compute("+628111111111"))
+	s.NotEqual(index.Compute("+628111111111"), index.Compute("+628222222222"))
+	s.NotEqual(index.Compute("+628111111111"), otherIndex.Compute("+628111111111"))
+
+	_, err = encryption.NewBlindIndex([]byte("short"))
+	s.Error(err)
+}
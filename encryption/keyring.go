@@ -0,0 +1,186 @@
+// Package encryption protects individual fields at rest. Values are sealed with AES-GCM under a data key
+// of their own, which is in turn sealed with a key encryption key from the configuration (envelope
+// encryption). Blind indexes make encrypted values searchable by equality.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// KeySize is the size of key encryption keys and data keys, which makes them AES-256 keys.
+	KeySize = 32
+
+	formatVersion  byte = 1
+	maxKeyIDLength      = 255
+)
+
+var errMalformedCiphertext = errors.New("ciphertext is malformed")
+
+// Key is a key encryption key. Its ID is stored with every value it protects.
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// ParseKeys parses keys written as comma separated id:key pairs, with base64 encoded keys.
+func ParseKeys(s string) ([]Key, error) {
+	var keys []Key
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		id, encoded, found := strings.Cut(pair, ":")
+		if !found {
+			return nil, fmt.Errorf("key %d is not an id:key pair", len(keys))
+		}
+
+		secret, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			// The error holds part of the key, so it is left out.
+			return nil, fmt.Errorf("key %q is not valid base64", id)
+		}
+		keys = append(keys, Key{ID: id, Secret: secret})
+	}
+	return keys, nil
+}
+
+// Keyring encrypts with its active key and decrypts with any of its keys, so that keys can be rotated
+// while values sealed with the previous key are still around.
+type Keyring struct {
+	activeKeyID string
+	keys        map[string]cipher.AEAD
+}
+
+// NewKeyring creates a keyring with the first key active.
+func NewKeyring(keys []Key) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one key is required")
+	}
+
+	keyring := &Keyring{
+		activeKeyID: keys[0].ID,
+		keys:        make(map[string]cipher.AEAD, len(keys)),
+	}
+	for _, key := range keys {
+		if key.ID == "" || len(key.ID) > maxKeyIDLength {
+			return nil, fmt.Errorf("key id must have 1 to %d characters", maxKeyIDLength)
+		}
+		if _, ok := keyring.keys[key.ID]; ok {
+			return nil, fmt.Errorf("key %q is listed twice", key.ID)
+		}
+		if len(key.Secret) != KeySize {
+			return nil, fmt.Errorf("key %q must be %d bytes long", key.ID, KeySize)
+		}
+
+		aead, err := newAEAD(key.Secret)
+		if err != nil {
+			return nil, err
+		}
+		keyring.keys[key.ID] = aead
+	}
+	return keyring, nil
+}
+
+// ActiveKeyID returns the ID of the key new values are encrypted with.
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeKeyID
+}
+
+// Encrypt seals plaintext with a new data key wrapped by the active key. associatedData is authenticated but
+// not stored, decrypting needs the same value. Binding a value to its row this way keeps ciphertexts from
+// being swapped between rows.
+func (k *Keyring) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	dataKey := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, fmt.Errorf("error generating data key: %w", err)
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	keyAEAD := k.keys[k.activeKeyID]
+
+	// The layout is version, key ID length, key ID, wrapped data key and sealed value, where the last two
+	// start with their nonce.
+	ciphertext := []byte{formatVersion, byte(len(k.activeKeyID))}
+	ciphertext = append(ciphertext, k.activeKeyID...)
+	if ciphertext, err = seal(keyAEAD, ciphertext, dataKey, []byte(k.activeKeyID)); err != nil {
+		return nil, err
+	}
+	return seal(dataAEAD, ciphertext, plaintext, associatedData)
+}
+
+// Decrypt opens a ciphertext returned by Encrypt, with the associatedData it was encrypted with.
+func (k *Keyring) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	if len(ciphertext) < 2 || ciphertext[0] != formatVersion {
+		return nil, errMalformedCiphertext
+	}
+	keyIDLength := int(ciphertext[1])
+	if len(ciphertext) < 2+keyIDLength {
+		return nil, errMalformedCiphertext
+	}
+	keyID := string(ciphertext[2 : 2+keyIDLength])
+	rest := ciphertext[2+keyIDLength:]
+
+	keyAEAD, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("key %q is not configured", keyID)
+	}
+
+	wrappedKeyLength := keyAEAD.NonceSize() + KeySize + keyAEAD.Overhead()
+	if len(rest) < wrappedKeyLength {
+		return nil, errMalformedCiphertext
+	}
+	dataKey, err := open(keyAEAD, rest[:wrappedKeyLength], []byte(keyID))
+	if err != nil {
+		return nil, err
+	}
+
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return open(dataAEAD, rest[wrappedKeyLength:], associatedData)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal appends a random nonce and the sealed plaintext to dst.
+func seal(aead cipher.AEAD, dst, plaintext, associatedData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %w", err)
+	}
+	dst = append(dst, nonce...)
+	return aead.Seal(dst, nonce, plaintext, associatedData), nil
+}
+
+// open opens a nonce followed by the sealed value.
+func open(aead cipher.AEAD, sealed, associatedData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize()+aead.Overhead() {
+		return nil, errMalformedCiphertext
+	}
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, sealed, associatedData)
+	if err != nil {
+		return nil, errors.New("ciphertext cannot be authenticated")
+	}
+	return plaintext, nil
+}
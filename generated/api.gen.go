@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/deepmap/oapi-codegen/pkg/runtime"
 	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
@@ -19,20 +20,218 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// Defines values for AuditEventActorType.
+const (
+	Admin  AuditEventActorType = "admin"
+	System AuditEventActorType = "system"
+	User   AuditEventActorType = "user"
+)
+
+// Defines values for CreateWebhookSubscriptionRequestEventTypes.
+const (
+	CreateWebhookSubscriptionRequestEventTypesUserAnonymized         CreateWebhookSubscriptionRequestEventTypes = "user.anonymized"
+	CreateWebhookSubscriptionRequestEventTypesUserPhoneNumberChanged CreateWebhookSubscriptionRequestEventTypes = "user.phone_number_changed"
+	CreateWebhookSubscriptionRequestEventTypesUserProfileUpdated     CreateWebhookSubscriptionRequestEventTypes = "user.profile_updated"
+	CreateWebhookSubscriptionRequestEventTypesUserRegistered         CreateWebhookSubscriptionRequestEventTypes = "user.registered"
+	CreateWebhookSubscriptionRequestEventTypesUserStatusChanged      CreateWebhookSubscriptionRequestEventTypes = "user.status_changed"
+)
+
+// Defines values for HealthCheckStatus.
+const (
+	HealthCheckStatusFailing HealthCheckStatus = "failing"
+	HealthCheckStatusOk      HealthCheckStatus = "ok"
+)
+
+// Defines values for HealthReportStatus.
+const (
+	HealthReportStatusDraining HealthReportStatus = "draining"
+	HealthReportStatusFailing  HealthReportStatus = "failing"
+	HealthReportStatusOk       HealthReportStatus = "ok"
+)
+
+// Defines values for UpdateUserStatusRequestStatus.
+const (
+	UpdateUserStatusRequestStatusActive    UpdateUserStatusRequestStatus = "active"
+	UpdateUserStatusRequestStatusDeleted   UpdateUserStatusRequestStatus = "deleted"
+	UpdateUserStatusRequestStatusLocked    UpdateUserStatusRequestStatus = "locked"
+	UpdateUserStatusRequestStatusPending   UpdateUserStatusRequestStatus = "pending"
+	UpdateUserStatusRequestStatusSuspended UpdateUserStatusRequestStatus = "suspended"
+)
+
+// Defines values for UpdateWebhookSubscriptionRequestEventTypes.
+const (
+	UpdateWebhookSubscriptionRequestEventTypesUserAnonymized         UpdateWebhookSubscriptionRequestEventTypes = "user.anonymized"
+	UpdateWebhookSubscriptionRequestEventTypesUserPhoneNumberChanged UpdateWebhookSubscriptionRequestEventTypes = "user.phone_number_changed"
+	UpdateWebhookSubscriptionRequestEventTypesUserProfileUpdated     UpdateWebhookSubscriptionRequestEventTypes = "user.profile_updated"
+	UpdateWebhookSubscriptionRequestEventTypesUserRegistered         UpdateWebhookSubscriptionRequestEventTypes = "user.registered"
+	UpdateWebhookSubscriptionRequestEventTypesUserStatusChanged      UpdateWebhookSubscriptionRequestEventTypes = "user.status_changed"
+)
+
+// Defines values for WebhookDeliveryStatus.
+const (
+	WebhookDeliveryStatusDeadLettered WebhookDeliveryStatus = "dead_lettered"
+	WebhookDeliveryStatusDelivered    WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusPending      WebhookDeliveryStatus = "pending"
+)
+
+// Defines values for WebhookSubscriptionEventTypes.
+const (
+	UserAnonymized         WebhookSubscriptionEventTypes = "user.anonymized"
+	UserPhoneNumberChanged WebhookSubscriptionEventTypes = "user.phone_number_changed"
+	UserProfileUpdated     WebhookSubscriptionEventTypes = "user.profile_updated"
+	UserRegistered         WebhookSubscriptionEventTypes = "user.registered"
+	UserStatusChanged      WebhookSubscriptionEventTypes = "user.status_changed"
+)
+
+// Defines values for GetApiV1AdminWebhooksWebhookIdDeliveriesParamsStatus.
+const (
+	DeadLettered GetApiV1AdminWebhooksWebhookIdDeliveriesParamsStatus = "dead_lettered"
+	Delivered    GetApiV1AdminWebhooksWebhookIdDeliveriesParamsStatus = "delivered"
+	Pending      GetApiV1AdminWebhooksWebhookIdDeliveriesParamsStatus = "pending"
+)
+
+// Defines values for GetApiV1UsersMeExportParamsFormat.
+const (
+	Json GetApiV1UsersMeExportParamsFormat = "json"
+	Zip  GetApiV1UsersMeExportParamsFormat = "zip"
+)
+
+// AuditChainVerification defines model for AuditChainVerification.
+type AuditChainVerification struct {
+	// BrokenAtSequence Sequence of the first event whose hash does not match, only set when the chain is not valid
+	BrokenAtSequence *int64 `json:"broken_at_sequence,omitempty"`
+	CheckedEvents    int64  `json:"checked_events"`
+	Valid            bool   `json:"valid"`
+}
+
+// AuditChange defines model for AuditChange.
+type AuditChange struct {
+	After  *string `json:"after"`
+	Before *string `json:"before"`
+
+	// Redacted Set when the field holds personal data, whose values are not recorded.
+	Redacted *bool `json:"redacted,omitempty"`
+}
+
+// AuditEvent defines model for AuditEvent.
+type AuditEvent struct {
+	Action       string                 `json:"action"`
+	ActorId      *openapi_types.UUID    `json:"actor_id,omitempty"`
+	ActorType    AuditEventActorType    `json:"actor_type"`
+	Changes      map[string]AuditChange `json:"changes"`
+	CreatedAt    time.Time              `json:"created_at"`
+	Hash         string                 `json:"hash"`
+	Id           openapi_types.UUID     `json:"id"`
+	IpAddress    string                 `json:"ip_address"`
+	PreviousHash string                 `json:"previous_hash"`
+	RequestId    string                 `json:"request_id"`
+	Sequence     int64                  `json:"sequence"`
+	TargetUserId *openapi_types.UUID    `json:"target_user_id,omitempty"`
+}
+
+// AuditEventActorType defines model for AuditEvent.ActorType.
+type AuditEventActorType string
+
+// AuditEventList defines model for AuditEventList.
+type AuditEventList struct {
+	Events             []AuditEvent `json:"events"`
+	NextBeforeSequence *int64       `json:"next_before_sequence,omitempty"`
+}
+
+// AvatarUrls Square JPEG thumbnails of the avatar, absent when no avatar has been uploaded
+type AvatarUrls struct {
+	// Large 512 x 512 pixels
+	Large string `json:"large"`
+
+	// Medium 256 x 256 pixels
+	Medium string `json:"medium"`
+
+	// Small 64 x 64 pixels
+	Small string `json:"small"`
+}
+
+// CreateWebhookSubscriptionRequest defines model for CreateWebhookSubscriptionRequest.
+type CreateWebhookSubscriptionRequest struct {
+	EventTypes *[]CreateWebhookSubscriptionRequestEventTypes `json:"event_types,omitempty"`
+	Secret     *string                                       `json:"secret,omitempty"`
+	Url        string                                        `json:"url"`
+}
+
+// CreateWebhookSubscriptionRequestEventTypes defines model for CreateWebhookSubscriptionRequest.EventTypes.
+type CreateWebhookSubscriptionRequestEventTypes string
+
+// DeleteAccountRequest defines model for DeleteAccountRequest.
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// DeleteAccountResponse defines model for DeleteAccountResponse.
+type DeleteAccountResponse struct {
+	RestorableUntil time.Time `json:"restorable_until"`
+}
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
+	// Code Machine readable error code, only set for errors the client is expected to handle
+	Code    *string `json:"code,omitempty"`
 	Details *[]struct {
 		Error string `json:"error"`
 	} `json:"details,omitempty"`
 	Message string `json:"message"`
+
+	// RequestId ID of the request, also sent in the X-Request-ID header. Only set for internal errors, whose details are logged under this ID instead of being returned
+	RequestId *string `json:"request_id,omitempty"`
 }
 
 // GetProfileResponse defines model for GetProfileResponse.
 type GetProfileResponse struct {
-	FullName    string `json:"full_name"`
-	PhoneNumber string `json:"phone_number"`
+	// AvatarUrls Square JPEG thumbnails of the avatar, absent when no avatar has been uploaded
+	AvatarUrls  *AvatarUrls          `json:"avatar_urls,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	DateOfBirth *openapi_types.Date  `json:"date_of_birth,omitempty"`
+	Email       *openapi_types.Email `json:"email,omitempty"`
+
+	// EmailVerifiedAt Absent until the current email has been verified. The service does not verify emails yet, so it is always absent for now.
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	FullName        string     `json:"full_name"`
+
+	// LastLoginAt Absent when the user has never logged in
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+
+	// Locale BCP 47 language tag
+	Locale      *string `json:"locale,omitempty"`
+	PhoneNumber string  `json:"phone_number"`
+
+	// Timezone IANA time zone name
+	Timezone  *string   `json:"timezone,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Version Incremented on every change of the user, also sent as the ETag header
+	Version int64 `json:"version"`
 }
 
+// HealthCheck defines model for HealthCheck.
+type HealthCheck struct {
+	DurationMs float64 `json:"duration_ms"`
+
+	// Error Either "check failed" or "check timed out", the cause is only logged
+	Error  *string           `json:"error,omitempty"`
+	Name   string            `json:"name"`
+	Status HealthCheckStatus `json:"status"`
+}
+
+// HealthCheckStatus defines model for HealthCheck.Status.
+type HealthCheckStatus string
+
+// HealthReport defines model for HealthReport.
+type HealthReport struct {
+	Checks *[]HealthCheck     `json:"checks,omitempty"`
+	Status HealthReportStatus `json:"status"`
+}
+
+// HealthReportStatus defines model for HealthReport.Status.
+type HealthReportStatus string
+
 // LoginRequest defines model for LoginRequest.
 type LoginRequest struct {
 	Password    string `json:"password"`
@@ -45,10 +244,50 @@ type LoginResponse struct {
 	UserId      openapi_types.UUID `json:"user_id"`
 }
 
+// PersonalDataExport defines model for PersonalDataExport.
+type PersonalDataExport struct {
+	ExportedAt time.Time `json:"exported_at"`
+
+	// Sections One entry per kind of stored data, keyed by section name
+	Sections map[string]interface{} `json:"sections"`
+	UserId   openapi_types.UUID     `json:"user_id"`
+}
+
+// ProfilePatch JSON Merge Patch of the profile, every property is optional
+type ProfilePatch struct {
+	DateOfBirth *openapi_types.Date `json:"date_of_birth"`
+
+	// Email Changing the email clears its verification. Emails are checked for format and uniqueness only, they are not verified yet.
+	Email *openapi_types.Email `json:"email"`
+
+	// FullName 3 to 60 characters, counted as user-perceived characters after Unicode NFC normalization. Surrounding
+	// whitespace is trimmed and inner whitespace collapsed to single spaces. Control and formatting
+	// characters are rejected.
+	FullName *string `json:"full_name,omitempty"`
+
+	// Locale BCP 47 language tag
+	Locale *string `json:"locale"`
+
+	// PhoneNumber Indonesian, Malaysian or Singaporean number. Numbers without an international prefix are read as
+	// Indonesian. Spaces, dashes, dots and parentheses are ignored and the number is stored in E.164,
+	// e.g. `0812-3456-7890` becomes `+6281234567890`.
+	PhoneNumber *string `json:"phone_number,omitempty"`
+
+	// Timezone IANA time zone name
+	Timezone *string `json:"timezone"`
+}
+
 // RegisterRequest defines model for RegisterRequest.
 type RegisterRequest struct {
-	FullName    string `json:"full_name"`
-	Password    string `json:"password"`
+	// FullName 3 to 60 characters, counted as user-perceived characters after Unicode NFC normalization. Surrounding
+	// whitespace is trimmed and inner whitespace collapsed to single spaces. Control and formatting
+	// characters are rejected.
+	FullName string `json:"full_name"`
+	Password string `json:"password"`
+
+	// PhoneNumber Indonesian, Malaysian or Singaporean number. Numbers without an international prefix are read as
+	// Indonesian. Spaces, dashes, dots and parentheses are ignored and the number is stored in E.164,
+	// e.g. `0812-3456-7890` becomes `+6281234567890`.
 	PhoneNumber string `json:"phone_number"`
 }
 
@@ -57,47 +296,311 @@ type RegisterResponse struct {
 	UserId openapi_types.UUID `json:"user_id"`
 }
 
+// RestoreAccountRequest defines model for RestoreAccountRequest.
+type RestoreAccountRequest struct {
+	Password    string `json:"password"`
+	PhoneNumber string `json:"phone_number"`
+}
+
 // UpdateProfileRequest defines model for UpdateProfileRequest.
 type UpdateProfileRequest struct {
-	FullName    string `json:"full_name"`
+	// FullName 3 to 60 characters, counted as user-perceived characters after Unicode NFC normalization. Surrounding
+	// whitespace is trimmed and inner whitespace collapsed to single spaces. Control and formatting
+	// characters are rejected.
+	FullName string `json:"full_name"`
+
+	// PhoneNumber Indonesian, Malaysian or Singaporean number. Numbers without an international prefix are read as
+	// Indonesian. Spaces, dashes, dots and parentheses are ignored and the number is stored in E.164,
+	// e.g. `0812-3456-7890` becomes `+6281234567890`.
 	PhoneNumber string `json:"phone_number"`
 }
 
+// UpdateUserStatusRequest defines model for UpdateUserStatusRequest.
+type UpdateUserStatusRequest struct {
+	Status UpdateUserStatusRequestStatus `json:"status"`
+}
+
+// UpdateUserStatusRequestStatus defines model for UpdateUserStatusRequest.Status.
+type UpdateUserStatusRequestStatus string
+
+// UpdateWebhookSubscriptionRequest defines model for UpdateWebhookSubscriptionRequest.
+type UpdateWebhookSubscriptionRequest struct {
+	Active     *bool                                         `json:"active,omitempty"`
+	EventTypes *[]UpdateWebhookSubscriptionRequestEventTypes `json:"event_types,omitempty"`
+	Secret     *string                                       `json:"secret,omitempty"`
+	Url        *string                                       `json:"url,omitempty"`
+}
+
+// UpdateWebhookSubscriptionRequestEventTypes defines model for UpdateWebhookSubscriptionRequest.EventTypes.
+type UpdateWebhookSubscriptionRequestEventTypes string
+
+// WebhookDelivery defines model for WebhookDelivery.
+type WebhookDelivery struct {
+	Attempts       int                   `json:"attempts"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+	EventId        openapi_types.UUID    `json:"event_id"`
+	EventType      string                `json:"event_type"`
+	Id             openapi_types.UUID    `json:"id"`
+	LastError      *string               `json:"last_error,omitempty"`
+	LastStatusCode *int                  `json:"last_status_code,omitempty"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	WebhookId      openapi_types.UUID    `json:"webhook_id"`
+}
+
+// WebhookDeliveryStatus defines model for WebhookDelivery.Status.
+type WebhookDeliveryStatus string
+
+// WebhookDeliveryList defines model for WebhookDeliveryList.
+type WebhookDeliveryList struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	NextBefore *time.Time        `json:"next_before,omitempty"`
+}
+
+// WebhookSubscription defines model for WebhookSubscription.
+type WebhookSubscription struct {
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// EventTypes Event types delivered to the subscription, every event type when empty
+	EventTypes []WebhookSubscriptionEventTypes `json:"event_types"`
+	Id         openapi_types.UUID              `json:"id"`
+
+	// Secret Signing secret, only returned when the subscription is created
+	Secret    *string   `json:"secret,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Url       string    `json:"url"`
+}
+
+// WebhookSubscriptionEventTypes defines model for WebhookSubscription.EventTypes.
+type WebhookSubscriptionEventTypes string
+
+// WebhookSubscriptionList defines model for WebhookSubscriptionList.
+type WebhookSubscriptionList struct {
+	Webhooks []WebhookSubscription `json:"webhooks"`
+}
+
+// GetApiV1AdminAuditEventsParams defines parameters for GetApiV1AdminAuditEvents.
+type GetApiV1AdminAuditEventsParams struct {
+	TargetUserId   *openapi_types.UUID `form:"target_user_id,omitempty" json:"target_user_id,omitempty"`
+	ActorId        *openapi_types.UUID `form:"actor_id,omitempty" json:"actor_id,omitempty"`
+	Action         *string             `form:"action,omitempty" json:"action,omitempty"`
+	BeforeSequence *int64              `form:"before_sequence,omitempty" json:"before_sequence,omitempty"`
+	Limit          *int                `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// XAdminKey Admin API key
+	XAdminKey string `json:"X-Admin-Key"`
+}
+
+// GetApiV1AdminAuditEventsVerificationParams defines parameters for GetApiV1AdminAuditEventsVerification.
+type GetApiV1AdminAuditEventsVerificationParams struct {
+	// XAdminKey Admin API key
+	XAdminKey string `json:"X-Admin-Key"`
+}
+
+// PutApiV1AdminUsersUserIdStatusParams defines parameters for PutApiV1AdminUsersUserIdStatus.
+type PutApiV1AdminUsersUserIdStatusParams struct {
+	// XAdminKey Admin API key
+	XAdminKey string `json:"X-Admin-Key"`
+}
+
+// GetApiV1AdminWebhooksParams defines parameters for GetApiV1AdminWebhooks.
+type GetApiV1AdminWebhooksParams struct {
+	// XAdminKey Admin API key
+	XAdminKey string `json:"X-Admin-Key"`
+}
+
+// PostApiV1AdminWebhooksParams defines parameters for PostApiV1AdminWebhooks.
+type PostApiV1AdminWebhooksParams struct {
+	// XAdminKey Admin API key
+	XAdminKey string `json:"X-Admin-Key"`
+}
+
+// DeleteApiV1AdminWebhooksWebhookIdParams defines parameters for DeleteApiV1AdminWebhooksWebhookId.
+type DeleteApiV1AdminWebhooksWebhookIdParams struct {
+	// XAdminKey Admin API key
+	XAdminKey string `json:"X-Admin-Key"`
+}
+
+// GetApiV1AdminWebhooksWebhookIdParams defines parameters for GetApiV1AdminWebhooksWebhookId.
+type GetApiV1AdminWebhooksWebhookIdParams struct {
+	// XAdminKey Admin API key
+	XAdminKey string `json:"X-Admin-Key"`
+}
+
+// PutApiV1AdminWebhooksWebhookIdParams defines parameters for PutApiV1AdminWebhooksWebhookId.
+type PutApiV1AdminWebhooksWebhookIdParams struct {
+	// XAdminKey Admin API key
+	XAdminKey string `json:"X-Admin-Key"`
+}
+
+// GetApiV1AdminWebhooksWebhookIdDeliveriesParams defines parameters for GetApiV1AdminWebhooksWebhookIdDeliveries.
+type GetApiV1AdminWebhooksWebhookIdDeliveriesParams struct {
+	Status *GetApiV1AdminWebhooksWebhookIdDeliveriesParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+	Before *time.Time                                            `form:"before,omitempty" json:"before,omitempty"`
+	Limit  *int                                                  `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// XAdminKey Admin API key
+	XAdminKey string `json:"X-Admin-Key"`
+}
+
+// GetApiV1AdminWebhooksWebhookIdDeliveriesParamsStatus defines parameters for GetApiV1AdminWebhooksWebhookIdDeliveries.
+type GetApiV1AdminWebhooksWebhookIdDeliveriesParamsStatus string
+
+// PostApiV1AdminWebhooksWebhookIdDeliveriesDeliveryIdRedeliveryParams defines parameters for PostApiV1AdminWebhooksWebhookIdDeliveriesDeliveryIdRedelivery.
+type PostApiV1AdminWebhooksWebhookIdDeliveriesDeliveryIdRedeliveryParams struct {
+	// XAdminKey Admin API key
+	XAdminKey string `json:"X-Admin-Key"`
+}
+
+// DeleteApiV1UsersMeParams defines parameters for DeleteApiV1UsersMe.
+type DeleteApiV1UsersMeParams struct {
+	// Authorization Bearer <access token>
+	Authorization string `json:"Authorization"`
+}
+
+// GetApiV1UsersMeExportParams defines parameters for GetApiV1UsersMeExport.
+type GetApiV1UsersMeExportParams struct {
+	Format *GetApiV1UsersMeExportParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Authorization Bearer <access token>
+	Authorization string `json:"Authorization"`
+}
+
+// GetApiV1UsersMeExportParamsFormat defines parameters for GetApiV1UsersMeExport.
+type GetApiV1UsersMeExportParamsFormat string
+
 // GetV1UsersProfileParams defines parameters for GetV1UsersProfile.
 type GetV1UsersProfileParams struct {
 	// Authorization Bearer <access token>
 	Authorization string `json:"Authorization"`
 }
 
+// PatchApiV1UsersProfileParams defines parameters for PatchApiV1UsersProfile.
+type PatchApiV1UsersProfileParams struct {
+	// Authorization Bearer <access token>
+	Authorization string `json:"Authorization"`
+
+	// IfMatch ETag returned by Get My Profile. The patch is rejected with 412 when the profile has changed since.
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
 // PutV1UsersProfileParams defines parameters for PutV1UsersProfile.
 type PutV1UsersProfileParams struct {
 	// Authorization Bearer <access token>
 	Authorization string `json:"Authorization"`
+
+	// IfMatch ETag returned by Get My Profile. The update is rejected with 412 when the profile has changed since.
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// PutApiV1UsersProfileAvatarMultipartBody defines parameters for PutApiV1UsersProfileAvatar.
+type PutApiV1UsersProfileAvatarMultipartBody struct {
+	Avatar openapi_types.File `json:"avatar"`
+}
+
+// PutApiV1UsersProfileAvatarParams defines parameters for PutApiV1UsersProfileAvatar.
+type PutApiV1UsersProfileAvatarParams struct {
+	// Authorization Bearer <access token>
+	Authorization string `json:"Authorization"`
 }
 
+// PutApiV1AdminUsersUserIdStatusJSONRequestBody defines body for PutApiV1AdminUsersUserIdStatus for application/json ContentType.
+type PutApiV1AdminUsersUserIdStatusJSONRequestBody = UpdateUserStatusRequest
+
+// PostApiV1AdminWebhooksJSONRequestBody defines body for PostApiV1AdminWebhooks for application/json ContentType.
+type PostApiV1AdminWebhooksJSONRequestBody = CreateWebhookSubscriptionRequest
+
+// PutApiV1AdminWebhooksWebhookIdJSONRequestBody defines body for PutApiV1AdminWebhooksWebhookId for application/json ContentType.
+type PutApiV1AdminWebhooksWebhookIdJSONRequestBody = UpdateWebhookSubscriptionRequest
+
 // PostApiV1UsersLoginJSONRequestBody defines body for PostApiV1UsersLogin for application/json ContentType.
 type PostApiV1UsersLoginJSONRequestBody = LoginRequest
 
+// DeleteApiV1UsersMeJSONRequestBody defines body for DeleteApiV1UsersMe for application/json ContentType.
+type DeleteApiV1UsersMeJSONRequestBody = DeleteAccountRequest
+
+// PatchApiV1UsersProfileApplicationMergePatchPlusJSONRequestBody defines body for PatchApiV1UsersProfile for application/merge-patch+json ContentType.
+type PatchApiV1UsersProfileApplicationMergePatchPlusJSONRequestBody = ProfilePatch
+
 // PutV1UsersProfileJSONRequestBody defines body for PutV1UsersProfile for application/json ContentType.
 type PutV1UsersProfileJSONRequestBody = UpdateProfileRequest
 
+// PutApiV1UsersProfileAvatarMultipartRequestBody defines body for PutApiV1UsersProfileAvatar for multipart/form-data ContentType.
+type PutApiV1UsersProfileAvatarMultipartRequestBody PutApiV1UsersProfileAvatarMultipartBody
+
 // PostApiV1UsersRegisterJSONRequestBody defines body for PostApiV1UsersRegister for application/json ContentType.
 type PostApiV1UsersRegisterJSONRequestBody = RegisterRequest
 
+// PostApiV1UsersRestoreJSONRequestBody defines body for PostApiV1UsersRestore for application/json ContentType.
+type PostApiV1UsersRestoreJSONRequestBody = RestoreAccountRequest
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// List Audit Events
+	// (GET /api/v1/admin/audit-events)
+	GetApiV1AdminAuditEvents(ctx echo.Context, params GetApiV1AdminAuditEventsParams) error
+	// Verify Audit Hash Chain
+	// (GET /api/v1/admin/audit-events/verification)
+	GetApiV1AdminAuditEventsVerification(ctx echo.Context, params GetApiV1AdminAuditEventsVerificationParams) error
+	// Change User Status
+	// (PUT /api/v1/admin/users/{user_id}/status)
+	PutApiV1AdminUsersUserIdStatus(ctx echo.Context, userId openapi_types.UUID, params PutApiV1AdminUsersUserIdStatusParams) error
+	// List Webhook Subscriptions
+	// (GET /api/v1/admin/webhooks)
+	GetApiV1AdminWebhooks(ctx echo.Context, params GetApiV1AdminWebhooksParams) error
+	// Create Webhook Subscription
+	// (POST /api/v1/admin/webhooks)
+	PostApiV1AdminWebhooks(ctx echo.Context, params PostApiV1AdminWebhooksParams) error
+	// Delete Webhook Subscription
+	// (DELETE /api/v1/admin/webhooks/{webhook_id})
+	DeleteApiV1AdminWebhooksWebhookId(ctx echo.Context, webhookId openapi_types.UUID, params DeleteApiV1AdminWebhooksWebhookIdParams) error
+	// Get Webhook Subscription
+	// (GET /api/v1/admin/webhooks/{webhook_id})
+	GetApiV1AdminWebhooksWebhookId(ctx echo.Context, webhookId openapi_types.UUID, params GetApiV1AdminWebhooksWebhookIdParams) error
+	// Update Webhook Subscription
+	// (PUT /api/v1/admin/webhooks/{webhook_id})
+	PutApiV1AdminWebhooksWebhookId(ctx echo.Context, webhookId openapi_types.UUID, params PutApiV1AdminWebhooksWebhookIdParams) error
+	// List Webhook Deliveries
+	// (GET /api/v1/admin/webhooks/{webhook_id}/deliveries)
+	GetApiV1AdminWebhooksWebhookIdDeliveries(ctx echo.Context, webhookId openapi_types.UUID, params GetApiV1AdminWebhooksWebhookIdDeliveriesParams) error
+	// Redeliver Webhook
+	// (POST /api/v1/admin/webhooks/{webhook_id}/deliveries/{delivery_id}/redelivery)
+	PostApiV1AdminWebhooksWebhookIdDeliveriesDeliveryIdRedelivery(ctx echo.Context, webhookId openapi_types.UUID, deliveryId openapi_types.UUID, params PostApiV1AdminWebhooksWebhookIdDeliveriesDeliveryIdRedeliveryParams) error
 	// User Login
 	// (POST /api/v1/users/login)
 	PostApiV1UsersLogin(ctx echo.Context) error
+	// Delete My Account
+	// (DELETE /api/v1/users/me)
+	DeleteApiV1UsersMe(ctx echo.Context, params DeleteApiV1UsersMeParams) error
+	// Export My Personal Data
+	// (GET /api/v1/users/me/export)
+	GetApiV1UsersMeExport(ctx echo.Context, params GetApiV1UsersMeExportParams) error
 	// Get My Profile
 	// (GET /api/v1/users/profile)
 	GetV1UsersProfile(ctx echo.Context, params GetV1UsersProfileParams) error
+	// Patch My Profile
+	// (PATCH /api/v1/users/profile)
+	PatchApiV1UsersProfile(ctx echo.Context, params PatchApiV1UsersProfileParams) error
 	// Update My Profile
 	// (PUT /api/v1/users/profile)
 	PutV1UsersProfile(ctx echo.Context, params PutV1UsersProfileParams) error
+	// Upload My Avatar
+	// (PUT /api/v1/users/profile/avatar)
+	PutApiV1UsersProfileAvatar(ctx echo.Context, params PutApiV1UsersProfileAvatarParams) error
 	// User Registration
 	// (POST /api/v1/users/register)
 	PostApiV1UsersRegister(ctx echo.Context) error
+	// Restore My Deleted Account
+	// (POST /api/v1/users/restore)
+	PostApiV1UsersRestore(ctx echo.Context) error
+	// Liveness Probe
+	// (GET /healthz)
+	GetHealthz(ctx echo.Context) error
+	// Readiness Probe
+	// (GET /readyz)
+	GetReadyz(ctx echo.Context) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -105,83 +608,684 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
-// PostApiV1UsersLogin converts echo context to params.
-func (w *ServerInterfaceWrapper) PostApiV1UsersLogin(ctx echo.Context) error {
+// GetApiV1AdminAuditEvents converts echo context to params.
+func (w *ServerInterfaceWrapper) GetApiV1AdminAuditEvents(ctx echo.Context) error {
 	var err error
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApiV1AdminAuditEventsParams
+	// ------------- Optional query parameter "target_user_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "target_user_id", ctx.QueryParams(), &params.TargetUserId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter target_user_id: %s", err))
+	}
+
+	// ------------- Optional query parameter "actor_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "actor_id", ctx.QueryParams(), &params.ActorId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter actor_id: %s", err))
+	}
+
+	// ------------- Optional query parameter "action" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "action", ctx.QueryParams(), &params.Action)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter action: %s", err))
+	}
+
+	// ------------- Optional query parameter "before_sequence" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "before_sequence", ctx.QueryParams(), &params.BeforeSequence)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter before_sequence: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "X-Admin-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Admin-Key")]; found {
+		var XAdminKey string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Admin-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Admin-Key", runtime.ParamLocationHeader, valueList[0], &XAdminKey)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Admin-Key: %s", err))
+		}
+
+		params.XAdminKey = XAdminKey
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter X-Admin-Key is required, but not found"))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PostApiV1UsersLogin(ctx)
+	err = w.Handler.GetApiV1AdminAuditEvents(ctx, params)
 	return err
 }
 
-// GetV1UsersProfile converts echo context to params.
-func (w *ServerInterfaceWrapper) GetV1UsersProfile(ctx echo.Context) error {
+// GetApiV1AdminAuditEventsVerification converts echo context to params.
+func (w *ServerInterfaceWrapper) GetApiV1AdminAuditEventsVerification(ctx echo.Context) error {
 	var err error
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetV1UsersProfileParams
+	var params GetApiV1AdminAuditEventsVerificationParams
 
 	headers := ctx.Request().Header
-	// ------------- Required header parameter "Authorization" -------------
-	if valueList, found := headers[http.CanonicalHeaderKey("Authorization")]; found {
-		var Authorization string
+	// ------------- Required header parameter "X-Admin-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Admin-Key")]; found {
+		var XAdminKey string
 		n := len(valueList)
 		if n != 1 {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Authorization, got %d", n))
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Admin-Key, got %d", n))
 		}
 
-		err = runtime.BindStyledParameterWithLocation("simple", false, "Authorization", runtime.ParamLocationHeader, valueList[0], &Authorization)
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Admin-Key", runtime.ParamLocationHeader, valueList[0], &XAdminKey)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Authorization: %s", err))
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Admin-Key: %s", err))
 		}
 
-		params.Authorization = Authorization
+		params.XAdminKey = XAdminKey
 	} else {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter Authorization is required, but not found"))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter X-Admin-Key is required, but not found"))
 	}
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetV1UsersProfile(ctx, params)
+	err = w.Handler.GetApiV1AdminAuditEventsVerification(ctx, params)
 	return err
 }
 
-// PutV1UsersProfile converts echo context to params.
-func (w *ServerInterfaceWrapper) PutV1UsersProfile(ctx echo.Context) error {
+// PutApiV1AdminUsersUserIdStatus converts echo context to params.
+func (w *ServerInterfaceWrapper) PutApiV1AdminUsersUserIdStatus(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "user_id" -------------
+	var userId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "user_id", runtime.ParamLocationPath, ctx.Param("user_id"), &userId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter user_id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutApiV1AdminUsersUserIdStatusParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "X-Admin-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Admin-Key")]; found {
+		var XAdminKey string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Admin-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Admin-Key", runtime.ParamLocationHeader, valueList[0], &XAdminKey)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Admin-Key: %s", err))
+		}
+
+		params.XAdminKey = XAdminKey
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter X-Admin-Key is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutApiV1AdminUsersUserIdStatus(ctx, userId, params)
+	return err
+}
+
+// GetApiV1AdminWebhooks converts echo context to params.
+func (w *ServerInterfaceWrapper) GetApiV1AdminWebhooks(ctx echo.Context) error {
 	var err error
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params PutV1UsersProfileParams
+	var params GetApiV1AdminWebhooksParams
 
 	headers := ctx.Request().Header
-	// ------------- Required header parameter "Authorization" -------------
-	if valueList, found := headers[http.CanonicalHeaderKey("Authorization")]; found {
-		var Authorization string
+	// ------------- Required header parameter "X-Admin-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Admin-Key")]; found {
+		var XAdminKey string
 		n := len(valueList)
 		if n != 1 {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Authorization, got %d", n))
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Admin-Key, got %d", n))
 		}
 
-		err = runtime.BindStyledParameterWithLocation("simple", false, "Authorization", runtime.ParamLocationHeader, valueList[0], &Authorization)
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Admin-Key", runtime.ParamLocationHeader, valueList[0], &XAdminKey)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Authorization: %s", err))
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Admin-Key: %s", err))
 		}
 
-		params.Authorization = Authorization
+		params.XAdminKey = XAdminKey
 	} else {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter Authorization is required, but not found"))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter X-Admin-Key is required, but not found"))
 	}
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PutV1UsersProfile(ctx, params)
+	err = w.Handler.GetApiV1AdminWebhooks(ctx, params)
 	return err
 }
 
-// PostApiV1UsersRegister converts echo context to params.
-func (w *ServerInterfaceWrapper) PostApiV1UsersRegister(ctx echo.Context) error {
+// PostApiV1AdminWebhooks converts echo context to params.
+func (w *ServerInterfaceWrapper) PostApiV1AdminWebhooks(ctx echo.Context) error {
 	var err error
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostApiV1AdminWebhooksParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "X-Admin-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Admin-Key")]; found {
+		var XAdminKey string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Admin-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Admin-Key", runtime.ParamLocationHeader, valueList[0], &XAdminKey)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Admin-Key: %s", err))
+		}
+
+		params.XAdminKey = XAdminKey
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter X-Admin-Key is required, but not found"))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.PostApiV1UsersRegister(ctx)
+	err = w.Handler.PostApiV1AdminWebhooks(ctx, params)
+	return err
+}
+
+// DeleteApiV1AdminWebhooksWebhookId converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteApiV1AdminWebhooksWebhookId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "webhook_id", runtime.ParamLocationPath, ctx.Param("webhook_id"), &webhookId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter webhook_id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteApiV1AdminWebhooksWebhookIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "X-Admin-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Admin-Key")]; found {
+		var XAdminKey string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Admin-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Admin-Key", runtime.ParamLocationHeader, valueList[0], &XAdminKey)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Admin-Key: %s", err))
+		}
+
+		params.XAdminKey = XAdminKey
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter X-Admin-Key is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteApiV1AdminWebhooksWebhookId(ctx, webhookId, params)
+	return err
+}
+
+// GetApiV1AdminWebhooksWebhookId converts echo context to params.
+func (w *ServerInterfaceWrapper) GetApiV1AdminWebhooksWebhookId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "webhook_id", runtime.ParamLocationPath, ctx.Param("webhook_id"), &webhookId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter webhook_id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApiV1AdminWebhooksWebhookIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "X-Admin-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Admin-Key")]; found {
+		var XAdminKey string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Admin-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Admin-Key", runtime.ParamLocationHeader, valueList[0], &XAdminKey)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Admin-Key: %s", err))
+		}
+
+		params.XAdminKey = XAdminKey
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter X-Admin-Key is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetApiV1AdminWebhooksWebhookId(ctx, webhookId, params)
+	return err
+}
+
+// PutApiV1AdminWebhooksWebhookId converts echo context to params.
+func (w *ServerInterfaceWrapper) PutApiV1AdminWebhooksWebhookId(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "webhook_id", runtime.ParamLocationPath, ctx.Param("webhook_id"), &webhookId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter webhook_id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutApiV1AdminWebhooksWebhookIdParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "X-Admin-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Admin-Key")]; found {
+		var XAdminKey string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Admin-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Admin-Key", runtime.ParamLocationHeader, valueList[0], &XAdminKey)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Admin-Key: %s", err))
+		}
+
+		params.XAdminKey = XAdminKey
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter X-Admin-Key is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutApiV1AdminWebhooksWebhookId(ctx, webhookId, params)
+	return err
+}
+
+// GetApiV1AdminWebhooksWebhookIdDeliveries converts echo context to params.
+func (w *ServerInterfaceWrapper) GetApiV1AdminWebhooksWebhookIdDeliveries(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "webhook_id", runtime.ParamLocationPath, ctx.Param("webhook_id"), &webhookId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter webhook_id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApiV1AdminWebhooksWebhookIdDeliveriesParams
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "before", ctx.QueryParams(), &params.Before)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter before: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "X-Admin-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Admin-Key")]; found {
+		var XAdminKey string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Admin-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Admin-Key", runtime.ParamLocationHeader, valueList[0], &XAdminKey)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Admin-Key: %s", err))
+		}
+
+		params.XAdminKey = XAdminKey
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter X-Admin-Key is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetApiV1AdminWebhooksWebhookIdDeliveries(ctx, webhookId, params)
+	return err
+}
+
+// PostApiV1AdminWebhooksWebhookIdDeliveriesDeliveryIdRedelivery converts echo context to params.
+func (w *ServerInterfaceWrapper) PostApiV1AdminWebhooksWebhookIdDeliveriesDeliveryIdRedelivery(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "webhook_id", runtime.ParamLocationPath, ctx.Param("webhook_id"), &webhookId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter webhook_id: %s", err))
+	}
+
+	// ------------- Path parameter "delivery_id" -------------
+	var deliveryId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "delivery_id", runtime.ParamLocationPath, ctx.Param("delivery_id"), &deliveryId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter delivery_id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostApiV1AdminWebhooksWebhookIdDeliveriesDeliveryIdRedeliveryParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "X-Admin-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-Admin-Key")]; found {
+		var XAdminKey string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for X-Admin-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "X-Admin-Key", runtime.ParamLocationHeader, valueList[0], &XAdminKey)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter X-Admin-Key: %s", err))
+		}
+
+		params.XAdminKey = XAdminKey
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter X-Admin-Key is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostApiV1AdminWebhooksWebhookIdDeliveriesDeliveryIdRedelivery(ctx, webhookId, deliveryId, params)
+	return err
+}
+
+// PostApiV1UsersLogin converts echo context to params.
+func (w *ServerInterfaceWrapper) PostApiV1UsersLogin(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostApiV1UsersLogin(ctx)
+	return err
+}
+
+// DeleteApiV1UsersMe converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteApiV1UsersMe(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteApiV1UsersMeParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "Authorization" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Authorization")]; found {
+		var Authorization string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Authorization, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Authorization", runtime.ParamLocationHeader, valueList[0], &Authorization)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Authorization: %s", err))
+		}
+
+		params.Authorization = Authorization
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter Authorization is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteApiV1UsersMe(ctx, params)
+	return err
+}
+
+// GetApiV1UsersMeExport converts echo context to params.
+func (w *ServerInterfaceWrapper) GetApiV1UsersMeExport(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApiV1UsersMeExportParams
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "Authorization" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Authorization")]; found {
+		var Authorization string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Authorization, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Authorization", runtime.ParamLocationHeader, valueList[0], &Authorization)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Authorization: %s", err))
+		}
+
+		params.Authorization = Authorization
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter Authorization is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetApiV1UsersMeExport(ctx, params)
+	return err
+}
+
+// GetV1UsersProfile converts echo context to params.
+func (w *ServerInterfaceWrapper) GetV1UsersProfile(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetV1UsersProfileParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "Authorization" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Authorization")]; found {
+		var Authorization string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Authorization, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Authorization", runtime.ParamLocationHeader, valueList[0], &Authorization)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Authorization: %s", err))
+		}
+
+		params.Authorization = Authorization
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter Authorization is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetV1UsersProfile(ctx, params)
+	return err
+}
+
+// PatchApiV1UsersProfile converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchApiV1UsersProfile(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchApiV1UsersProfileParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "Authorization" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Authorization")]; found {
+		var Authorization string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Authorization, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Authorization", runtime.ParamLocationHeader, valueList[0], &Authorization)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Authorization: %s", err))
+		}
+
+		params.Authorization = Authorization
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter Authorization is required, but not found"))
+	}
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PatchApiV1UsersProfile(ctx, params)
+	return err
+}
+
+// PutV1UsersProfile converts echo context to params.
+func (w *ServerInterfaceWrapper) PutV1UsersProfile(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutV1UsersProfileParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "Authorization" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Authorization")]; found {
+		var Authorization string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Authorization, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Authorization", runtime.ParamLocationHeader, valueList[0], &Authorization)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Authorization: %s", err))
+		}
+
+		params.Authorization = Authorization
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter Authorization is required, but not found"))
+	}
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutV1UsersProfile(ctx, params)
+	return err
+}
+
+// PutApiV1UsersProfileAvatar converts echo context to params.
+func (w *ServerInterfaceWrapper) PutApiV1UsersProfileAvatar(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutApiV1UsersProfileAvatarParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "Authorization" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Authorization")]; found {
+		var Authorization string
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Authorization, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Authorization", runtime.ParamLocationHeader, valueList[0], &Authorization)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Authorization: %s", err))
+		}
+
+		params.Authorization = Authorization
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter Authorization is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PutApiV1UsersProfileAvatar(ctx, params)
+	return err
+}
+
+// PostApiV1UsersRegister converts echo context to params.
+func (w *ServerInterfaceWrapper) PostApiV1UsersRegister(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostApiV1UsersRegister(ctx)
+	return err
+}
+
+// PostApiV1UsersRestore converts echo context to params.
+func (w *ServerInterfaceWrapper) PostApiV1UsersRestore(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostApiV1UsersRestore(ctx)
+	return err
+}
+
+// GetHealthz converts echo context to params.
+func (w *ServerInterfaceWrapper) GetHealthz(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetHealthz(ctx)
+	return err
+}
+
+// GetReadyz converts echo context to params.
+func (w *ServerInterfaceWrapper) GetReadyz(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetReadyz(ctx)
 	return err
 }
 
@@ -213,35 +1317,116 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/api/v1/admin/audit-events", wrapper.GetApiV1AdminAuditEvents)
+	router.GET(baseURL+"/api/v1/admin/audit-events/verification", wrapper.GetApiV1AdminAuditEventsVerification)
+	router.PUT(baseURL+"/api/v1/admin/users/:user_id/status", wrapper.PutApiV1AdminUsersUserIdStatus)
+	router.GET(baseURL+"/api/v1/admin/webhooks", wrapper.GetApiV1AdminWebhooks)
+	router.POST(baseURL+"/api/v1/admin/webhooks", wrapper.PostApiV1AdminWebhooks)
+	router.DELETE(baseURL+"/api/v1/admin/webhooks/:webhook_id", wrapper.DeleteApiV1AdminWebhooksWebhookId)
+	router.GET(baseURL+"/api/v1/admin/webhooks/:webhook_id", wrapper.GetApiV1AdminWebhooksWebhookId)
+	router.PUT(baseURL+"/api/v1/admin/webhooks/:webhook_id", wrapper.PutApiV1AdminWebhooksWebhookId)
+	router.GET(baseURL+"/api/v1/admin/webhooks/:webhook_id/deliveries", wrapper.GetApiV1AdminWebhooksWebhookIdDeliveries)
+	router.POST(baseURL+"/api/v1/admin/webhooks/:webhook_id/deliveries/:delivery_id/redelivery", wrapper.PostApiV1AdminWebhooksWebhookIdDeliveriesDeliveryIdRedelivery)
 	router.POST(baseURL+"/api/v1/users/login", wrapper.PostApiV1UsersLogin)
+	router.DELETE(baseURL+"/api/v1/users/me", wrapper.DeleteApiV1UsersMe)
+	router.GET(baseURL+"/api/v1/users/me/export", wrapper.GetApiV1UsersMeExport)
 	router.GET(baseURL+"/api/v1/users/profile", wrapper.GetV1UsersProfile)
+	router.PATCH(baseURL+"/api/v1/users/profile", wrapper.PatchApiV1UsersProfile)
 	router.PUT(baseURL+"/api/v1/users/profile", wrapper.PutV1UsersProfile)
+	router.PUT(baseURL+"/api/v1/users/profile/avatar", wrapper.PutApiV1UsersProfileAvatar)
 	router.POST(baseURL+"/api/v1/users/register", wrapper.PostApiV1UsersRegister)
+	router.POST(baseURL+"/api/v1/users/restore", wrapper.PostApiV1UsersRestore)
+	router.GET(baseURL+"/healthz", wrapper.GetHealthz)
+	router.GET(baseURL+"/readyz", wrapper.GetReadyz)
 
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xXbW/bthP/Ki7/+QNrK0cPlhVbwNClRRcEa7Yg6/ZicRbQ0tmiK5EsSTm2A3/3gZSU",
-	"SI6SOpubdMD8iqLveA+/3x2P1yhiGWcUqJIovEYySiDDZvleCCbOQHJGJegNLhgHoQiYv2NQmKRmSRRk",
-	"8q4E6AP0Qi05oBBJJQidovXaQgI+50RAjMLzUuzCqsTYeAaRQuubDSwEXurvDKTEU/jykZVg26FHoE4F",
-	"m5AU7o9tkqfpJcVZmykL8YRRuKR5NoYtwrs9a0NTO0dUqlVbfNr03EKLrlSMp2SaKJP0WKfmYDW88mbO",
-	"hFxNxsb0BzYl9Aw+5yDV3cA4lvKKiXgHcTWkrduTa2E1fNkuoOUYsCTONFCJ+lQP6D6ocBSBlJeKfQLa",
-	"GlUuQVwSE/GEiQwrFKI8J/GtQ/cEWClaTRt343sUYoPxsDcOwI1mfOEZm2cwJVKBuBe0BhszvPgAdKoS",
-	"FAaOhTJCq8+e1QJpDe+6pt/QDLSgUiAoCtGf3735fv/V+WH3j4uXZjkaxcXi/Ie9F/9/9WaUO44XXLzU",
-	"Iri7Go3ixv51YAX+eg9ZXyZYzSO31/DIdRoujUavA2//+sBynbaTH2ZmowBbWLqZ/+1w9D2a5BGZidWQ",
-	"DDZwvI+r/5iKrV4/in6z5crDCzGN5nNVNIzfeIwV3LSfr8DBZ0a9lrTWWLdLXG9IgkUyyfPVQabx1uYJ",
-	"nTAtkJIISsCLFKGT44/mAqvsShCdX0HMSaSBmoOQhOkY3X1n39GSjAPFnGhDZsskIjGptzEn9ty1NQmk",
-	"neqeY/BhBU4aJawIo8fazVMm1SEnv7vapDQNChV5AqnesnipVSJGFVCjjTlPSWT07Zlk9HYG0Ks9ARMU",
-	"ov/Zt0OCXU4IdqO5F/kQJReN257j7NpWyXRjKwYZCcJVkcdfftJJ9Hdosjn9tJh8i+POTfgW8r3h09n+",
-	"yFjnBNNl5YDUHvSfMvpjqqsUp4bVIDpGwdSkzLMMi2XF+oKC+o8mj3lRhNqRKbQQ+QhUSeKyXE1JCJyB",
-	"AiFReH69iQdgAaKjb6FeVNzYHXNjmx2trusGJYBj0yDKSj3MVcIEWRnLqN5SlMjBquVrs/1cfEXCtwyE",
-	"D7C+93S4/8jEmMQx0G+ScUegOifLTsUYffnkbU0y/1dwa/c9u/X+a+3dfvHEqyfhZ9Z5VzrxrKzznSfs",
-	"tO8YnaSkeDl+ew3WwNlg/J0+K8o5cduRoZorHz01wAJnPC34875Yd1z9McdpDhszZMX0+gsFZctT/eGI",
-	"+MXmWzlErwNv4Fa/ImHbZXpzvG+lu7uTAG/me4TdYOj33ag76Hv9rt/re93xIMJd58CJY98fYhf3/14Q",
-	"D1BVAFYQP/sg9F951uefArmi3ErEjUZxzeQi1feGUjy07ZRFOE2YVOHAGThofbH+KwAA///h4/B3nhMA",
-	"AA==",
+	"H4sIAAAAAAAC/+w9/XfbNpL/Cpa39952l5LlzyR+796em4/WbZP6kqa77+qcDBEjCQkJsABoW87z/35v",
+	"AJAiKVCSndhRG/eHRibxMRjMF2YGw49RIrNcChBGR4cfI51MIaP251HBuHk6pVz8CoqPeUINlwLf5Erm",
+	"oAwH226k5AcQQ2qGGn4vQCSATxnoRPHcdYne+DdEjomZAhlzpQ2BcxCGXEylBjKlekqYBE2ENCSjJpnG",
+	"RIp0RjRgGxC2Y4LwEO5andOUsyiOxlJl1ESHERfmYC+KIzPLwf0JE1DRdRwlU0g+ABvaKS3Ya3Ry4x9+",
+	"LF+NpEyBiuj6Oo4U/F5wBSw6/C0q4WhN8i6ODDcp9uzAZTWpHL2HxOCcZUsxgUVU07EBhT9EkaZ0hEMb",
+	"VUA1jDaKiwkOM4KxVLBWUwWMJgZYaNdqmB9zSBmZypRpkoPSUtCUMGpo7HfwnKYFaEIV2M1RkEjFgPWj",
+	"eBX+PLCxX18Ab4iNLmQ9R2wHcJWU5LqwYpoYqYacNcigKOwedjR2jz9GIIoMQS40KASYZRy3Uc+0gcxC",
+	"3u6fWOgdSIxxBIqmJw1Q/6pgHB1G/7E158Utz4hbdQxcB1CQKKAG2JCaxmoYNdAzPIPQkpDXgohZEyU8",
+	"H1LGFGgdHCVXcM5loYed8+DmgzZDzoKv63JkDTY1VE3ADHFL1tvUFvnZJtWcjR2PSzKa72Nj+Y2lNDaj",
+	"jQaP9TZtO+JdSto/cR0g77kg4way9cjITTanIqoUneHfAi7N0HHh8EbIb2GyQ/DNlxFa6Dk1VL1VqQ4I",
+	"oN8LFCc/nDz/jphpkY0E5akulQi1PWNCR9rpERBESP8YFQoZAQhS5KmkDFgUt1CYIt0sTrq/vUMuCf4/",
+	"55eQ6hAHZMB4kS323dk/IJcE/9/dV2c0TRe7HuyRS3Kw19mxhWo3SgVJ7FdTR/0csQG0P7Wk+i8YTaX8",
+	"8KYYVaC8dgTdQXGWLZpkVxeJfQUTrg0oi277JFdyzFMYFjnKpPnjqRQwFEU2AjV0vFW904aaQrefUiHF",
+	"LONXwIJytk3TGhIFdhkZFz+BmJhpdLh9EOhZqDQghlr4xkY15K5EXwDlzyAFA0dJIgthOtGcU60vpGKr",
+	"Qapa1uAKzrEaFp1LoQMGhwJtpELzYVgIw9N1dUwL0oVhuiH2kARAfq6UVN2gJpIFmPklTaZcAFFAGc5P",
+	"AEch2LhmXo6lci+0MzJTjgKFawKXOaBtRIwkUypYGtSnDAzKpQZXtHgHB1+9o67Zu8Di29SdgdbUSa8V",
+	"2rWJj+NnpfT0rWJCUy2JlaDcWXr/7nnC6R0/I1OgDFSf/FzHFRcGFNp/DmmlBejxYE3AVE4mwEghGChi",
+	"plyT42eEC22AMgRhBFxMiAJTKAGrdXS53hBuvgNz4oRMN3U4pTAsVLpaVc7l5i3tK3w5lOPhiCszXegW",
+	"6gEZbTGXe9LVdHhujxEVXM1NPnIK0bKaI+hCKXxi+841YzlIn/wyBaJBnfME5ocw+3rmOmkyAxMTLQm3",
+	"jEHTCzrTpepFmhDyoh/FzaV2YmhcpOlQ0CxMwCnVZpjKCRfLlledTFA72EUJOAdVUh4Xa0OTyoSmAeHx",
+	"7dMTsveIpFRMCjoBYugkiiO4pFmOzSPOesfPQgPWlVtwhQjJlRSBOY+PXh0RfE3wPbE4qs95pDnd+oF+",
+	"oMrQ0NRezd6IXs9BaX9WagEjEgUZCJSAUuB5Xc2IU8ulGEHk12UIdSL0+S904mXHOgf0FrfPyaOFyzms",
+	"LWO7tuyabglIhrb8iKPLnjYyT/lkalGGUjOij66eXOy8H4z5xXhkwfseaGqmT/GEvyhdWKHsgX6YNV0L",
+	"TBajusrwq0AuLhVCE+HPuZmCIqfOlUDGlKfATiMi589wExmRhTmNYsfctNCAPGnVmaP+0C53spszt+qm",
+	"nPyAm0Z5ii3erZLNfqP8MHEDG7XNqGMwIMXd69eQSxWwiuza1z/u1OcK2YbLVxxHTFEu1lq8H2phnX4h",
+	"gYX+hILtNubfSrnStg+bnBMyFxuwrMcasxFQzQeTAzM1H+ycfpBO1ZskoPXQoJcwuKpbH9zLjnFzjsX1",
+	"3Yj3H4+e7I4OYDt5n1/u2DlPvLvrGTX0+WWYQME+v6Hg1WC9C8ucQ9dxS0T8LICAMGpGclDkAxfWnNJG",
+	"KmDeH/cBZsDIaEb8+KUWWSDFz4D5+rprC6rtQQB7AVC8nD5Bx283OsY01dDGyA9vfn5FXoKaALHdS9Xk",
+	"T56x11t+v2ZWVOZu7AWfwErTbaU7tTLlmkBaFx6avAiZbUOSFKjShBtNzmte4T55nlVmtHcpWwvLgUKo",
+	"QLOao5cGtJP6VhHMKtdradehzdawyUqjMqOX5Yl4Z39vjUU17LXmwnbxbHQwQLNA0cQAHgfsWQ4YGgNI",
+	"Kb0cVAL8HFitFbHOXvJWcDyMkVcvnhKBcKb8yqPhTaGULATjYnIqLqbcgM5pYlWdUTxDNYi44EKAIrX3",
+	"iUxTmmt3atNcTFIg9o3uk6dSGCVT29Ghxdjh63ApIAre23Nf/1REcd2BsHsH5uNK7LfFfttIY1KA5lTE",
+	"5CVN6Qx/osXwhosJzaUCKojr3Cev7L+aXHAzlQUSU3mYcwxBcgVjfumRQHELT8V8hj55YxEZE0b11P4r",
+	"jbbYzKkCYaagfQyAT4QVSfgOad5BgJvnZRUX5Hl/+2AvPhXQn/TJ2eDx9k5vd2//oPfo8ZPBGRlBIjPQ",
+	"5OwfBzuPt3fwjX3hNmWOx+brJnnv7sR3aH2v2LnrmhCsi7eA+Hvt/WaddsEDBy7hwLrRVNv8g71GzwNs",
+	"aJDYo8Po//72z//q//23o97/vvvG/jw9Ze7Hb//917/859//eVoMBjsH777BJrR3dXrKGs8/HsQHe9d/",
+	"jR7Y9Y7ZdblR2zgpBgzcNl+tZwLu7YhpkfD36uoJf2xBmI/TZeZ+si0VhPpGluv72dUOvVST5PzcjDzY",
+	"dvc+wed8B4eOMFABofjWHuqrE/yDZLyFZHyQRV9AFtWIPUjD67Hz7hN+cDkdF8XVo8xJITfYWw3qjXU7",
+	"dPLEooMjB0usPqJ9bh02hcan1luUSjxmRHi0SsEEQ2wrHR9d0HVy9k2ijx7qQDJO/BVGJpsov1kY0rd+",
+	"BinHc3EA1cZAlpt6ckc9k+o2ARE31w17uW1dMydlTgOfktliIw9dwTr/uqQBH29cxJDNpvBIvNGCl3Ft",
+	"hUL7m7JhCsZRc4jkLtwe3z4dpjZAbSMaaK55fCuKWVx8g2BqwqJNhasJNZwH4/HCYX3ncHvq5Qkxtwx5",
+	"1+DqXnVXTkyAoW8mEW/DpC0p2gpM4EtiX5KKFNGGQf2sa3CW3jaoOrhAHdID7vKmC+c1BcVchrcylvgE",
+	"4wbEvfcpBmWUex6yrGMM7Rq/X58roLdWRotdGLZsbn3NQFgjwhai1PUIOszOXu7cmJkbACxsa2vl1STL",
+	"FxLmThyMi7FEwFKegD8MugNI9PL4l6iunzUo8sYF1mvBy8Nouz/oD7ClzEHQnKO5Zx9ZH8XULnuL5nzr",
+	"fHvLJpluUcyk683T/iYh8nttKU0T29jxoCYCLkAbl3PdJydUa3IWyvg7Q8c5JTn6KqkmZwuvjSQTMM4k",
+	"h0tjW6J/GTfQHgKOmQu6HuX81+0jBHue/6ft0hTNwIDS0eFvC2F9bE+OTo4xfhEhkqPDqAohe/z+u2eb",
+	"9X60Teab6vxvjiiCpP/Rjfd74ZSNH66VOlofYaXSDI9Y5RZ/nrEcP914Xa2tCwPTHYwPD5ryjJvGUAzG",
+	"tEhNdLg/sEcnnqFM3x4M7LnU/xWY4B1unXNtWFreGQwim8IljM/lpnme+njI1nvt1N983vUyXS3/Wn5t",
+	"RdB+RMbb+4xzNlPTAlN+SxkpTXI79+79zf1CqhFnDKxc3L/PVR+XaWIoAUER28FKY11kGcWzR4SbROye",
+	"ES8m8H236Ns6b11G6ZCDCG9hwGWj2Msl7uqIHHsDpSYiraNB2YC9XrieYqbUtC6mrC/zWrc9vpz8u3OO",
+	"W7zcsoTzHqjfU/+vLsXO0f/3SKUWjwEeQBWltz56TXW9NT8rtojKkg3aEHOimau3boJZpaLexVFeWJQ1",
+	"Kf+kqFE+mjsa/3fM3pRnwy9M81bmfivZ7LNtfJeb6/ra2ZkNLttblE2vJHnq4fhq1dDeYO/+Zn4lDXmB",
+	"zno385P7m/mpFOOUJ2YjZY+7VEbcEcVx66LYqZ/HJhDg/4bm+1fZ+s+q6rpOiQ+6bk1LzyOQ1DFo8+xz",
+	"qQN2nLtmowlteEx8vnrD1VIlwFbOFnubgWtSEkNMKFFUMJkRKWykbgICSbl0zIjyMT8HsWjjnUi9kaT+",
+	"+TXcystNQVW3fZdcFpSu3mn2cJjbGJVidyTI5Et0y9bHebTh2vv0wQTC+e6qmF70oBo5AZs4j7Fpm1Dp",
+	"fdQ2H36Rlf2dswVm9v8esw1TYGtYkV+fJbdx1O+oqoP64xuYT5tKhoP7lvA///hA3BtC3N+B6aTs1R6I",
+	"Rjj5jp0QG8hFd+WEuKmJ9sUY+MHH8VULD0etn2QXbjUTPZZGP9E+nDd3Uc2GvbgiIhoOhN4q/rkgjJ7N",
+	"l7GB8dAqo2fe89NSkZYHKMNxyaVJNn/k0GQo++dBaD4IzTX8ZTWxca8G142F89ZH/3tmXyhg9TzT+4I7",
+	"Dg5eg+yTzdCgu/J/CiigoX9mhE4oF84xQclYgZ4SBXh9d1SwCRisnUKNLVzBja4qdThBvK4TMqBd/K/Z",
+	"MXs934LNOk7u3JVgDXHcUZJAXnkLH8TbFxdvFVmWMq5hCbpwt60BYwWHZ7cOZrBxZ3vXP7qb41ajTsId",
+	"H62aNQs20z7Yuccg6i9SkpdUzEoA9GYecTQo4khwkY7Lu2mr3drUXYrzF9AU9BIpxlxlZeWA8j5dnxyl",
+	"KXFlL4gte1FeDjuXWC/AXkHj1EA6q65RlWMnVJARNvW3qVydqLN2kbaz2ENxMeWJ86o36r7aMlBVcvVS",
+	"J7vlz5ewSv98C1Rh9ZtiMNhN6muzT6BLIx0VZiqVv5q3Cc6ZYBG+oNTYuas5u4m5qQgfwmabEzh4OSN+",
+	"/4IiZAuq0jNLvR82vdFMbWjcMTgdycLMS6VRjKczeSGwLiryO7EVVJhMigyEiavqKOmMXPE8B9bt5PCc",
+	"XdV12Sz+7vAWePs+6C6I7P7HldvD/3nF85B34y69BYG6OUgW9QERqsZ41cllxAWt36qqIH7IU1nFjQ7X",
+	"yI3lDhDcggBP+jtKy3KjPIf4+7+bqQDviH4DxffC5Bf7BVkIsHBgIA/IH4v9BZqFCk8aBCPcVh88Ox73",
+	"XmKW9pnL6rH3lirzqdqHbpxcP7BDO/CHvOAxZ71PvkZXy7BAOG2u1kJBrr+9fvGUPNp9fPBNeWXQb0Sf",
+	"vOCQsnkhUSUz9972s4VkYWxIIfytvvhUjF0PDQYHO8MaPGe2pa2n5ZK5FNS+WUDTVF5owo2vYuvSRyy0",
+	"jPjRsL/9jAOtKiu0zpoI0FzlbSpDxwsXN7EQZ5UTN5qR5n66VDqHbZsr52pLOH/V3vbO/Mqi3zFbaNVv",
+	"BtFcJNDvWkXJiNFnMcEzpKeehfQfN1Sk9dpLd3x4/5xC79egsPOHsopJ1pBlX2cg4Qvlee9t79zfxCcK",
+	"EilcpUTywlaLdTDs3x8MbwUKU1sEkrxEpwP5BalwE3WZ00YtbVYEzlLP8bZ6XTc0tVAfs+ZJezRUR65B",
+	"WdnHDyCV003ItFnAp1+YP6VWcTfGN1yt3CbtplXN57YXfx5E4xcQjZuaG1MXSl0HzS33FQMEKyi1XkOe",
+	"0qT0JNu2juOM5Ub3GRz7NZ2YnLz6DuXSd8cvCM+oq+dODcmkNmSfvOTfOh5OQBgFRLsP8ThL5FS4HlwT",
+	"ndB0Xgek+kQP0fwKNEm5Nu4ixlnt+wtnQfO6MAvGtfsQwx/LaZwVqeE5VWYLXTE99JM36Sn0WYr1PDfN",
+	"Gha+57tgfYrmYv409q4j4QeDNyzVt+9x5hM6w60gGJv7yX546sHqXCLhLa7Qve+YdlHAl/WP6rHu9rcY",
+	"BSNUkLNjBlkuDYhkhjkYZ/4rGyiDXV6JpmNIZ31y5P+uNICmGWBmhw0IjiSbYTajUxZS8QnHZZSCwvWa",
+	"T2Z6VrnMgJ1Zz0vtSz4l7Ohjs8kuS7JWrHgvq5veOFbv6zY6EeJ+E3vrzH56slULtJQJ9erAUTbDZM+L",
+	"gWJ/aX9axBeDLP9zm70elbSLzN76etzqBVZVZiO6ffBkb3876T3e39nv7e3u7/RGjxPaGzwaMLa394Ru",
+	"0/3bLWKJMbcpN+6+lAG7c48GLJpfLWa3pySaKqBs5j9uqP3HAShhfDwG6yVXc1RtZp6EozVFFxLAS2lo",
+	"A5frJv74esJ3lPoTrlb8UOzhD3De3MCcN0tLaAq4mD9rRvyn9tNBV0tC/GWNImpKG9keeLgmqhCCi0mf",
+	"HNfKFblPRmHyFANb61cks2Aw/3s/8x0eFRrfReoIRLcSoM/dJ05OlBz5Y7EVfd0Isl98KhNw/YJ5eWPY",
+	"f99OADBty2/jRpXCUvetk0BjtlMKjQ56WhhtEyaCqHvtQPrCmENq3723GX1JQ/JW0HPK3Tcw2qROGW/s",
+	"Hr62rOFO8bZGZTQ1Jj/c2rKfUJlKbQ4fDx4Pout31/8/AH6n+9uZfgAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/SawitProRecruitment/UserService/generated"
+	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) PutApiV1AdminUsersUserIdStatus(ctx echo.Context, userId openapi_types.UUID, params generated.PutApiV1AdminUsersUserIdStatusParams) error {
+	if errResponse := s.authorizeAdmin(params.XAdminKey); errResponse != nil {
+		return ctx.JSON(http.StatusForbidden, errResponse)
+	}
+
+	var request generated.UpdateUserStatusRequest
+	if err := json.NewDecoder(ctx.Request().Body).Decode(&request); err != nil {
+		response := generated.ErrorResponse{
+			Message: err.Error(),
+		}
+		return ctx.JSON(http.StatusBadRequest, response)
+	}
+
+	if err := s.accountService.ChangeStatus(ctx.Request().Context(), userId, request); err != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (s *Server) GetApiV1AdminAuditEvents(ctx echo.Context, params generated.GetApiV1AdminAuditEventsParams) error {
+	if errResponse := s.authorizeAdmin(params.XAdminKey); errResponse != nil {
+		return ctx.JSON(http.StatusForbidden, errResponse)
+	}
+
+	result, err := s.auditService.ListEvents(ctx.Request().Context(), params)
+	if err != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (s *Server) GetApiV1AdminAuditEventsVerification(ctx echo.Context, params generated.GetApiV1AdminAuditEventsVerificationParams) error {
+	if errResponse := s.authorizeAdmin(params.XAdminKey); errResponse != nil {
+		return ctx.JSON(http.StatusForbidden, errResponse)
+	}
+
+	result, err := s.auditService.VerifyChain(ctx.Request().Context())
+	if err != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
@@ -3,10 +3,14 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
 	"net/http"
 
 	"github.com/SawitProRecruitment/UserService/common"
 	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/SawitProRecruitment/UserService/service"
 	"github.com/labstack/echo/v4"
 )
 
@@ -21,7 +25,7 @@ func (s *Server) PostApiV1UsersRegister(ctx echo.Context) error {
 
 	result, err := s.authService.Register(ctx.Request().Context(), request)
 	if err != nil {
-		return ctx.JSON(constructErrorResponse(err))
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
 	}
 
 	return ctx.JSON(http.StatusCreated, result)
@@ -38,7 +42,7 @@ func (s *Server) PostApiV1UsersLogin(ctx echo.Context) error {
 
 	result, err := s.authService.Login(ctx.Request().Context(), request)
 	if err != nil {
-		return ctx.JSON(constructErrorResponse(err))
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
 	}
 
 	return ctx.JSON(http.StatusOK, result)
@@ -47,22 +51,24 @@ func (s *Server) PostApiV1UsersLogin(ctx echo.Context) error {
 func (s *Server) GetV1UsersProfile(ctx echo.Context, params generated.GetV1UsersProfileParams) error {
 	accessToken, errResponse := extractAccessToken(params.Authorization)
 	if errResponse != nil {
-		ctx.JSON(http.StatusForbidden, errResponse)
+		return ctx.JSON(http.StatusForbidden, errResponse)
 	}
 
 	appCtx := context.WithValue(ctx.Request().Context(), common.KeyAccessToken, accessToken)
 
 	result, err := s.profileService.GetProfile(appCtx)
 	if err != nil {
-		return ctx.JSON(constructErrorResponse(err))
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
 	}
+
+	ctx.Response().Header().Set("ETag", formatETag(result.Version))
 	return ctx.JSON(http.StatusOK, result)
 }
 
 func (s *Server) PutV1UsersProfile(ctx echo.Context, params generated.PutV1UsersProfileParams) error {
 	accessToken, errResponse := extractAccessToken(params.Authorization)
 	if errResponse != nil {
-		ctx.JSON(http.StatusForbidden, errResponse)
+		return ctx.JSON(http.StatusForbidden, errResponse)
 	}
 
 	var request generated.UpdateProfileRequest
@@ -73,11 +79,131 @@ func (s *Server) PutV1UsersProfile(ctx echo.Context, params generated.PutV1Users
 		return ctx.JSON(http.StatusBadRequest, response)
 	}
 
-	appCtx := context.WithValue(ctx.Request().Context(), common.KeyAccessToken, accessToken)
+	version, errResponse := parseIfMatch(params.IfMatch)
+	if errResponse != nil {
+		return ctx.JSON(http.StatusPreconditionFailed, errResponse)
+	}
 
-	err := s.profileService.UpdateProfile(appCtx, request)
+	appCtx := context.WithValue(ctx.Request().Context(), common.KeyAccessToken, accessToken)
+	err := s.profileService.UpdateProfile(appCtx, request, version)
 	if err != nil {
-		return ctx.JSON(constructErrorResponse(err))
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
 	}
 	return ctx.JSON(http.StatusOK, nil)
 }
+
+func (s *Server) PatchApiV1UsersProfile(ctx echo.Context, params generated.PatchApiV1UsersProfileParams) error {
+	accessToken, errResponse := extractAccessToken(params.Authorization)
+	if errResponse != nil {
+		return ctx.JSON(http.StatusForbidden, errResponse)
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType)); err != nil || mediaType != mergePatchContentType {
+		response := generated.ErrorResponse{
+			Message: "content type must be " + mergePatchContentType,
+		}
+		return ctx.JSON(http.StatusUnsupportedMediaType, response)
+	}
+
+	var document map[string]json.RawMessage
+	if err := json.NewDecoder(ctx.Request().Body).Decode(&document); err != nil || document == nil {
+		response := generated.ErrorResponse{
+			Message: "merge patch must be a JSON object",
+		}
+		return ctx.JSON(http.StatusBadRequest, response)
+	}
+
+	version, errResponse := parseIfMatch(params.IfMatch)
+	if errResponse != nil {
+		return ctx.JSON(http.StatusPreconditionFailed, errResponse)
+	}
+
+	appCtx := context.WithValue(ctx.Request().Context(), common.KeyAccessToken, accessToken)
+	result, err := s.profileService.PatchProfile(appCtx, document, version)
+	if err != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
+	}
+
+	ctx.Response().Header().Set("ETag", formatETag(result.Version))
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (s *Server) PutApiV1UsersProfileAvatar(ctx echo.Context, params generated.PutApiV1UsersProfileAvatarParams) error {
+	accessToken, errResponse := extractAccessToken(params.Authorization)
+	if errResponse != nil {
+		return ctx.JSON(http.StatusForbidden, errResponse)
+	}
+
+	// The service checks the exact file size, this only stops oversized requests before they are buffered.
+	ctx.Request().Body = http.MaxBytesReader(ctx.Response(), ctx.Request().Body, maxAvatarRequestSize)
+
+	file, err := ctx.FormFile("avatar")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response := generated.ErrorResponse{
+				Message: fmt.Sprintf("avatar must be at most %d MiB", service.MaxAvatarSize>>20),
+			}
+			return ctx.JSON(http.StatusRequestEntityTooLarge, response)
+		}
+
+		response := generated.ErrorResponse{
+			Message: "avatar file is required",
+		}
+		return ctx.JSON(http.StatusBadRequest, response)
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), common.WrapError(common.ErrUnexpectedError, err, "error reading avatar file")))
+	}
+	defer content.Close()
+
+	appCtx := context.WithValue(ctx.Request().Context(), common.KeyAccessToken, accessToken)
+
+	result, errService := s.profileService.UploadAvatar(appCtx, content)
+	if errService != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), errService))
+	}
+
+	ctx.Response().Header().Set("ETag", formatETag(result.Version))
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (s *Server) DeleteApiV1UsersMe(ctx echo.Context, params generated.DeleteApiV1UsersMeParams) error {
+	accessToken, errResponse := extractAccessToken(params.Authorization)
+	if errResponse != nil {
+		return ctx.JSON(http.StatusForbidden, errResponse)
+	}
+
+	var request generated.DeleteAccountRequest
+	if err := json.NewDecoder(ctx.Request().Body).Decode(&request); err != nil {
+		response := generated.ErrorResponse{
+			Message: err.Error(),
+		}
+		return ctx.JSON(http.StatusBadRequest, response)
+	}
+
+	appCtx := context.WithValue(ctx.Request().Context(), common.KeyAccessToken, accessToken)
+
+	result, err := s.accountService.DeleteAccount(appCtx, request)
+	if err != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
+	}
+	return ctx.JSON(http.StatusAccepted, result)
+}
+
+func (s *Server) PostApiV1UsersRestore(ctx echo.Context) error {
+	var request generated.RestoreAccountRequest
+	if err := json.NewDecoder(ctx.Request().Body).Decode(&request); err != nil {
+		response := generated.ErrorResponse{
+			Message: err.Error(),
+		}
+		return ctx.JSON(http.StatusBadRequest, response)
+	}
+
+	if err := s.accountService.RestoreAccount(ctx.Request().Context(), request); err != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
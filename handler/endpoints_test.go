@@ -1,36 +1,56 @@
 package handler_test
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/SawitProRecruitment/UserService/common"
 	"github.com/SawitProRecruitment/UserService/generated"
 	"github.com/SawitProRecruitment/UserService/handler"
+	"github.com/SawitProRecruitment/UserService/health"
+	"github.com/SawitProRecruitment/UserService/logging"
+	"github.com/SawitProRecruitment/UserService/metrics"
+	"github.com/SawitProRecruitment/UserService/model"
 	"github.com/SawitProRecruitment/UserService/service"
+	"github.com/SawitProRecruitment/UserService/tracing"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/suite"
 )
 
 type HTTPHandlerTestSuite struct {
 	suite.Suite
-	ctrl           *gomock.Controller
-	authService    *service.MockAuthService
-	profileService *service.MockProfileService
-	sut            *handler.Server
+	ctrl               *gomock.Controller
+	authService        *service.MockAuthService
+	profileService     *service.MockProfileService
+	exportService      *service.MockExportService
+	idempotencyService *service.MockIdempotencyService
+	sut                *handler.Server
 }
 
 func (s *HTTPHandlerTestSuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.authService = service.NewMockAuthService(s.ctrl)
 	s.profileService = service.NewMockProfileService(s.ctrl)
+	s.exportService = service.NewMockExportService(s.ctrl)
+	s.idempotencyService = service.NewMockIdempotencyService(s.ctrl)
 	s.sut = handler.NewServer(handler.NewServerOptions{
 		AuthService:    s.authService,
 		ProfileService: s.profileService,
+		ExportService:  s.exportService,
 	})
 }
 
@@ -55,6 +75,23 @@ func (s *HTTPHandlerTestSuite) TestPutV1UsersProfileGivenInvalidAuthorizationPar
 	s.sut.PutV1UsersProfile(ctx, params)
 
 	s.Equal(http.StatusForbidden, w.Result().StatusCode)
+	s.Equal(1, strings.Count(w.Body.String(), "\n"))
+}
+
+func (s *HTTPHandlerTestSuite) TestGetV1UsersProfileGivenInvalidAuthorizationParamShouldReturnForbidden() {
+	e := echo.New()
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/users/profile", nil)
+	w := httptest.NewRecorder()
+	ctx := e.NewContext(r, w)
+
+	params := generated.GetV1UsersProfileParams{
+		Authorization: "auth",
+	}
+
+	s.sut.GetV1UsersProfile(ctx, params)
+
+	s.Equal(http.StatusForbidden, w.Result().StatusCode)
+	s.Equal(1, strings.Count(w.Body.String(), "\n"))
 }
 
 func (s *HTTPHandlerTestSuite) TestPutV1UsersProfileGivenInvalidRequestShouldReturnBadRequest() {
@@ -98,7 +135,7 @@ func (s *HTTPHandlerTestSuite) TestPutV1UsersProfileOnInvalidInputErrorShouldRet
 		PhoneNumber: "+62888888888",
 	}
 
-	s.profileService.EXPECT().UpdateProfile(gomock.Eq(expectedAppCtx), gomock.Eq(expectedRequest)).Return(getProfileErr)
+	s.profileService.EXPECT().UpdateProfile(gomock.Eq(expectedAppCtx), gomock.Eq(expectedRequest), gomock.Eq(int64(0))).Return(getProfileErr)
 
 	s.sut.PutV1UsersProfile(ctx, params)
 
@@ -131,7 +168,7 @@ func (s *HTTPHandlerTestSuite) TestPutV1UsersProfileOnUnauthorizedErrorShouldRet
 		PhoneNumber: "+62888888888",
 	}
 
-	s.profileService.EXPECT().UpdateProfile(gomock.Eq(expectedAppCtx), gomock.Eq(expectedRequest)).Return(getProfileErr)
+	s.profileService.EXPECT().UpdateProfile(gomock.Eq(expectedAppCtx), gomock.Eq(expectedRequest), gomock.Eq(int64(0))).Return(getProfileErr)
 
 	s.sut.PutV1UsersProfile(ctx, params)
 
@@ -164,7 +201,7 @@ func (s *HTTPHandlerTestSuite) TestPutV1UsersProfileOnEntityAlreadyExistsErrorSh
 		PhoneNumber: "+62888888888",
 	}
 
-	s.profileService.EXPECT().UpdateProfile(gomock.Eq(expectedAppCtx), gomock.Eq(expectedRequest)).Return(getProfileErr)
+	s.profileService.EXPECT().UpdateProfile(gomock.Eq(expectedAppCtx), gomock.Eq(expectedRequest), gomock.Eq(int64(0))).Return(getProfileErr)
 
 	s.sut.PutV1UsersProfile(ctx, params)
 
@@ -197,7 +234,7 @@ func (s *HTTPHandlerTestSuite) TestPutV1UsersProfileOnUnexpectedErrorShouldRetur
 		PhoneNumber: "+62888888888",
 	}
 
-	s.profileService.EXPECT().UpdateProfile(gomock.Eq(expectedAppCtx), gomock.Eq(expectedRequest)).Return(getProfileErr)
+	s.profileService.EXPECT().UpdateProfile(gomock.Eq(expectedAppCtx), gomock.Eq(expectedRequest), gomock.Eq(int64(0))).Return(getProfileErr)
 
 	s.sut.PutV1UsersProfile(ctx, params)
 
@@ -228,9 +265,551 @@ func (s *HTTPHandlerTestSuite) TestPutV1UsersProfileWhenUpdateSuccessShouldRetur
 		PhoneNumber: "+62888888888",
 	}
 
-	s.profileService.EXPECT().UpdateProfile(gomock.Eq(expectedAppCtx), gomock.Eq(expectedRequest)).Return(nil)
+	s.profileService.EXPECT().UpdateProfile(gomock.Eq(expectedAppCtx), gomock.Eq(expectedRequest), gomock.Eq(int64(0))).Return(nil)
+
+	s.sut.PutV1UsersProfile(ctx, params)
+
+	s.Equal(http.StatusOK, w.Result().StatusCode)
+}
+
+func (s *HTTPHandlerTestSuite) TestPutV1UsersProfileGivenIfMatchShouldPassExpectedVersion() {
+	request := `{"full_name": "Jasuke"}`
+
+	e := echo.New()
+	r := httptest.NewRequest(http.MethodPut, "/api/v1/users/profile", bytes.NewReader([]byte(request)))
+	w := httptest.NewRecorder()
+	ctx := e.NewContext(r, w)
+
+	accessToken := "token"
+	ifMatch := `"7"`
+	params := generated.PutV1UsersProfileParams{
+		Authorization: "Bearer " + accessToken,
+		IfMatch:       &ifMatch,
+	}
+
+	expectedAppCtx := context.WithValue(r.Context(), common.KeyAccessToken, accessToken)
+	precondition := common.NewCustomError(common.ErrPreconditionFailed, "profile has been changed by another request")
+
+	s.profileService.EXPECT().UpdateProfile(gomock.Eq(expectedAppCtx), gomock.Eq(generated.UpdateProfileRequest{FullName: "Jasuke"}), gomock.Eq(int64(7))).Return(precondition)
+
+	s.sut.PutV1UsersProfile(ctx, params)
+
+	s.Equal(http.StatusPreconditionFailed, w.Result().StatusCode)
+}
+
+func (s *HTTPHandlerTestSuite) TestPutV1UsersProfileGivenWeakIfMatchShouldReturnPreconditionFailed() {
+	request := `{"full_name": "Jasuke"}`
+
+	e := echo.New()
+	r := httptest.NewRequest(http.MethodPut, "/api/v1/users/profile", bytes.NewReader([]byte(request)))
+	w := httptest.NewRecorder()
+	ctx := e.NewContext(r, w)
+
+	ifMatch := `W/"7"`
+	params := generated.PutV1UsersProfileParams{
+		Authorization: "Bearer token",
+		IfMatch:       &ifMatch,
+	}
 
 	s.sut.PutV1UsersProfile(ctx, params)
 
+	s.Equal(http.StatusPreconditionFailed, w.Result().StatusCode)
+}
+
+func (s *HTTPHandlerTestSuite) TestGetV1UsersProfileShouldReturnVersionAsETag() {
+	e := echo.New()
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/users/profile", nil)
+	w := httptest.NewRecorder()
+	ctx := e.NewContext(r, w)
+
+	s.profileService.EXPECT().GetProfile(gomock.Any()).Return(generated.GetProfileResponse{FullName: "Jasuke", PhoneNumber: "+62888888888", Version: 3}, nil)
+
+	s.sut.GetV1UsersProfile(ctx, generated.GetV1UsersProfileParams{Authorization: "Bearer token"})
+
 	s.Equal(http.StatusOK, w.Result().StatusCode)
+	s.Equal(`"3"`, w.Header().Get("ETag"))
+}
+
+func (s *HTTPHandlerTestSuite) TestGetApiV1UsersMeExportGivenZipFormatShouldReturnZippedDocument() {
+	e := echo.New()
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/users/me/export?format=zip", nil)
+	w := httptest.NewRecorder()
+	ctx := e.NewContext(r, w)
+
+	accessToken := "token"
+	format := generated.Zip
+	params := generated.GetApiV1UsersMeExportParams{
+		Authorization: "Bearer " + accessToken,
+		Format:        &format,
+	}
+
+	export := generated.PersonalDataExport{
+		UserId:     uuid.New(),
+		ExportedAt: time.Now(),
+		Sections:   map[string]interface{}{"profile": map[string]interface{}{"full_name": "Jasuke"}},
+	}
+
+	expectedAppCtx := context.WithValue(r.Context(), common.KeyAccessToken, accessToken)
+	s.exportService.EXPECT().Export(gomock.Eq(expectedAppCtx)).Return(export, nil)
+
+	s.sut.GetApiV1UsersMeExport(ctx, params)
+
+	s.Equal(http.StatusOK, w.Result().StatusCode)
+	s.Equal("application/zip", w.Result().Header.Get(echo.HeaderContentType))
+
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	s.Require().NoError(err)
+	s.Require().Len(archive.File, 1)
+
+	file, err := archive.File[0].Open()
+	s.Require().NoError(err)
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	s.Require().NoError(err)
+
+	var document map[string]interface{}
+	s.Require().NoError(json.Unmarshal(content, &document))
+	s.Equal(export.UserId.String(), document["user_id"])
+}
+
+func (s *HTTPHandlerTestSuite) TestPatchApiV1UsersProfileGivenPlainJSONShouldReturnUnsupportedMediaType() {
+	e := echo.New()
+	r := httptest.NewRequest(http.MethodPatch, "/api/v1/users/profile", bytes.NewReader([]byte(`{"full_name": "Jasuke"}`)))
+	r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	w := httptest.NewRecorder()
+	ctx := e.NewContext(r, w)
+
+	s.sut.PatchApiV1UsersProfile(ctx, generated.PatchApiV1UsersProfileParams{Authorization: "Bearer token"})
+
+	s.Equal(http.StatusUnsupportedMediaType, w.Result().StatusCode)
+}
+
+func (s *HTTPHandlerTestSuite) TestPatchApiV1UsersProfileShouldPassDocumentAndReturnETag() {
+	e := echo.New()
+	r := httptest.NewRequest(http.MethodPatch, "/api/v1/users/profile", bytes.NewReader([]byte(`{"full_name": "Jasuke", "phone_number": null}`)))
+	r.Header.Set(echo.HeaderContentType, "application/merge-patch+json; charset=utf-8")
+	w := httptest.NewRecorder()
+	ctx := e.NewContext(r, w)
+
+	expectedAppCtx := context.WithValue(r.Context(), common.KeyAccessToken, "token")
+	expectedDocument := map[string]json.RawMessage{
+		"full_name":    json.RawMessage(`"Jasuke"`),
+		"phone_number": json.RawMessage(`null`),
+	}
+
+	s.profileService.EXPECT().PatchProfile(gomock.Eq(expectedAppCtx), gomock.Eq(expectedDocument), gomock.Eq(int64(0))).Return(generated.GetProfileResponse{FullName: "Jasuke", Version: 8}, nil)
+
+	s.sut.PatchApiV1UsersProfile(ctx, generated.PatchApiV1UsersProfileParams{Authorization: "Bearer token"})
+
+	s.Equal(http.StatusOK, w.Result().StatusCode)
+	s.Equal(`"8"`, w.Header().Get("ETag"))
+}
+
+func (s *HTTPHandlerTestSuite) TestPutApiV1UsersProfileAvatarGivenMissingFileShouldReturnBadRequest() {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	s.Require().NoError(writer.WriteField("name", "avatar.png"))
+	s.Require().NoError(writer.Close())
+
+	e := echo.New()
+	r := httptest.NewRequest(http.MethodPut, "/api/v1/users/profile/avatar", &body)
+	r.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	ctx := e.NewContext(r, w)
+
+	s.sut.PutApiV1UsersProfileAvatar(ctx, generated.PutApiV1UsersProfileAvatarParams{Authorization: "Bearer token"})
+
+	s.Equal(http.StatusBadRequest, w.Result().StatusCode)
+}
+
+func (s *HTTPHandlerTestSuite) TestPutApiV1UsersProfileAvatarShouldPassFileContentAndReturnETag() {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("avatar", "avatar.png")
+	s.Require().NoError(err)
+	_, err = part.Write([]byte("image content"))
+	s.Require().NoError(err)
+	s.Require().NoError(writer.Close())
+
+	e := echo.New()
+	r := httptest.NewRequest(http.MethodPut, "/api/v1/users/profile/avatar", &body)
+	r.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	ctx := e.NewContext(r, w)
+
+	expectedAppCtx := context.WithValue(r.Context(), common.KeyAccessToken, "token")
+
+	s.profileService.EXPECT().UploadAvatar(gomock.Eq(expectedAppCtx), gomock.Any()).DoAndReturn(
+		func(_ context.Context, content io.Reader) (generated.GetProfileResponse, *common.CustomError) {
+			data, err := io.ReadAll(content)
+			s.Require().NoError(err)
+			s.Equal("image content", string(data))
+			return generated.GetProfileResponse{Version: 4}, nil
+		})
+
+	s.sut.PutApiV1UsersProfileAvatar(ctx, generated.PutApiV1UsersProfileAvatarParams{Authorization: "Bearer token"})
+
+	s.Equal(http.StatusOK, w.Result().StatusCode)
+	s.Equal(`"4"`, w.Header().Get("ETag"))
+}
+
+func (s *HTTPHandlerTestSuite) TestGetReadyzGivenFailingCheckShouldReturnServiceUnavailable() {
+	healthChecker := health.NewChecker(health.CheckerOptions{Timeout: time.Second})
+	healthChecker.AddCheck("database", func(ctx context.Context) error {
+		return errors.New("dial tcp 10.0.0.1:5432: connection refused")
+	})
+	healthChecker.AddCheck("migrations", func(ctx context.Context) error { return nil })
+	sut := handler.NewServer(handler.NewServerOptions{HealthChecker: healthChecker})
+
+	var logs bytes.Buffer
+	logger := logging.NewLogger(logging.LoggerOptions{Output: &logs, Level: logging.LevelInfo})
+
+	e := echo.New()
+	r := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	r = r.WithContext(logging.WithLogger(r.Context(), logger))
+	w := httptest.NewRecorder()
+
+	sut.GetReadyz(e.NewContext(r, w))
+
+	var response generated.HealthReport
+	s.Require().NoError(json.NewDecoder(w.Body).Decode(&response))
+	s.Equal(http.StatusServiceUnavailable, w.Result().StatusCode)
+	s.Equal(generated.HealthReportStatusFailing, response.Status)
+	s.Require().Len(*response.Checks, 2)
+	s.Equal(generated.HealthCheckStatusFailing, (*response.Checks)[0].Status)
+	s.Equal("check failed", *(*response.Checks)[0].Error)
+	s.Equal(generated.HealthCheckStatusOk, (*response.Checks)[1].Status)
+	s.NotContains(w.Body.String(), "10.0.0.1")
+	s.Contains(logs.String(), "dial tcp 10.0.0.1:5432: connection refused")
+}
+
+func (s *HTTPHandlerTestSuite) TestGetReadyzWhileDrainingShouldReturnServiceUnavailable() {
+	healthChecker := health.NewChecker(health.CheckerOptions{Timeout: time.Second})
+	healthChecker.SetDraining()
+	sut := handler.NewServer(handler.NewServerOptions{HealthChecker: healthChecker})
+
+	e := echo.New()
+	r := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	w := httptest.NewRecorder()
+
+	sut.GetReadyz(e.NewContext(r, w))
+
+	s.Equal(http.StatusServiceUnavailable, w.Result().StatusCode)
+	s.Contains(w.Body.String(), `"status":"draining"`)
+}
+
+func (s *HTTPHandlerTestSuite) TestMetricsMiddlewareShouldCountRequestsByRouteTemplate() {
+	registry := metrics.NewRegistry()
+	e := echo.New()
+	e.Use(handler.MetricsMiddleware(registry))
+	e.GET("/metrics-test/:id", func(ctx echo.Context) error { return ctx.NoContent(http.StatusNoContent) })
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics-test/1", nil))
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics-test/2", nil))
+
+	var buf bytes.Buffer
+	s.Require().NoError(registry.WriteText(&buf))
+	s.Contains(buf.String(), `http_requests_total{method="GET",route="/metrics-test/:id",code="204"} 2`+"\n")
+	s.Contains(buf.String(), `http_request_duration_seconds_count{method="GET",route="/metrics-test/:id"} 2`+"\n")
+}
+
+type recordingSpanExporter struct {
+	spans []tracing.SpanData
+}
+
+func (e *recordingSpanExporter) Export(ctx context.Context, spans []tracing.SpanData) error {
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (s *HTTPHandlerTestSuite) TestTracingMiddlewareShouldContinueIncomingTrace() {
+	exporter := &recordingSpanExporter{}
+	tracer := tracing.NewTracer(tracing.TracerOptions{Exporter: exporter, QueueSize: 10, BatchSize: 10, Interval: time.Hour})
+	tracing.SetDefault(tracer)
+	defer tracing.SetDefault(nil)
+
+	var handlerSpan tracing.SpanContext
+	e := echo.New()
+	e.Use(handler.TracingMiddleware())
+	e.GET("/tracing-test/:id", func(ctx echo.Context) error {
+		handlerSpan = tracing.SpanFromContext(ctx.Request().Context()).SpanContext()
+		return ctx.NoContent(http.StatusNoContent)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/tracing-test/1", nil)
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	e.ServeHTTP(httptest.NewRecorder(), r)
+
+	stopCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tracer.Run(stopCtx)
+
+	s.Require().Len(exporter.spans, 1)
+	span := exporter.spans[0]
+	s.Equal("GET /tracing-test/:id", span.Name)
+	s.Equal(handlerSpan, span.SpanContext)
+	s.Equal("4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext.TraceID.String())
+	s.Equal("00f067aa0ba902b7", span.ParentSpanID.String())
+	s.Contains(span.Attributes, tracing.Attribute{Key: "http.status_code", Value: int64(http.StatusNoContent)})
+}
+
+func (s *HTTPHandlerTestSuite) TestRequestMetadataMiddlewareShouldPropagateOrGenerateRequestID() {
+	var requestIDs []string
+	e := echo.New()
+	e.Use(handler.RequestMetadataMiddleware(logging.Default()))
+	e.GET("/request-id-test", func(ctx echo.Context) error {
+		requestIDs = append(requestIDs, ctx.Request().Context().Value(common.KeyRequestID).(string))
+		return ctx.NoContent(http.StatusNoContent)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/request-id-test", nil)
+	r.Header.Set(echo.HeaderXRequestID, "req-123")
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, r)
+	s.Equal("req-123", w.Header().Get(echo.HeaderXRequestID))
+
+	r = httptest.NewRequest(http.MethodGet, "/request-id-test", nil)
+	r.Header.Set(echo.HeaderXRequestID, "not a valid\nid")
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, r)
+	_, err := uuid.Parse(w.Header().Get(echo.HeaderXRequestID))
+	s.NoError(err)
+
+	s.Equal([]string{"req-123", w.Header().Get(echo.HeaderXRequestID)}, requestIDs)
+}
+
+func (s *HTTPHandlerTestSuite) TestUnexpectedErrorShouldReturnRequestIDAndLogTheCause() {
+	var logs bytes.Buffer
+	logger := logging.NewLogger(logging.LoggerOptions{Output: &logs, Level: logging.LevelInfo})
+
+	e := echo.New()
+	e.Use(handler.RequestMetadataMiddleware(logger))
+	e.GET("/v1/users/profile", func(ctx echo.Context) error {
+		return s.sut.GetV1UsersProfile(ctx, generated.GetV1UsersProfileParams{Authorization: "Bearer token"})
+	})
+
+	dbErr := common.NewCustomError(common.ErrUnexpectedError, `pq: duplicate key value (phone_number)=(+628123456789)`)
+	s.profileService.EXPECT().GetProfile(gomock.Any()).Return(generated.GetProfileResponse{}, dbErr)
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/users/profile", nil)
+	r.Header.Set(echo.HeaderXRequestID, "req-500")
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, r)
+
+	s.Equal(http.StatusInternalServerError, w.Code)
+	var response generated.ErrorResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Equal("internal server error", response.Message)
+	s.Require().NotNil(response.RequestId)
+	s.Equal("req-500", *response.RequestId)
+
+	s.Contains(logs.String(), `"request_id":"req-500"`)
+	s.Contains(logs.String(), `pq: duplicate key value (phone_number)=(+**********89)`)
+	s.NotContains(logs.String(), "+628123456789")
+}
+
+func (s *HTTPHandlerTestSuite) TestGetV1UsersProfileOnUnavailableErrorShouldReturnServiceUnavailable() {
+	e := echo.New()
+	r := httptest.NewRequest(http.MethodGet, "/v1/users/profile", nil)
+	w := httptest.NewRecorder()
+	ctx := e.NewContext(r, w)
+
+	cause := errors.New("dial tcp 10.0.0.1:5432: connection refused")
+	s.profileService.EXPECT().GetProfile(gomock.Any()).Return(generated.GetProfileResponse{}, common.WrapError(common.ErrUnavailable, cause, "database is unavailable, please retry later"))
+
+	s.sut.GetV1UsersProfile(ctx, generated.GetV1UsersProfileParams{Authorization: "Bearer token"})
+
+	s.Equal(http.StatusServiceUnavailable, w.Code)
+	s.Contains(w.Body.String(), `"code":"unavailable"`)
+	s.NotContains(w.Body.String(), "10.0.0.1")
+}
+
+func (s *HTTPHandlerTestSuite) TestRequestTimeoutMiddlewareShouldAnswerGatewayTimeoutWhenDeadlinePasses() {
+	e := echo.New()
+	e.Use(handler.RequestTimeoutMiddleware(10 * time.Millisecond))
+	e.GET("/v1/users/profile", func(ctx echo.Context) error {
+		return s.sut.GetV1UsersProfile(ctx, generated.GetV1UsersProfileParams{Authorization: "Bearer token"})
+	})
+
+	s.profileService.EXPECT().GetProfile(gomock.Any()).DoAndReturn(func(ctx context.Context) (generated.GetProfileResponse, *common.CustomError) {
+		<-ctx.Done()
+		return generated.GetProfileResponse{}, common.WrapError(common.ErrTimeout, ctx.Err(), "database did not answer in time, please retry later")
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users/profile", nil))
+
+	s.Equal(http.StatusGatewayTimeout, w.Code)
+	s.Contains(w.Body.String(), `"code":"timeout"`)
+}
+
+// A query cancelled along with the request fails like a statement timeout, which must not be reported as one.
+func (s *HTTPHandlerTestSuite) TestGetV1UsersProfileGivenClientGoneShouldNotLogErrorOrAnswerGatewayTimeout() {
+	var logs bytes.Buffer
+	logger := logging.NewLogger(logging.LoggerOptions{Output: &logs, Level: logging.LevelInfo})
+
+	e := echo.New()
+	e.Use(handler.RequestMetadataMiddleware(logger))
+	e.GET("/v1/users/profile", func(ctx echo.Context) error {
+		return s.sut.GetV1UsersProfile(ctx, generated.GetV1UsersProfileParams{Authorization: "Bearer token"})
+	})
+
+	reqCtx, cancel := context.WithCancel(context.Background())
+	s.profileService.EXPECT().GetProfile(gomock.Any()).DoAndReturn(func(ctx context.Context) (generated.GetProfileResponse, *common.CustomError) {
+		cancel()
+		cause := &pq.Error{Code: "57014", Message: "canceling statement due to user request"}
+		return generated.GetProfileResponse{}, common.WrapError(common.ErrTimeout, cause, "database did not answer in time, please retry later")
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users/profile", nil).WithContext(reqCtx))
+
+	s.NotEqual(http.StatusGatewayTimeout, w.Code)
+	s.NotContains(w.Body.String(), `"code":"timeout"`)
+	s.Contains(logs.String(), "request cancelled by the client")
+	s.NotContains(logs.String(), `"level":"error"`)
+}
+
+func (s *HTTPHandlerTestSuite) newIdempotentRegisterRequest(body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/users/register", bytes.NewBufferString(body))
+	r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	r.Header.Set(handler.HeaderIdempotencyKey, "5f1c2a9e-register")
+	return r
+}
+
+func (s *HTTPHandlerTestSuite) newIdempotentServer() *echo.Echo {
+	e := echo.New()
+	e.Use(handler.IdempotencyMiddleware(s.idempotencyService, []byte("idempotency fingerprint key of 32 bytes")))
+	e.POST("/api/v1/users/register", s.sut.PostApiV1UsersRegister)
+	return e
+}
+
+func (s *HTTPHandlerTestSuite) TestIdempotencyMiddlewareShouldStoreResponseOfFirstRequest() {
+	e := s.newIdempotentServer()
+	userID := uuid.New()
+
+	var claimed model.IdempotencyRecord
+	s.idempotencyService.EXPECT().Begin(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, record model.IdempotencyRecord) (*model.IdempotencyRecord, *common.CustomError) {
+			claimed = record
+			return nil, nil
+		})
+	s.authService.EXPECT().Register(gomock.Any(), gomock.Any()).Return(generated.RegisterResponse{UserId: userID}, nil)
+	s.idempotencyService.EXPECT().Complete(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, record model.IdempotencyRecord) *common.CustomError {
+			s.Equal("5f1c2a9e-register", record.Key)
+			s.Equal(claimed.Fingerprint, record.Fingerprint)
+			s.Equal(http.StatusCreated, record.StatusCode)
+			s.Equal(echo.MIMEApplicationJSONCharsetUTF8, record.ContentType)
+			s.Contains(string(record.Body), userID.String())
+			return nil
+		})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, s.newIdempotentRegisterRequest(`{"phone_number":"+628111111111","full_name":"Jasuke","password":"myPassw0rd!"}`))
+
+	s.Equal(http.StatusCreated, w.Code)
+	s.Contains(w.Body.String(), userID.String())
+	s.Empty(w.Header().Get(handler.HeaderIdempotentReplayed))
+}
+
+func (s *HTTPHandlerTestSuite) TestIdempotencyMiddlewareGivenRetryShouldReplayStoredResponse() {
+	e := s.newIdempotentServer()
+	body := `{"user_id":"a169451c-8525-4352-b8ca-070dd449a1a5"}`
+
+	s.idempotencyService.EXPECT().Begin(gomock.Any(), gomock.Any()).Return(&model.IdempotencyRecord{
+		StatusCode:  http.StatusCreated,
+		ContentType: echo.MIMEApplicationJSONCharsetUTF8,
+		Body:        []byte(body),
+	}, nil)
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, s.newIdempotentRegisterRequest(`{"phone_number":"+628111111111","full_name":"Jasuke","password":"myPassw0rd!"}`))
+
+	s.Equal(http.StatusCreated, w.Code)
+	s.Equal(body, w.Body.String())
+	s.Equal(echo.MIMEApplicationJSONCharsetUTF8, w.Header().Get(echo.HeaderContentType))
+	s.Equal("true", w.Header().Get(handler.HeaderIdempotentReplayed))
+}
+
+func (s *HTTPHandlerTestSuite) TestIdempotencyMiddlewareGivenKeyReusedWithDifferentPayloadShouldReturnUnprocessableEntity() {
+	e := s.newIdempotentServer()
+
+	var fingerprints [][]byte
+	s.idempotencyService.EXPECT().Begin(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, record model.IdempotencyRecord) (*model.IdempotencyRecord, *common.CustomError) {
+			fingerprints = append(fingerprints, record.Fingerprint)
+			return nil, common.NewCustomError(common.ErrIdempotencyKeyReused, "Idempotency-Key has already been used for a different request")
+		}).Times(2)
+
+	for _, phoneNumber := range []string{"+628111111111", "+628222222222"} {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, s.newIdempotentRegisterRequest(`{"phone_number":"`+phoneNumber+`","full_name":"Jasuke","password":"myPassw0rd!"}`))
+
+		s.Equal(http.StatusUnprocessableEntity, w.Code)
+		s.Contains(w.Body.String(), `"code":"idempotency_key_reused"`)
+	}
+	s.NotEqual(fingerprints[0], fingerprints[1])
+}
+
+func (s *HTTPHandlerTestSuite) TestIdempotencyMiddlewareGivenServerErrorShouldReleaseKey() {
+	e := s.newIdempotentServer()
+
+	s.idempotencyService.EXPECT().Begin(gomock.Any(), gomock.Any()).Return(nil, nil)
+	s.authService.EXPECT().Register(gomock.Any(), gomock.Any()).
+		Return(generated.RegisterResponse{}, common.NewCustomError(common.ErrUnavailable, "database is unavailable, please retry later"))
+	s.idempotencyService.EXPECT().Abandon(gomock.Any(), gomock.Any()).Return(nil)
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, s.newIdempotentRegisterRequest(`{"phone_number":"+628111111111","full_name":"Jasuke","password":"myPassw0rd!"}`))
+
+	s.Equal(http.StatusServiceUnavailable, w.Code)
+}
+
+func (s *HTTPHandlerTestSuite) TestIdempotencyMiddlewareGivenNoKeyShouldPassThrough() {
+	e := s.newIdempotentServer()
+	r := s.newIdempotentRegisterRequest(`{"phone_number":"+628111111111","full_name":"Jasuke","password":"myPassw0rd!"}`)
+	r.Header.Del(handler.HeaderIdempotencyKey)
+
+	s.authService.EXPECT().Register(gomock.Any(), gomock.Any()).Return(generated.RegisterResponse{UserId: uuid.New()}, nil)
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, r)
+
+	s.Equal(http.StatusCreated, w.Code)
+}
+
+func (s *HTTPHandlerTestSuite) TestIdempotencyMiddlewareGivenWebhookSubscriptionShouldNotStoreResponse() {
+	e := s.newIdempotentServer()
+	e.POST("/api/v1/admin/webhooks", func(ctx echo.Context) error {
+		return ctx.JSON(http.StatusCreated, map[string]string{"secret": "signing secret"})
+	})
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/admin/webhooks", bytes.NewBufferString(`{"url":"https://example.com"}`))
+	r.Header.Set(handler.HeaderIdempotencyKey, "5f1c2a9e-webhook")
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, r)
+
+	s.Equal(http.StatusCreated, w.Code)
+	s.Empty(w.Header().Get(handler.HeaderIdempotentReplayed))
+}
+
+func (s *HTTPHandlerTestSuite) TestIdempotencyMiddlewareShouldNotStorePlainHashOfRequest() {
+	e := s.newIdempotentServer()
+	body := `{"phone_number":"+628111111111","full_name":"Jasuke","password":"myPassw0rd!"}`
+
+	var fingerprint []byte
+	s.idempotencyService.EXPECT().Begin(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, record model.IdempotencyRecord) (*model.IdempotencyRecord, *common.CustomError) {
+			fingerprint = record.Fingerprint
+			return nil, common.NewCustomError(common.ErrIdempotencyKeyReused, "Idempotency-Key has already been used for a different request")
+		})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, s.newIdempotentRegisterRequest(body))
+
+	plain := sha256.Sum256([]byte("POST /api/v1/users/register\n" + body))
+	s.Require().NotEmpty(fingerprint)
+	s.NotEqual(plain[:], fingerprint)
 }
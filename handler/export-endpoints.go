@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) GetApiV1UsersMeExport(ctx echo.Context, params generated.GetApiV1UsersMeExportParams) error {
+	accessToken, errResponse := extractAccessToken(params.Authorization)
+	if errResponse != nil {
+		return ctx.JSON(http.StatusForbidden, errResponse)
+	}
+
+	appCtx := context.WithValue(ctx.Request().Context(), common.KeyAccessToken, accessToken)
+
+	result, err := s.exportService.Export(appCtx)
+	if err != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
+	}
+
+	document, errMarshal := json.MarshalIndent(result, "", "  ")
+	if errMarshal != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), common.WrapError(common.ErrUnexpectedError, errMarshal, "error encoding export")))
+	}
+
+	fileName := fmt.Sprintf("personal-data-%s", result.ExportedAt.UTC().Format("20060102T150405Z"))
+
+	if params.Format == nil || *params.Format == generated.Json {
+		ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName+".json"))
+		return ctx.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, document)
+	}
+
+	archive, errZip := zipFile(fileName+".json", document)
+	if errZip != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), common.WrapError(common.ErrUnexpectedError, errZip, "error zipping export")))
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName+".zip"))
+	return ctx.Blob(http.StatusOK, "application/zip", archive)
+}
+
+func zipFile(name string, content []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	writer := zip.NewWriter(&buf)
+	file, err := writer.Create(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := file.Write(content); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
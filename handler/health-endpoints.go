@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/SawitProRecruitment/UserService/logging"
+	"github.com/labstack/echo/v4"
+)
+
+// Failing checks are reported with a generic message, their errors may name hosts, users or files and
+// only go to the logs.
+const (
+	healthCheckFailedMessage   = "check failed"
+	healthCheckTimedOutMessage = "check timed out"
+)
+
+func (s *Server) GetHealthz(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, generated.HealthReport{Status: generated.HealthReportStatusOk})
+}
+
+func (s *Server) GetReadyz(ctx echo.Context) error {
+	report := s.healthChecker.CheckReadiness(ctx.Request().Context())
+
+	checks := make([]generated.HealthCheck, 0, len(report.Results))
+	for _, result := range report.Results {
+		check := generated.HealthCheck{
+			Name:       result.Name,
+			Status:     generated.HealthCheckStatusOk,
+			DurationMs: float64(result.Duration) / float64(time.Millisecond),
+		}
+		if result.Err != nil {
+			logging.FromContext(ctx.Request().Context()).Warn("readiness check failed", "check", result.Name, "error", result.Err)
+
+			message := healthCheckFailedMessage
+			if errors.Is(result.Err, context.DeadlineExceeded) {
+				message = healthCheckTimedOutMessage
+			}
+			check.Status = generated.HealthCheckStatusFailing
+			check.Error = &message
+		}
+		checks = append(checks, check)
+	}
+
+	response := generated.HealthReport{Status: generated.HealthReportStatusOk, Checks: &checks}
+	switch {
+	case report.Draining:
+		response.Status = generated.HealthReportStatusDraining
+	case !report.Ready():
+		response.Status = generated.HealthReportStatusFailing
+	}
+
+	if response.Status != generated.HealthReportStatusOk {
+		return ctx.JSON(http.StatusServiceUnavailable, response)
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -1,18 +1,69 @@
 package handler
 
 import (
+	"context"
+	"crypto/subtle"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/SawitProRecruitment/UserService/common"
 	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/SawitProRecruitment/UserService/logging"
+	"github.com/SawitProRecruitment/UserService/service"
 )
 
-func constructErrorResponse(err *common.CustomError) (int, generated.ErrorResponse) {
+const (
+	mergePatchContentType = "application/merge-patch+json"
+
+	// maxAvatarRequestSize leaves room for the multipart framing around the avatar file.
+	maxAvatarRequestSize = service.MaxAvatarSize + 64<<10
+
+	// statusClientClosedRequest is recorded for requests whose client went away before the answer, nginx
+	// uses the same code. The client never sees it.
+	statusClientClosedRequest = 499
+)
+
+// errorCodes holds the machine readable codes of the errors clients are expected to handle.
+var errorCodes = map[common.ErrType]string{
+	common.ErrAccountPending:   "account_pending",
+	common.ErrAccountSuspended: "account_suspended",
+	common.ErrAccountLocked:    "account_locked",
+	common.ErrAccountDeleted:   "account_deleted",
+	// Retrying the same request is expected to succeed.
+	common.ErrTransactionConflict: "transaction_conflict",
+	common.ErrPreconditionFailed:  "precondition_failed",
+	// Retrying the same request later is expected to succeed.
+	common.ErrUnavailable: "unavailable",
+	common.ErrTimeout:     "timeout",
+	// Retrying with the same Idempotency-Key is expected to succeed, or to replay the response.
+	common.ErrIdempotencyKeyInUse:  "idempotency_key_in_use",
+	common.ErrIdempotencyKeyReused: "idempotency_key_reused",
+}
+
+// internalErrorMessage replaces the message of unexpected errors, which may quote database errors or other
+// internals that clients must not see.
+const internalErrorMessage = "internal server error"
+
+// constructErrorResponse maps err to a status code and response body. Server errors are logged in full with
+// the request ID from ctx, and for unexpected errors the client only gets that ID to refer to them. Once the
+// client has gone away, which cancels ctx, the error is whatever the cancellation caused, such as a query
+// cancelled like a statement timeout, and not a server error.
+func constructErrorResponse(ctx context.Context, err *common.CustomError) (int, generated.ErrorResponse) {
+	if ctx.Err() == context.Canceled || err.ErrType == common.ErrCanceled {
+		logging.FromContext(ctx).Info("request cancelled by the client", "error", err.Error())
+		return statusClientClosedRequest, generated.ErrorResponse{Message: "request was cancelled"}
+	}
+
 	response := generated.ErrorResponse{
 		Message: err.Message,
 	}
 
+	if code, ok := errorCodes[err.ErrType]; ok {
+		response.Code = &code
+	}
+
 	if len(err.Details) > 0 {
 		var errDetails []struct {
 			Error string
@@ -31,16 +82,46 @@ func constructErrorResponse(err *common.CustomError) (int, generated.ErrorRespon
 	switch err.ErrType {
 	case common.ErrInvalidInput:
 		statusCode = http.StatusBadRequest
-	case common.ErrUnauthorized:
+	case common.ErrUnauthorized, common.ErrAccountPending, common.ErrAccountSuspended, common.ErrAccountLocked, common.ErrAccountDeleted:
 		statusCode = http.StatusForbidden
-	case common.ErrEntityAlreadyExists:
+	case common.ErrEntityNotFound:
+		statusCode = http.StatusNotFound
+	case common.ErrEntityAlreadyExists, common.ErrInvalidStatusTransition, common.ErrTransactionConflict, common.ErrIdempotencyKeyInUse:
 		statusCode = http.StatusConflict
+	case common.ErrPreconditionFailed:
+		statusCode = http.StatusPreconditionFailed
+	case common.ErrIdempotencyKeyReused:
+		statusCode = http.StatusUnprocessableEntity
+	case common.ErrPayloadTooLarge:
+		statusCode = http.StatusRequestEntityTooLarge
+	case common.ErrUnsupportedMediaType:
+		statusCode = http.StatusUnsupportedMediaType
 	case common.ErrTooManyAttempts:
 		statusCode = http.StatusTooManyRequests
+	case common.ErrUnavailable:
+		statusCode = http.StatusServiceUnavailable
+	case common.ErrTimeout:
+		statusCode = http.StatusGatewayTimeout
 	default:
 		statusCode = http.StatusInternalServerError
 	}
 
+	if statusCode >= http.StatusInternalServerError {
+		keyvals := []interface{}{"error", err.Error(), "status", statusCode}
+		if len(err.Details) > 0 {
+			keyvals = append(keyvals, "details", err.Details)
+		}
+		logging.FromContext(ctx).Error("internal error", keyvals...)
+	}
+
+	if statusCode == http.StatusInternalServerError {
+		requestID, _ := ctx.Value(common.KeyRequestID).(string)
+		response = generated.ErrorResponse{
+			Message:   internalErrorMessage,
+			RequestId: &requestID,
+		}
+	}
+
 	return statusCode, response
 }
 
@@ -54,3 +135,42 @@ func extractAccessToken(authParam string) (string, *generated.ErrorResponse) {
 
 	return strings.TrimPrefix(authParam, "Bearer "), nil
 }
+
+func (s *Server) authorizeAdmin(adminKey string) *generated.ErrorResponse {
+	if s.adminAPIKey == "" || subtle.ConstantTimeCompare([]byte(adminKey), []byte(s.adminAPIKey)) != 1 {
+		return &generated.ErrorResponse{
+			Message: "invalid admin key",
+		}
+	}
+	return nil
+}
+
+func formatETag(version int64) string {
+	return fmt.Sprintf("%q", strconv.FormatInt(version, 10))
+}
+
+// parseIfMatch returns the version an If-Match header refers to. It is zero when the header is absent or
+// "*", which both mean the update is unconditional. Weak or malformed tags can never match the strong
+// ETag of a profile.
+func parseIfMatch(ifMatch *string) (version int64, errResponse *generated.ErrorResponse) {
+	if ifMatch == nil || strings.TrimSpace(*ifMatch) == "*" {
+		return 0, nil
+	}
+
+	code := errorCodes[common.ErrPreconditionFailed]
+	invalid := &generated.ErrorResponse{
+		Message: "If-Match does not match the current profile version",
+		Code:    &code,
+	}
+
+	tag, err := strconv.Unquote(strings.TrimSpace(*ifMatch))
+	if err != nil {
+		return 0, invalid
+	}
+
+	version, err = strconv.ParseInt(tag, 10, 64)
+	if err != nil || version < 1 {
+		return 0, invalid
+	}
+	return version, nil
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"regexp"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/logging"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/service"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed marks a response replayed from an earlier request with the same key.
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	adminKeyHeader = "X-Admin-Key"
+
+	// maxIdempotentRequestSize caps the request bodies read to fingerprint a request.
+	maxIdempotentRequestSize = 1 << 20
+)
+
+var idempotencyKeyPattern = regexp.MustCompile(`^[\x21-\x7e]{1,255}$`)
+
+// idempotencyExemptRoutes are not covered by idempotency keys, because their responses carry secrets that
+// must not be stored. Login responses hold access tokens, and logging in again is harmless anyway. A new
+// webhook subscription holds its signing secret, which is only ever returned once.
+var idempotencyExemptRoutes = map[string]bool{
+	"/api/v1/users/login":    true,
+	"/api/v1/admin/webhooks": true,
+}
+
+// IdempotencyMiddleware lets clients retry POST requests safely by sending an Idempotency-Key header. The
+// response to the first request with a key is stored, and retries with the same key and request get it back
+// with Idempotent-Replayed set instead of running the request again. A key sent with a different request is
+// rejected with 422, and a retry arriving while the first request is still handled with 409. Server errors
+// are not stored, so a retry with the same key runs the request again.
+//
+// Keys are scoped to the credentials a request carries. Credentials and requests are stored as HMACs keyed
+// with fingerprintKey, since request bodies may hold passwords. It has to run after RequestMetadataMiddleware
+// to log with the request ID.
+func IdempotencyMiddleware(idempotencyService service.IdempotencyService, fingerprintKey []byte) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			key := req.Header.Get(HeaderIdempotencyKey)
+			if req.Method != http.MethodPost || key == "" || idempotencyExemptRoutes[routeTemplate(ctx)] {
+				return next(ctx)
+			}
+
+			if !idempotencyKeyPattern.MatchString(key) {
+				return ctx.JSON(constructErrorResponse(req.Context(), common.NewCustomError(common.ErrInvalidInput, "invalid request params",
+					"Idempotency-Key must have 1 to 255 printable ASCII characters")))
+			}
+
+			body, err := io.ReadAll(io.LimitReader(req.Body, maxIdempotentRequestSize+1))
+			if err != nil {
+				return ctx.JSON(constructErrorResponse(req.Context(), common.WrapError(common.ErrInvalidInput, err, "error reading request body")))
+			}
+			if len(body) > maxIdempotentRequestSize {
+				return ctx.JSON(constructErrorResponse(req.Context(), common.NewCustomError(common.ErrPayloadTooLarge, "request body is too large")))
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			record := model.IdempotencyRecord{
+				Principal:   requestPrincipal(req, fingerprintKey),
+				Key:         key,
+				Fingerprint: requestFingerprint(req, body, fingerprintKey),
+			}
+			stored, errBegin := idempotencyService.Begin(req.Context(), record)
+			if errBegin != nil {
+				return ctx.JSON(constructErrorResponse(req.Context(), errBegin))
+			}
+			if stored != nil {
+				return replayResponse(ctx, *stored)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: ctx.Response().Writer}
+			ctx.Response().Writer = recorder
+			err = next(ctx)
+			ctx.Response().Writer = recorder.ResponseWriter
+
+			// The client may be gone by now, its retry still needs the outcome.
+			logger := logging.FromContext(req.Context())
+			storeCtx := logging.WithLogger(context.Background(), logger)
+
+			status := responseStatus(ctx, err)
+			if err != nil || status >= http.StatusInternalServerError {
+				if errAbandon := idempotencyService.Abandon(storeCtx, record); errAbandon != nil {
+					logger.Error("error releasing idempotency key", "error", errAbandon.Error())
+				}
+				return err
+			}
+
+			record.StatusCode = status
+			record.ContentType = ctx.Response().Header().Get(echo.HeaderContentType)
+			record.Body = recorder.body.Bytes()
+			if errComplete := idempotencyService.Complete(storeCtx, record); errComplete != nil {
+				logger.Error("error storing idempotent response", "error", errComplete.Error())
+			}
+			return nil
+		}
+	}
+}
+
+// requestPrincipal identifies the caller by a hash of the credentials it sent, anonymous callers share one.
+func requestPrincipal(req *http.Request, key []byte) string {
+	hash := hmac.New(sha256.New, key)
+	io.WriteString(hash, req.Header.Get(echo.HeaderAuthorization))
+	io.WriteString(hash, "\n")
+	io.WriteString(hash, req.Header.Get(adminKeyHeader))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// requestFingerprint hashes what makes two requests the same: method, URI and body.
+func requestFingerprint(req *http.Request, body []byte, key []byte) []byte {
+	hash := hmac.New(sha256.New, key)
+	io.WriteString(hash, req.Method)
+	io.WriteString(hash, " ")
+	io.WriteString(hash, req.RequestURI)
+	io.WriteString(hash, "\n")
+	hash.Write(body)
+	return hash.Sum(nil)
+}
+
+func replayResponse(ctx echo.Context, record model.IdempotencyRecord) error {
+	header := ctx.Response().Header()
+	header.Set(HeaderIdempotentReplayed, "true")
+	if record.ContentType != "" {
+		header.Set(echo.HeaderContentType, record.ContentType)
+	}
+
+	ctx.Response().WriteHeader(record.StatusCode)
+	_, err := ctx.Response().Write(record.Body)
+	return err
+}
+
+// responseRecorder keeps a copy of the response body written through it.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
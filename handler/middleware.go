@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/SawitProRecruitment/UserService/logging"
+	"github.com/SawitProRecruitment/UserService/metrics"
+	"github.com/SawitProRecruitment/UserService/tracing"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// requestIDPattern limits propagated request IDs to characters that are safe to log and echo back.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestMetadataMiddleware stores the client IP and request ID in the request context so that the
+// service layer can attach them to audit events. The request ID is taken from X-Request-ID or generated,
+// and sent back in the response. The context also carries a logger adding the request and trace IDs to
+// every entry, and keeps the reads of a request that wrote to the database on the primary.
+func RequestMetadataMiddleware(logger *logging.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			requestID := ctx.Request().Header.Get(echo.HeaderXRequestID)
+			if !requestIDPattern.MatchString(requestID) {
+				requestID = uuid.NewString()
+			}
+			ctx.Response().Header().Set(echo.HeaderXRequestID, requestID)
+
+			appCtx := context.WithValue(ctx.Request().Context(), common.KeyClientIP, ctx.RealIP())
+			appCtx = context.WithValue(appCtx, common.KeyRequestID, requestID)
+			appCtx = common.WithReadYourWrites(appCtx)
+
+			requestLogger := logger.With("request_id", requestID)
+			if span := tracing.SpanFromContext(appCtx); span != nil {
+				requestLogger = requestLogger.With("trace_id", span.SpanContext().TraceID.String())
+			}
+			appCtx = logging.WithLogger(appCtx, requestLogger)
+
+			ctx.SetRequest(ctx.Request().WithContext(appCtx))
+			return next(ctx)
+		}
+	}
+}
+
+// AccessLogMiddleware logs every request once it has been handled. It has to run after
+// RequestMetadataMiddleware to log with the request ID.
+func AccessLogMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			start := time.Now()
+			err := next(ctx)
+
+			req := ctx.Request()
+			logging.FromContext(req.Context()).Info("request handled",
+				"method", req.Method,
+				"route", routeTemplate(ctx),
+				"status", responseStatus(ctx, err),
+				"duration_ms", float64(time.Since(start))/float64(time.Millisecond),
+				"client_ip", ctx.RealIP(),
+			)
+			return err
+		}
+	}
+}
+
+// HTTPErrorHandler writes errors returned to echo, such as unknown routes, as an ErrorResponse. Errors
+// other than echo.HTTPError are unexpected: they are logged and the client only gets the request ID.
+func HTTPErrorHandler(err error, ctx echo.Context) {
+	if ctx.Response().Committed {
+		logging.FromContext(ctx.Request().Context()).Error("error after response was written", "error", err)
+		return
+	}
+
+	var status int
+	var response generated.ErrorResponse
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Code < http.StatusInternalServerError {
+		status = httpErr.Code
+		response.Message = fmt.Sprint(httpErr.Message)
+	} else {
+		status, response = constructErrorResponse(ctx.Request().Context(), common.WrapError(common.ErrUnexpectedError, err, "unhandled error"))
+		if httpErr != nil {
+			status = httpErr.Code
+		}
+	}
+
+	if ctx.Request().Method == http.MethodHead {
+		err = ctx.NoContent(status)
+	} else {
+		err = ctx.JSON(status, response)
+	}
+	if err != nil {
+		logging.FromContext(ctx.Request().Context()).Error("error writing error response", "error", err)
+	}
+}
+
+// MetricsMiddleware counts requests and measures their latency in registry. Requests are labelled with the
+// route template, such as /api/v1/admin/users/:userId/status, to keep the number of series bounded.
+func MetricsMiddleware(registry *metrics.Registry) echo.MiddlewareFunc {
+	httpRequestsTotal := registry.NewCounter("http_requests_total",
+		"HTTP requests by method, route and status code.", "method", "route", "code")
+	httpRequestDuration := registry.NewHistogram("http_request_duration_seconds",
+		"Time spent serving HTTP requests by method and route.", metrics.DefaultBuckets, "method", "route")
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			start := time.Now()
+			err := next(ctx)
+
+			status := responseStatus(ctx, err)
+			route := routeTemplate(ctx)
+
+			method := ctx.Request().Method
+			httpRequestsTotal.Inc(method, route, strconv.Itoa(status))
+			httpRequestDuration.Observe(time.Since(start).Seconds(), method, route)
+			return err
+		}
+	}
+}
+
+// TracingMiddleware continues the trace of an incoming traceparent header, or starts a new one, with a server
+// span covering the whole request. Handlers and services find the span in the request context.
+func TracingMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			route := routeTemplate(ctx)
+
+			appCtx := tracing.Extract(req.Context(), req.Header)
+			appCtx, span := tracing.Start(appCtx, req.Method+" "+route, tracing.WithSpanKind(tracing.SpanKindServer))
+			defer span.End()
+			span.SetAttribute("http.method", req.Method)
+			span.SetAttribute("http.route", route)
+
+			ctx.SetRequest(req.WithContext(appCtx))
+			err := next(ctx)
+
+			status := responseStatus(ctx, err)
+			span.SetAttribute("http.status_code", status)
+			if status >= http.StatusInternalServerError {
+				span.SetError(http.StatusText(status))
+			}
+			return err
+		}
+	}
+}
+
+// RequestTimeoutMiddleware sets a deadline on the request context. Repositories stop waiting for the
+// database once it passes, and the request fails with a timeout error instead of hanging. A zero timeout
+// disables the deadline.
+func RequestTimeoutMiddleware(timeout time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if timeout <= 0 {
+				return next(ctx)
+			}
+
+			appCtx, cancel := context.WithTimeout(ctx.Request().Context(), timeout)
+			defer cancel()
+
+			ctx.SetRequest(ctx.Request().WithContext(appCtx))
+			return next(ctx)
+		}
+	}
+}
+
+// responseStatus returns the status code of the response to a request handled with err. Errors returned to
+// echo are only written by its error handler, after the middlewares have returned.
+func responseStatus(ctx echo.Context, err error) int {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+	if err != nil {
+		return http.StatusInternalServerError
+	}
+	return ctx.Response().Status
+}
+
+// routeTemplate returns the route a request matched, such as /api/v1/admin/users/:userId/status.
+func routeTemplate(ctx echo.Context) string {
+	if route := ctx.Path(); route != "" {
+		return route
+	}
+	return "unmatched"
+}
@@ -1,22 +1,48 @@
 package handler
 
 import (
+	"github.com/SawitProRecruitment/UserService/health"
 	"github.com/SawitProRecruitment/UserService/service"
 )
 
 type Server struct {
 	authService    service.AuthService
 	profileService service.ProfileService
+	accountService service.AccountService
+	exportService  service.ExportService
+	auditService   service.AuditService
+	webhookService service.WebhookService
+	healthChecker  *health.Checker
+	adminAPIKey    string
 }
 
 type NewServerOptions struct {
 	AuthService    service.AuthService
 	ProfileService service.ProfileService
+	AccountService service.AccountService
+	ExportService  service.ExportService
+	AuditService   service.AuditService
+	WebhookService service.WebhookService
+	// HealthChecker backs /readyz. Without one the service always reports ready.
+	HealthChecker *health.Checker
+	// AdminAPIKey guards the admin endpoints. Admin endpoints reject every request when it is empty.
+	AdminAPIKey string
 }
 
 func NewServer(opts NewServerOptions) *Server {
+	healthChecker := opts.HealthChecker
+	if healthChecker == nil {
+		healthChecker = health.NewChecker(health.CheckerOptions{})
+	}
+
 	return &Server{
 		authService:    opts.AuthService,
 		profileService: opts.ProfileService,
+		accountService: opts.AccountService,
+		exportService:  opts.ExportService,
+		auditService:   opts.AuditService,
+		webhookService: opts.WebhookService,
+		healthChecker:  healthChecker,
+		adminAPIKey:    opts.AdminAPIKey,
 	}
 }
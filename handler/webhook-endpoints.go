@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/SawitProRecruitment/UserService/generated"
+	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) GetApiV1AdminWebhooks(ctx echo.Context, params generated.GetApiV1AdminWebhooksParams) error {
+	if errResponse := s.authorizeAdmin(params.XAdminKey); errResponse != nil {
+		return ctx.JSON(http.StatusForbidden, errResponse)
+	}
+
+	result, err := s.webhookService.ListWebhooks(ctx.Request().Context())
+	if err != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (s *Server) PostApiV1AdminWebhooks(ctx echo.Context, params generated.PostApiV1AdminWebhooksParams) error {
+	if errResponse := s.authorizeAdmin(params.XAdminKey); errResponse != nil {
+		return ctx.JSON(http.StatusForbidden, errResponse)
+	}
+
+	var request generated.CreateWebhookSubscriptionRequest
+	if err := json.NewDecoder(ctx.Request().Body).Decode(&request); err != nil {
+		response := generated.ErrorResponse{
+			Message: err.Error(),
+		}
+		return ctx.JSON(http.StatusBadRequest, response)
+	}
+
+	result, err := s.webhookService.CreateWebhook(ctx.Request().Context(), request)
+	if err != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
+	}
+	return ctx.JSON(http.StatusCreated, result)
+}
+
+func (s *Server) GetApiV1AdminWebhooksWebhookId(ctx echo.Context, webhookId openapi_types.UUID, params generated.GetApiV1AdminWebhooksWebhookIdParams) error {
+	if errResponse := s.authorizeAdmin(params.XAdminKey); errResponse != nil {
+		return ctx.JSON(http.StatusForbidden, errResponse)
+	}
+
+	result, err := s.webhookService.GetWebhook(ctx.Request().Context(), webhookId)
+	if err != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (s *Server) PutApiV1AdminWebhooksWebhookId(ctx echo.Context, webhookId openapi_types.UUID, params generated.PutApiV1AdminWebhooksWebhookIdParams) error {
+	if errResponse := s.authorizeAdmin(params.XAdminKey); errResponse != nil {
+		return ctx.JSON(http.StatusForbidden, errResponse)
+	}
+
+	var request generated.UpdateWebhookSubscriptionRequest
+	if err := json.NewDecoder(ctx.Request().Body).Decode(&request); err != nil {
+		response := generated.ErrorResponse{
+			Message: err.Error(),
+		}
+		return ctx.JSON(http.StatusBadRequest, response)
+	}
+
+	result, err := s.webhookService.UpdateWebhook(ctx.Request().Context(), webhookId, request)
+	if err != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (s *Server) DeleteApiV1AdminWebhooksWebhookId(ctx echo.Context, webhookId openapi_types.UUID, params generated.DeleteApiV1AdminWebhooksWebhookIdParams) error {
+	if errResponse := s.authorizeAdmin(params.XAdminKey); errResponse != nil {
+		return ctx.JSON(http.StatusForbidden, errResponse)
+	}
+
+	if err := s.webhookService.DeleteWebhook(ctx.Request().Context(), webhookId); err != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (s *Server) GetApiV1AdminWebhooksWebhookIdDeliveries(ctx echo.Context, webhookId openapi_types.UUID, params generated.GetApiV1AdminWebhooksWebhookIdDeliveriesParams) error {
+	if errResponse := s.authorizeAdmin(params.XAdminKey); errResponse != nil {
+		return ctx.JSON(http.StatusForbidden, errResponse)
+	}
+
+	result, err := s.webhookService.ListDeliveries(ctx.Request().Context(), webhookId, params)
+	if err != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+func (s *Server) PostApiV1AdminWebhooksWebhookIdDeliveriesDeliveryIdRedelivery(ctx echo.Context, webhookId openapi_types.UUID, deliveryId openapi_types.UUID, params generated.PostApiV1AdminWebhooksWebhookIdDeliveriesDeliveryIdRedeliveryParams) error {
+	if errResponse := s.authorizeAdmin(params.XAdminKey); errResponse != nil {
+		return ctx.JSON(http.StatusForbidden, errResponse)
+	}
+
+	result, err := s.webhookService.Redeliver(ctx.Request().Context(), webhookId, deliveryId)
+	if err != nil {
+		return ctx.JSON(constructErrorResponse(ctx.Request().Context(), err))
+	}
+	return ctx.JSON(http.StatusAccepted, result)
+}
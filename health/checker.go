@@ -0,0 +1,115 @@
+// Package health reports whether the service is alive and whether it is ready to receive traffic.
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Check returns an error when the dependency it checks is not usable. It should return once ctx is done.
+type Check func(ctx context.Context) error
+
+type CheckerOptions struct {
+	// Timeout bounds every readiness check individually.
+	Timeout time.Duration
+}
+
+// Checker runs the readiness checks registered with AddCheck.
+type Checker struct {
+	opts     *CheckerOptions
+	checks   []namedCheck
+	draining atomic.Bool
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Result is the outcome of a single check.
+type Result struct {
+	Name     string
+	Err      error
+	Duration time.Duration
+}
+
+// Report is the outcome of every readiness check, sorted by check name.
+type Report struct {
+	Draining bool
+	Results  []Result
+}
+
+// Ready reports whether the service is not draining and every check passed.
+func (r Report) Ready() bool {
+	if r.Draining {
+		return false
+	}
+	for _, result := range r.Results {
+		if result.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func NewChecker(opts CheckerOptions) *Checker {
+	return &Checker{
+		opts: &opts,
+	}
+}
+
+// AddCheck registers a readiness check. Checks must be added before the checker is used.
+func (c *Checker) AddCheck(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// SetDraining makes readiness fail from now on, so traffic moves away before the service stops.
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+// CheckReadiness runs every check concurrently, each bounded by the check timeout. A check that ignores
+// its context is reported as timed out rather than holding up the report.
+func (c *Checker) CheckReadiness(ctx context.Context) Report {
+	report := Report{
+		Draining: c.draining.Load(),
+		Results:  make([]Result, len(c.checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, nc := range c.checks {
+		wg.Add(1)
+		go func(i int, nc namedCheck) {
+			defer wg.Done()
+			report.Results[i] = c.run(ctx, nc)
+		}(i, nc)
+	}
+	wg.Wait()
+
+	sort.Slice(report.Results, func(i, j int) bool {
+		return report.Results[i].Name < report.Results[j].Name
+	})
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, nc namedCheck) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- nc.check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	return Result{Name: nc.name, Err: err, Duration: time.Since(start)}
+}
@@ -0,0 +1,87 @@
+package health_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/health"
+	"github.com/stretchr/testify/suite"
+)
+
+type CheckerTestSuite struct {
+	suite.Suite
+	sut *health.Checker
+}
+
+func (s *CheckerTestSuite) SetupTest() {
+	s.sut = health.NewChecker(health.CheckerOptions{Timeout: 50 * time.Millisecond})
+}
+
+func TestChecker(t *testing.T) {
+	suite.Run(t, new(CheckerTestSuite))
+}
+
+func (s *CheckerTestSuite) TestCheckReadinessGivenPassingChecksShouldBeReady() {
+	s.sut.AddCheck("b", func(ctx context.Context) error { return nil })
+	s.sut.AddCheck("a", func(ctx context.Context) error { return nil })
+
+	report := s.sut.CheckReadiness(context.Background())
+
+	s.True(report.Ready())
+	s.Equal("a", report.Results[0].Name)
+	s.Equal("b", report.Results[1].Name)
+}
+
+func (s *CheckerTestSuite) TestCheckReadinessGivenFailingCheckShouldNotBeReady() {
+	checkErr := errors.New("connection refused")
+	s.sut.AddCheck("database", func(ctx context.Context) error { return checkErr })
+	s.sut.AddCheck("signing_key", func(ctx context.Context) error { return nil })
+
+	report := s.sut.CheckReadiness(context.Background())
+
+	s.False(report.Ready())
+	s.Equal(checkErr, report.Results[0].Err)
+	s.NoError(report.Results[1].Err)
+}
+
+func (s *CheckerTestSuite) TestCheckReadinessGivenCheckIgnoringContextShouldTimeOut() {
+	release := make(chan struct{})
+	defer close(release)
+	s.sut.AddCheck("stuck", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	start := time.Now()
+	report := s.sut.CheckReadiness(context.Background())
+
+	s.Less(time.Since(start), time.Second)
+	s.ErrorIs(report.Results[0].Err, context.DeadlineExceeded)
+}
+
+func (s *CheckerTestSuite) TestCheckReadinessAfterSetDrainingShouldNotBeReady() {
+	s.sut.AddCheck("database", func(ctx context.Context) error { return nil })
+	s.sut.SetDraining()
+
+	report := s.sut.CheckReadiness(context.Background())
+
+	s.True(report.Draining)
+	s.False(report.Ready())
+}
+
+func (s *CheckerTestSuite) TestPoolCheckShouldFailOnlyWhileRequestsWaitForConnections() {
+	stats := sql.DBStats{MaxOpenConnections: 2, InUse: 2, WaitCount: 5}
+	check := health.PoolCheck(func() sql.DBStats { return stats })
+
+	s.Error(check(context.Background()))
+
+	// All connections are still busy, but nobody had to wait since the last check.
+	s.NoError(check(context.Background()))
+
+	stats.WaitCount = 6
+	stats.InUse = 1
+	s.NoError(check(context.Background()))
+}
@@ -0,0 +1,55 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/SawitProRecruitment/UserService/migrations"
+)
+
+// DatabaseCheck fails when the database cannot be reached.
+func DatabaseCheck(db *sql.DB) Check {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// PoolCheck fails when the connection pool is saturated: every connection is in use and requests have been
+// waiting for one since the previous check. A busy pool that still keeps up passes.
+func PoolCheck(stats func() sql.DBStats) Check {
+	var mu sync.Mutex
+	var lastWaitCount int64
+
+	return func(ctx context.Context) error {
+		current := stats()
+
+		mu.Lock()
+		waited := current.WaitCount - lastWaitCount
+		lastWaitCount = current.WaitCount
+		mu.Unlock()
+
+		if current.MaxOpenConnections > 0 && current.InUse >= current.MaxOpenConnections && waited > 0 {
+			return fmt.Errorf("all %d connections are in use and %d requests waited for one", current.MaxOpenConnections, waited)
+		}
+		return nil
+	}
+}
+
+// MigrationCheck fails until the database schema is at least at the newest migration this build embeds.
+// A newer schema passes, since migrations are backward compatible while instances roll over.
+func MigrationCheck(db *sql.DB) Check {
+	latest := migrations.LatestVersion()
+
+	return func(ctx context.Context) error {
+		current, err := migrations.CurrentVersion(ctx, db)
+		if err != nil {
+			return err
+		}
+		if current < latest {
+			return fmt.Errorf("database is at migration %d, expected %d", current, latest)
+		}
+		return nil
+	}
+}
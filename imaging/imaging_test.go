@@ -0,0 +1,102 @@
+package imaging_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/imaging"
+	"github.com/stretchr/testify/suite"
+)
+
+type ImagingTestSuite struct {
+	suite.Suite
+}
+
+func TestImaging(t *testing.T) {
+	suite.Run(t, new(ImagingTestSuite))
+}
+
+func (s *ImagingTestSuite) encodePNG(img image.Image) []byte {
+	var buffer bytes.Buffer
+	s.Require().NoError(png.Encode(&buffer, img))
+	return buffer.Bytes()
+}
+
+func (s *ImagingTestSuite) TestDecodeShouldDecodePNG() {
+	img, err := imaging.Decode(s.encodePNG(image.NewRGBA(image.Rect(0, 0, 40, 20))), 1000)
+
+	s.Require().NoError(err)
+	s.Equal(image.Rect(0, 0, 40, 20), img.Bounds())
+}
+
+func (s *ImagingTestSuite) TestDecodeGivenTooManyPixelsShouldFail() {
+	_, err := imaging.Decode(s.encodePNG(image.NewRGBA(image.Rect(0, 0, 40, 20))), 799)
+
+	s.ErrorIs(err, imaging.ErrTooManyPixels)
+}
+
+func (s *ImagingTestSuite) TestDecodeGivenNoImageShouldFail() {
+	_, err := imaging.Decode([]byte("not an image"), 1000)
+
+	s.Error(err)
+}
+
+func (s *ImagingTestSuite) TestThumbnailShouldCropCentreSquareAndScaleIt() {
+	// Red bars on both sides of a blue square, the crop keeps only the blue square.
+	src := image.NewRGBA(image.Rect(0, 0, 300, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 300; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 100 && x < 200 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			src.SetRGBA(x, y, c)
+		}
+	}
+
+	thumbnail := imaging.Thumbnail(src, 10)
+
+	s.Equal(image.Rect(0, 0, 10, 10), thumbnail.Bounds())
+	for _, point := range []image.Point{{0, 0}, {9, 0}, {5, 5}, {9, 9}} {
+		s.Equal(color.RGBA{B: 255, A: 255}, thumbnail.RGBAAt(point.X, point.Y), point)
+	}
+}
+
+func (s *ImagingTestSuite) TestThumbnailShouldAverageSourcePixels() {
+	// A checkerboard scaled to a single pixel averages its black and white pixels to grey.
+	src := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			c := color.RGBA{A: 255}
+			if (x+y)%2 == 0 {
+				c = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+			}
+			src.SetRGBA(x, y, c)
+		}
+	}
+
+	thumbnail := imaging.Thumbnail(src, 1)
+
+	s.Equal(color.RGBA{R: 127, G: 127, B: 127, A: 255}, thumbnail.RGBAAt(0, 0))
+}
+
+func (s *ImagingTestSuite) TestThumbnailShouldRenderTransparencyOnWhite() {
+	thumbnail := imaging.Thumbnail(image.NewRGBA(image.Rect(0, 0, 8, 8)), 16)
+
+	s.Equal(image.Rect(0, 0, 16, 16), thumbnail.Bounds())
+	s.Equal(color.RGBA{R: 255, G: 255, B: 255, A: 255}, thumbnail.RGBAAt(15, 15))
+}
+
+func (s *ImagingTestSuite) TestEncodeJPEGShouldWriteDecodableJPEG() {
+	var buffer bytes.Buffer
+	s.Require().NoError(imaging.EncodeJPEG(&buffer, imaging.Thumbnail(image.NewRGBA(image.Rect(0, 0, 8, 8)), 4)))
+
+	config, format, err := image.DecodeConfig(&buffer)
+	s.Require().NoError(err)
+	s.Equal("jpeg", format)
+	s.Equal(4, config.Width)
+	s.Equal(4, config.Height)
+}
@@ -0,0 +1,147 @@
+// Package lifecycle starts the parts of the service in dependency order and stops them in reverse order
+// on shutdown.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/logging"
+)
+
+type ManagerOptions struct {
+	// ShutdownTimeout bounds the time all components together get to stop.
+	ShutdownTimeout time.Duration
+	// DrainDelay is waited after the shutdown hooks ran and before the first component stops. It is not
+	// part of ShutdownTimeout.
+	DrainDelay time.Duration
+	// Logger defaults to logging.Default().
+	Logger *logging.Logger
+}
+
+// Manager owns the components of the service. Components are started in the order they are added, so
+// every component has to be added after the components it depends on.
+type Manager struct {
+	opts       *ManagerOptions
+	components []component
+	hooks      []func()
+}
+
+type component struct {
+	name  string
+	start func(fail func(error))
+	stop  func(ctx context.Context) error
+}
+
+func NewManager(opts ManagerOptions) *Manager {
+	if opts.Logger == nil {
+		opts.Logger = logging.Default()
+	}
+	return &Manager{
+		opts: &opts,
+	}
+}
+
+// AddWorker runs a background worker. On shutdown its context is cancelled and the manager waits for run
+// to return.
+func (m *Manager) AddWorker(name string, run func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	m.components = append(m.components, component{
+		name: name,
+		start: func(fail func(error)) {
+			go func() {
+				defer close(done)
+				run(ctx)
+			}()
+		},
+		stop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return fmt.Errorf("%s did not stop in time: %w", name, stopCtx.Err())
+			}
+		},
+	})
+}
+
+// AddServer runs serve until shutdown is called, which should stop accepting connections and wait for
+// in-flight requests. A serve error other than http.ErrServerClosed shuts the whole service down.
+func (m *Manager) AddServer(name string, serve func() error, shutdown func(ctx context.Context) error) {
+	m.components = append(m.components, component{
+		name: name,
+		start: func(fail func(error)) {
+			go func() {
+				if err := serve(); err != nil && err != http.ErrServerClosed {
+					fail(fmt.Errorf("%s: %w", name, err))
+				}
+			}()
+		},
+		stop: shutdown,
+	})
+}
+
+// AddCloser closes a resource, such as the database pool, once every component added after it has stopped.
+func (m *Manager) AddCloser(name string, close func() error) {
+	m.components = append(m.components, component{
+		name:  name,
+		start: func(fail func(error)) {},
+		stop: func(ctx context.Context) error {
+			return close()
+		},
+	})
+}
+
+// OnShutdown registers hook to run as soon as shutdown begins, before DrainDelay and before any component
+// stops.
+func (m *Manager) OnShutdown(hook func()) {
+	m.hooks = append(m.hooks, hook)
+}
+
+// Run starts every component and blocks until ctx is cancelled or a server fails, then stops the
+// components in reverse order. It returns the server failure or the first error of a component that did
+// not stop cleanly.
+func (m *Manager) Run(ctx context.Context) error {
+	failures := make(chan error, len(m.components))
+	fail := func(err error) {
+		failures <- err
+	}
+
+	for _, c := range m.components {
+		c.start(fail)
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		m.opts.Logger.Info("shutting down")
+	case runErr = <-failures:
+		m.opts.Logger.Error("shutting down after failure", "error", runErr)
+	}
+
+	for _, hook := range m.hooks {
+		hook()
+	}
+	if m.opts.DrainDelay > 0 {
+		time.Sleep(m.opts.DrainDelay)
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), m.opts.ShutdownTimeout)
+	defer cancel()
+
+	for i := len(m.components) - 1; i >= 0; i-- {
+		c := m.components[i]
+		if err := c.stop(stopCtx); err != nil {
+			m.opts.Logger.Error("error stopping component", "component", c.name, "error", err)
+			if runErr == nil {
+				runErr = err
+			}
+		}
+	}
+	return runErr
+}
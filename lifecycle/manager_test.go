@@ -0,0 +1,203 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/lifecycle"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/SawitProRecruitment/UserService/worker"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type ManagerTestSuite struct {
+	suite.Suite
+	mu      sync.Mutex
+	stopped []string
+}
+
+func (s *ManagerTestSuite) SetupTest() {
+	s.stopped = nil
+}
+
+func TestManager(t *testing.T) {
+	suite.Run(t, new(ManagerTestSuite))
+}
+
+func (s *ManagerTestSuite) recordStop(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = append(s.stopped, name)
+}
+
+func (s *ManagerTestSuite) TestRunShouldStopComponentsInReverseOrder() {
+	sut := lifecycle.NewManager(lifecycle.ManagerOptions{ShutdownTimeout: time.Second})
+	serving := make(chan struct{})
+
+	sut.AddCloser("database", func() error {
+		s.recordStop("database")
+		return nil
+	})
+	sut.AddWorker("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		s.recordStop("worker")
+	})
+	sut.AddServer("server", func() error {
+		<-serving
+		return http.ErrServerClosed
+	}, func(ctx context.Context) error {
+		close(serving)
+		s.recordStop("server")
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := sut.Run(ctx)
+
+	s.NoError(err)
+	s.Equal([]string{"server", "worker", "database"}, s.stopped)
+}
+
+func (s *ManagerTestSuite) TestRunShouldRunShutdownHooksBeforeStoppingComponents() {
+	sut := lifecycle.NewManager(lifecycle.ManagerOptions{ShutdownTimeout: time.Second, DrainDelay: 10 * time.Millisecond})
+
+	sut.AddCloser("database", func() error {
+		s.recordStop("database")
+		return nil
+	})
+	sut.OnShutdown(func() {
+		s.recordStop("readiness")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := sut.Run(ctx)
+
+	s.NoError(err)
+	s.Equal([]string{"readiness", "database"}, s.stopped)
+}
+
+func (s *ManagerTestSuite) TestRunGivenServerFailureShouldStopAndReturnIt() {
+	sut := lifecycle.NewManager(lifecycle.ManagerOptions{ShutdownTimeout: time.Second})
+	serveErr := errors.New("address already in use")
+
+	sut.AddWorker("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		s.recordStop("worker")
+	})
+	sut.AddServer("server", func() error {
+		return serveErr
+	}, func(ctx context.Context) error {
+		return nil
+	})
+
+	err := sut.Run(context.Background())
+
+	s.ErrorIs(err, serveErr)
+	s.Equal([]string{"worker"}, s.stopped)
+}
+
+func (s *ManagerTestSuite) TestRunGivenStuckWorkerShouldGiveUpAfterShutdownTimeout() {
+	sut := lifecycle.NewManager(lifecycle.ManagerOptions{ShutdownTimeout: 10 * time.Millisecond})
+	release := make(chan struct{})
+	defer close(release)
+
+	sut.AddCloser("database", func() error {
+		s.recordStop("database")
+		return nil
+	})
+	sut.AddWorker("worker", func(ctx context.Context) {
+		<-release
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := sut.Run(ctx)
+
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Equal([]string{"database"}, s.stopped)
+}
+
+// TestRunShouldFinishInFlightRequestsAndTheirBackgroundWork shuts down while requests are being served and
+// checks that every request completes and every login it recorded is saved before the database closes.
+func (s *ManagerTestSuite) TestRunShouldFinishInFlightRequestsAndTheirBackgroundWork() {
+	ctrl := gomock.NewController(s.T())
+	defer ctrl.Finish()
+
+	var databaseClosed bool
+	var saved []uuid.UUID
+	loginLogRepository := repository.NewMockLoginLogRepository(ctrl)
+	loginLogRepository.EXPECT().Save(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, loginLog model.LoginLog) (uuid.UUID, *common.CustomError) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.False(databaseClosed, "login saved after the database closed")
+			saved = append(saved, loginLog.UserID)
+			return uuid.New(), nil
+		})
+	recorder := worker.NewLoginLogRecorder(worker.LoginLogRecorderOptions{LoginLogRepository: loginLogRepository, BufferSize: 100})
+
+	const requests = 20
+	received := make(chan struct{}, requests)
+	release := make(chan struct{})
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+		recorder.Record(r.Context(), model.LoginLog{UserID: uuid.New(), LoginAt: time.Now()})
+		w.WriteHeader(http.StatusOK)
+	})}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+
+	sut := lifecycle.NewManager(lifecycle.ManagerOptions{ShutdownTimeout: 5 * time.Second})
+	sut.AddCloser("database", func() error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		databaseClosed = true
+		return nil
+	})
+	sut.AddWorker("login log recorder", recorder.Run)
+	sut.AddServer("http server", func() error { return server.Serve(listener) }, server.Shutdown)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- sut.Run(ctx) }()
+
+	statuses := make(chan int, requests)
+	for i := 0; i < requests; i++ {
+		go func() {
+			response, err := http.Get("http://" + listener.Addr().String())
+			if err != nil {
+				statuses <- 0
+				return
+			}
+			response.Body.Close()
+			statuses <- response.StatusCode
+		}()
+	}
+	for i := 0; i < requests; i++ {
+		<-received
+	}
+
+	cancel()
+	// Let the shutdown begin before the in-flight requests complete.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	s.NoError(<-done)
+	for i := 0; i < requests; i++ {
+		s.Equal(http.StatusOK, <-statuses)
+	}
+	s.Len(saved, requests)
+	s.True(databaseClosed)
+}
@@ -0,0 +1,178 @@
+// Package logging writes structured JSON logs. Every message and field passes through a redaction layer that
+// masks phone numbers, tokens and passwords, so personal data and credentials do not end up in the logs.
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	default:
+		return "error"
+	}
+}
+
+// ParseLevel parses debug, info, warn or error.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q", s)
+	}
+}
+
+type LoggerOptions struct {
+	Output io.Writer
+	// Level is the lowest level written.
+	Level Level
+}
+
+// Logger writes one JSON object per line with the time, level, message and key value pairs. Loggers derived
+// with With share the output of their parent.
+type Logger struct {
+	opts   *LoggerOptions
+	mu     *sync.Mutex
+	fields []interface{}
+}
+
+func NewLogger(opts LoggerOptions) *Logger {
+	return &Logger{
+		opts: &opts,
+		mu:   &sync.Mutex{},
+	}
+}
+
+var defaultLogger atomic.Pointer[Logger]
+
+func init() {
+	defaultLogger.Store(NewLogger(LoggerOptions{Output: os.Stderr, Level: LevelInfo}))
+}
+
+// Default returns the logger used when none has been injected, writing info and above to stderr until
+// SetDefault replaces it.
+func Default() *Logger {
+	return defaultLogger.Load()
+}
+
+func SetDefault(logger *Logger) {
+	defaultLogger.Store(logger)
+}
+
+type contextKey struct{}
+
+// WithLogger stores logger in ctx, typically one carrying the fields of the current request.
+func WithLogger(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx, or the default logger.
+func FromContext(ctx context.Context) *Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*Logger); ok {
+		return logger
+	}
+	return Default()
+}
+
+// With returns a logger adding the key value pairs to every entry.
+func (l *Logger) With(keyvals ...interface{}) *Logger {
+	fields := make([]interface{}, 0, len(l.fields)+len(keyvals))
+	fields = append(fields, l.fields...)
+	fields = append(fields, keyvals...)
+	return &Logger{opts: l.opts, mu: l.mu, fields: fields}
+}
+
+func (l *Logger) Debug(msg string, keyvals ...interface{}) {
+	l.log(LevelDebug, msg, keyvals)
+}
+
+func (l *Logger) Info(msg string, keyvals ...interface{}) {
+	l.log(LevelInfo, msg, keyvals)
+}
+
+func (l *Logger) Warn(msg string, keyvals ...interface{}) {
+	l.log(LevelWarn, msg, keyvals)
+}
+
+func (l *Logger) Error(msg string, keyvals ...interface{}) {
+	l.log(LevelError, msg, keyvals)
+}
+
+func (l *Logger) log(level Level, msg string, keyvals []interface{}) {
+	if level < l.opts.Level {
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"time":`)
+	writeJSON(&buf, time.Now().UTC().Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeJSON(&buf, level.String())
+	buf.WriteString(`,"msg":`)
+	writeJSON(&buf, redactString(msg))
+	writeFields(&buf, l.fields)
+	writeFields(&buf, keyvals)
+	buf.WriteString("}\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.opts.Output.Write(buf.Bytes())
+}
+
+// writeFields writes key value pairs. A key without a value is written under "!BADKEY", like log/slog does.
+func writeFields(buf *bytes.Buffer, keyvals []interface{}) {
+	for i := 0; i < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		var value interface{}
+		if !ok || i+1 == len(keyvals) {
+			key, value = "!BADKEY", keyvals[i]
+			i--
+		} else {
+			value = keyvals[i+1]
+		}
+
+		buf.WriteByte(',')
+		writeJSON(buf, key)
+		buf.WriteByte(':')
+		writeJSON(buf, redact(key, value))
+	}
+}
+
+func writeJSON(buf *bytes.Buffer, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprintf("!ERROR %s", err.Error()))
+	}
+	buf.Write(data)
+}
@@ -0,0 +1,160 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// Counter is a monotonically increasing value per combination of label values.
+type Counter struct {
+	desc
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labelValues []string
+	value       float64
+}
+
+// NewCounter registers a counter. A counter without labels is written as 0 before its first increment.
+func (r *Registry) NewCounter(name, help string, labelNames ...string) *Counter {
+	c := &Counter{
+		desc:   desc{metricName: name, help: help, labelNames: labelNames},
+		series: map[string]*counterSeries{},
+	}
+	if len(labelNames) == 0 {
+		c.series[""] = &counterSeries{}
+	}
+	r.register(c)
+	return c
+}
+
+// Inc adds one to the series of labelValues, given in the order of the label names.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds v, which must not be negative, to the series of labelValues.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic(fmt.Sprintf("counter %s cannot decrease", c.metricName))
+	}
+	key := c.key(labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.series[key]
+	if !ok {
+		s = &counterSeries{labelValues: append([]string(nil), labelValues...)}
+		c.series[key] = s
+	}
+	s.value += v
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writeHeader(w, "counter")
+	for _, key := range sortedKeys(c.series) {
+		s := c.series[key]
+		fmt.Fprintf(w, "%s%s %s\n", c.metricName, c.labels(s.labelValues), formatValue(s.value))
+	}
+}
+
+// Histogram counts observations into cumulative buckets per combination of label values.
+type Histogram struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+// NewHistogram registers a histogram with the given upper bucket bounds. The +Inf bucket is implicit.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	if n := len(buckets); n > 0 && math.IsInf(buckets[n-1], 1) {
+		buckets = buckets[:n-1]
+	}
+
+	h := &Histogram{
+		desc:    desc{metricName: name, help: help, labelNames: labelNames},
+		buckets: buckets,
+		series:  map[string]*histogramSeries{},
+	}
+	r.register(h)
+	return h
+}
+
+// Observe records v in the series of labelValues.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	key := h.key(labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{labelValues: append([]string(nil), labelValues...), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.writeHeader(w, "histogram")
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.labels(s.labelValues, "le", formatValue(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.labels(s.labelValues, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.metricName, h.labels(s.labelValues), formatValue(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.metricName, h.labels(s.labelValues), s.count)
+	}
+}
+
+// funcMetric reads its value when the registry is written, for values another component already tracks.
+type funcMetric struct {
+	desc
+	metricType string
+	read       func() float64
+}
+
+// NewGaugeFunc registers a gauge whose value is read from read on every scrape.
+func (r *Registry) NewGaugeFunc(name, help string, read func() float64) {
+	r.register(&funcMetric{desc: desc{metricName: name, help: help}, metricType: "gauge", read: read})
+}
+
+// NewCounterFunc registers a counter whose value is read from read on every scrape.
+func (r *Registry) NewCounterFunc(name, help string, read func() float64) {
+	r.register(&funcMetric{desc: desc{metricName: name, help: help}, metricType: "counter", read: read})
+}
+
+func (m *funcMetric) write(w *bufio.Writer) {
+	m.writeHeader(w, m.metricType)
+	fmt.Fprintf(w, "%s %s\n", m.metricName, formatValue(m.read()))
+}
@@ -0,0 +1,116 @@
+package metrics_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/metrics"
+	"github.com/stretchr/testify/suite"
+)
+
+type RegistryTestSuite struct {
+	suite.Suite
+	sut *metrics.Registry
+}
+
+func (s *RegistryTestSuite) SetupTest() {
+	s.sut = metrics.NewRegistry()
+}
+
+func TestRegistry(t *testing.T) {
+	suite.Run(t, new(RegistryTestSuite))
+}
+
+func (s *RegistryTestSuite) writeText() string {
+	var buf bytes.Buffer
+	s.Require().NoError(s.sut.WriteText(&buf))
+	return buf.String()
+}
+
+func (s *RegistryTestSuite) TestWriteTextShouldWriteCountersSortedByLabelValues() {
+	counter := s.sut.NewCounter("logins_total", "Login attempts.", "result", "reason")
+	counter.Inc("success", "")
+	counter.Inc("failure", "wrong_password")
+	counter.Add(2, "failure", "wrong_password")
+
+	s.Equal(`# HELP logins_total Login attempts.
+# TYPE logins_total counter
+logins_total{result="failure",reason="wrong_password"} 3
+logins_total{result="success",reason=""} 1
+`, s.writeText())
+}
+
+func (s *RegistryTestSuite) TestWriteTextShouldWriteUnlabelledCounterBeforeFirstIncrement() {
+	s.sut.NewCounter("registrations_total", "Registrations.")
+
+	s.Equal(`# HELP registrations_total Registrations.
+# TYPE registrations_total counter
+registrations_total 0
+`, s.writeText())
+}
+
+func (s *RegistryTestSuite) TestWriteTextShouldWriteCumulativeHistogramBuckets() {
+	histogram := s.sut.NewHistogram("duration_seconds", "Durations.", []float64{0.1, 1}, "route")
+	histogram.Observe(0.05, "/a")
+	histogram.Observe(0.1, "/a")
+	histogram.Observe(0.5, "/a")
+	histogram.Observe(3, "/a")
+
+	s.Equal(`# HELP duration_seconds Durations.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{route="/a",le="0.1"} 2
+duration_seconds_bucket{route="/a",le="1"} 3
+duration_seconds_bucket{route="/a",le="+Inf"} 4
+duration_seconds_sum{route="/a"} 3.65
+duration_seconds_count{route="/a"} 4
+`, s.writeText())
+}
+
+func (s *RegistryTestSuite) TestWriteTextShouldReadFuncMetricsAndSortByName() {
+	depth := 3
+	s.sut.NewGaugeFunc("queue_depth", "Queued items.", func() float64 { return float64(depth) })
+	s.sut.NewCounterFunc("db_wait_count_total", "Waits.", func() float64 { return 7 })
+	depth = 5
+
+	s.Equal(`# HELP db_wait_count_total Waits.
+# TYPE db_wait_count_total counter
+db_wait_count_total 7
+# HELP queue_depth Queued items.
+# TYPE queue_depth gauge
+queue_depth 5
+`, s.writeText())
+}
+
+func (s *RegistryTestSuite) TestWriteTextShouldEscapeHelpAndLabelValues() {
+	counter := s.sut.NewCounter("escaped_total", "Back\\slash\nnewline.", "value")
+	counter.Inc("quote\" back\\slash\nnewline")
+
+	s.Equal(`# HELP escaped_total Back\\slash\nnewline.
+# TYPE escaped_total counter
+escaped_total{value="quote\" back\\slash\nnewline"} 1
+`, s.writeText())
+}
+
+func (s *RegistryTestSuite) TestNewCounterGivenDuplicateNameShouldPanic() {
+	s.sut.NewCounter("logins_total", "Login attempts.")
+
+	s.Panics(func() { s.sut.NewCounter("logins_total", "Login attempts.") })
+}
+
+func (s *RegistryTestSuite) TestIncGivenWrongNumberOfLabelValuesShouldPanic() {
+	counter := s.sut.NewCounter("logins_total", "Login attempts.", "result")
+
+	s.Panics(func() { counter.Inc("failure", "wrong_password") })
+}
+
+func (s *RegistryTestSuite) TestHandlerShouldServeTextFormat() {
+	s.sut.NewCounter("registrations_total", "Registrations.")
+	w := httptest.NewRecorder()
+
+	s.sut.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	s.Equal("text/plain; version=0.0.4; charset=utf-8", w.Header().Get("Content-Type"))
+	s.Contains(w.Body.String(), "registrations_total 0\n")
+}
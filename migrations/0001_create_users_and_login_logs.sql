@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS users (
+  id UUID PRIMARY KEY,
+  full_name VARCHAR(60) NOT NULL,
+  phone_number VARCHAR(13) UNIQUE NOT NULL,
+  password_hash TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS login_logs (
+  id UUID PRIMARY KEY,
+  user_id UUID REFERENCES users(id) NOT NULL,
+  login_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS users_id_index ON users(id);
+CREATE INDEX IF NOT EXISTS users_phone_number_index ON users(phone_number);
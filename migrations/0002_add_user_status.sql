@@ -0,0 +1,6 @@
+ALTER TABLE users
+  ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('pending', 'active', 'suspended', 'locked', 'deleted')),
+  ADD COLUMN status_changed_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX users_status_index ON users(status);
@@ -0,0 +1,7 @@
+-- Anonymized accounts release their phone number so it can be registered again.
+ALTER TABLE users
+  ALTER COLUMN phone_number DROP NOT NULL,
+  ADD COLUMN tokens_revoked_at TIMESTAMPTZ,
+  ADD COLUMN anonymized_at TIMESTAMPTZ;
+
+CREATE INDEX login_logs_user_id_index ON login_logs(user_id);
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+  id UUID PRIMARY KEY,
+  sequence BIGSERIAL UNIQUE NOT NULL,
+  actor_type VARCHAR(16) NOT NULL CHECK (actor_type IN ('user', 'admin', 'system')),
+  actor_id UUID,
+  target_user_id UUID REFERENCES users(id),
+  action VARCHAR(64) NOT NULL,
+  changes JSONB NOT NULL,
+  ip_address TEXT NOT NULL,
+  request_id TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  previous_hash CHAR(64) NOT NULL,
+  hash CHAR(64) UNIQUE NOT NULL
+);
+
+CREATE INDEX audit_events_target_user_id_index ON audit_events(target_user_id, sequence);
+CREATE INDEX audit_events_actor_id_index ON audit_events(actor_id, sequence);
+
+-- Audit events are append-only, the hash chain detects anything that bypasses this trigger.
+CREATE OR REPLACE FUNCTION reject_audit_event_change() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+  BEFORE UPDATE OR DELETE ON audit_events
+  FOR EACH ROW EXECUTE FUNCTION reject_audit_event_change();
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS outbox_events (
+  id UUID PRIMARY KEY,
+  event_type VARCHAR(64) NOT NULL,
+  aggregate_id UUID NOT NULL,
+  payload JSONB NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_error TEXT,
+  published_at TIMESTAMPTZ
+);
+
+CREATE INDEX outbox_events_pending_index ON outbox_events(next_attempt_at) WHERE published_at IS NULL;
@@ -0,0 +1,12 @@
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS email VARCHAR(254),
+  ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS date_of_birth DATE,
+  ADD COLUMN IF NOT EXISTS locale VARCHAR(35),
+  ADD COLUMN IF NOT EXISTS timezone VARCHAR(64);
+
+-- Addresses differing only in case belong to the same mailbox in practice.
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_unique_index ON users(lower(email));
+
+-- Serves the last login time derived from the login log.
+CREATE INDEX IF NOT EXISTS login_logs_user_id_login_at_index ON login_logs(user_id, login_at);
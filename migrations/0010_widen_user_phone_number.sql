@@ -0,0 +1,2 @@
+-- E.164 numbers have up to 15 digits after the +.
+ALTER TABLE users ALTER COLUMN phone_number TYPE VARCHAR(16);
@@ -0,0 +1,3 @@
+-- Full names are limited to 60 user-perceived characters, which can take more than 60 code points when
+-- they carry combining marks. The service caps them at 240 code points.
+ALTER TABLE users ALTER COLUMN full_name TYPE VARCHAR(240);
@@ -0,0 +1,8 @@
+-- Phone numbers are encrypted by the service, which holds the keys, and found through a keyed hash of the
+-- number (blind index). Existing numbers stay in phone_number until the re-encryption job has moved them.
+ALTER TABLE users
+  ADD COLUMN phone_number_ciphertext BYTEA,
+  ADD COLUMN phone_number_key_id TEXT,
+  ADD COLUMN phone_number_hash BYTEA;
+
+CREATE UNIQUE INDEX users_phone_number_hash_unique_index ON users(phone_number_hash);
@@ -0,0 +1,16 @@
+-- Responses to requests sent with an Idempotency-Key header, replayed to retries until they expire.
+-- status_code is NULL while the first request is still being handled, and the row expires when that
+-- request is abandoned.
+CREATE TABLE idempotency_keys (
+  principal TEXT NOT NULL,
+  idempotency_key TEXT NOT NULL,
+  fingerprint BYTEA NOT NULL,
+  status_code INT,
+  content_type TEXT,
+  body BYTEA,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (principal, idempotency_key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_index ON idempotency_keys(expires_at);
@@ -0,0 +1,6 @@
+-- User events no longer carry phone numbers, remove them from the events stored so far. Webhook deliveries
+-- hold the whole envelope, with the event payload under "payload".
+UPDATE outbox_events SET payload = payload - 'phone_number' WHERE payload ? 'phone_number';
+
+UPDATE webhook_deliveries SET payload = jsonb_set(payload, '{payload}', (payload -> 'payload') - 'phone_number')
+  WHERE payload -> 'payload' ? 'phone_number';
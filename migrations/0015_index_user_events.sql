@@ -0,0 +1,3 @@
+-- Anonymization scrubs the outbox events and webhook deliveries about a user.
+CREATE INDEX outbox_events_aggregate_id_index ON outbox_events(aggregate_id);
+CREATE INDEX webhook_deliveries_aggregate_id_index ON webhook_deliveries((payload ->> 'aggregate_id'));
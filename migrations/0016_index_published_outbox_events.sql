@@ -0,0 +1,2 @@
+-- Published events are deleted once they are older than the retention.
+CREATE INDEX outbox_events_published_at_index ON outbox_events(published_at) WHERE published_at IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Numbers stored before they were normalized to E.164 can be in another notation, e.g. +620812345678. The
+-- re-encryption job rewrites them and sets phone_number_normalized. Existing rows start out unnormalized,
+-- rows inserted from now on hold E.164 numbers.
+ALTER TABLE users ADD COLUMN phone_number_normalized BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ALTER COLUMN phone_number_normalized SET DEFAULT TRUE;
@@ -0,0 +1,3 @@
+-- Numbers the re-encryption job cannot parse stay as stored. The flag keeps the job from picking them up
+-- again every run; it is cleared when the user changes the number.
+ALTER TABLE users ADD COLUMN phone_number_unparseable BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,130 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// advisoryLockKey serialises migrations between service instances starting at the same time.
+const advisoryLockKey = 7283910457
+
+type migration struct {
+	version int
+	name    string
+	query   string
+}
+
+func load() ([]migration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]migration, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+
+		prefix, _, found := strings.Cut(name, "_")
+		if !found {
+			return nil, fmt.Errorf("migration %s is not prefixed with a version", name)
+		}
+
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has an invalid version: %w", name, err)
+		}
+
+		query, err := files.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, migration{version: version, name: name, query: string(query)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+	return migrations, nil
+}
+
+// LatestVersion returns the version of the newest embedded migration.
+func LatestVersion() int {
+	migrations, err := load()
+	if err != nil || len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].version
+}
+
+// CurrentVersion returns the version of the newest migration applied to the database.
+func CurrentVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var version int
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations;`).Scan(&version); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// Migrate applies every embedded migration that has not been recorded in schema_migrations yet.
+// Each migration runs in its own transaction.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	migrations, err := load()
+	if err != nil {
+		return err
+	}
+
+	createQuery := `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	);`
+	if _, err := db.ExecContext(ctx, createQuery); err != nil {
+		return fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+
+	for _, m := range migrations {
+		if err := apply(ctx, db, m); err != nil {
+			return fmt.Errorf("error applying migration %s: %w", m.name, err)
+		}
+	}
+	return nil
+}
+
+func apply(ctx context.Context, db *sql.DB, m migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1);`, advisoryLockKey); err != nil {
+		return err
+	}
+
+	var applied bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1);`, m.version).Scan(&applied); err != nil {
+		return err
+	}
+	if applied {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, m.query); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2);`, m.version, m.name); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
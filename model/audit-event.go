@@ -0,0 +1,99 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditActorType string
+
+const (
+	AuditActorUser   AuditActorType = "user"
+	AuditActorAdmin  AuditActorType = "admin"
+	AuditActorSystem AuditActorType = "system"
+)
+
+type AuditAction string
+
+const (
+	AuditActionUserRegistered    AuditAction = "user.registered"
+	AuditActionProfileUpdated    AuditAction = "user.profile_updated"
+	AuditActionStatusChanged     AuditAction = "user.status_changed"
+	AuditActionAccountDeleted    AuditAction = "user.account_deleted"
+	AuditActionAccountRestored   AuditAction = "user.account_restored"
+	AuditActionAccountAnonymized AuditAction = "user.account_anonymized"
+)
+
+// AuditGenesisHash is the previous hash of the first event in the chain.
+var AuditGenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// AuditChange holds the value of one field before and after a change. Nil means the field had no value.
+// Changes to personal data only have Redacted set, their values are not recorded.
+type AuditChange struct {
+	Before   *string `json:"before"`
+	After    *string `json:"after"`
+	Redacted bool    `json:"redacted,omitempty"`
+}
+
+type AuditEvent struct {
+	ID           uuid.UUID
+	Sequence     int64
+	ActorType    AuditActorType
+	ActorID      *uuid.UUID
+	TargetUserID *uuid.UUID
+	Action       AuditAction
+	Changes      map[string]AuditChange
+	IPAddress    string
+	RequestID    string
+	CreatedAt    time.Time
+	PreviousHash string
+	Hash         string
+}
+
+type AuditEventFilter struct {
+	TargetUserID *uuid.UUID
+	ActorID      *uuid.UUID
+	Action       *AuditAction
+	// BeforeSequence and AfterSequence bound the returned events, zero means unbounded.
+	BeforeSequence int64
+	AfterSequence  int64
+	Ascending      bool
+	Limit          int
+}
+
+// ComputeHash returns the chain hash of the event: the SHA-256 of the previous hash followed by a
+// canonical encoding of every recorded field. The sequence is left out since it is assigned by the
+// database after the hash has been computed.
+func (e AuditEvent) ComputeHash() string {
+	payload, _ := json.Marshal(struct {
+		ID           uuid.UUID              `json:"id"`
+		ActorType    AuditActorType         `json:"actor_type"`
+		ActorID      *uuid.UUID             `json:"actor_id"`
+		TargetUserID *uuid.UUID             `json:"target_user_id"`
+		Action       AuditAction            `json:"action"`
+		Changes      map[string]AuditChange `json:"changes"`
+		IPAddress    string                 `json:"ip_address"`
+		RequestID    string                 `json:"request_id"`
+		CreatedAt    string                 `json:"created_at"`
+	}{
+		ID:           e.ID,
+		ActorType:    e.ActorType,
+		ActorID:      e.ActorID,
+		TargetUserID: e.TargetUserID,
+		Action:       e.Action,
+		Changes:      e.Changes,
+		IPAddress:    e.IPAddress,
+		RequestID:    e.RequestID,
+		CreatedAt:    e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+
+	hash := sha256.New()
+	hash.Write([]byte(e.PreviousHash))
+	hash.Write(payload)
+	return hex.EncodeToString(hash.Sum(nil))
+}
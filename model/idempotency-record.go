@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// IdempotencyRecord keeps the response to a request sent with an Idempotency-Key header, so that retries of
+// the request get the same response.
+type IdempotencyRecord struct {
+	// Principal identifies the caller, the same key sent by different callers is a different key.
+	Principal string
+	Key       string
+	// Fingerprint identifies the request the key was first sent with.
+	Fingerprint []byte
+	// StatusCode is zero while the first request is still being handled.
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time
+}
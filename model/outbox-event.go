@@ -0,0 +1,51 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxEventType string
+
+const (
+	OutboxEventUserRegistered         OutboxEventType = "user.registered"
+	OutboxEventUserProfileUpdated     OutboxEventType = "user.profile_updated"
+	OutboxEventUserPhoneNumberChanged OutboxEventType = "user.phone_number_changed"
+	OutboxEventUserStatusChanged      OutboxEventType = "user.status_changed"
+	OutboxEventUserAnonymized         OutboxEventType = "user.anonymized"
+)
+
+func (t OutboxEventType) IsValid() bool {
+	switch t {
+	case OutboxEventUserRegistered, OutboxEventUserProfileUpdated, OutboxEventUserPhoneNumberChanged, OutboxEventUserStatusChanged, OutboxEventUserAnonymized:
+		return true
+	}
+	return false
+}
+
+// OutboxEvent is a domain event stored in the same transaction as the change it describes and
+// delivered to other services afterwards.
+type OutboxEvent struct {
+	ID          uuid.UUID
+	EventType   OutboxEventType
+	AggregateID uuid.UUID
+	Payload     json.RawMessage
+	CreatedAt   time.Time
+	Attempts    int
+}
+
+// PersonalUserEventFields lists the fields of UserEventPayload that hold personal data. Anonymizing a user
+// removes them from the events stored about it.
+var PersonalUserEventFields = []string{"full_name", "email"}
+
+// UserEventPayload is the payload of every user event. Fields that did not change are left out. Phone
+// numbers are never included, consumers look them up by user ID.
+type UserEventPayload struct {
+	UserID     uuid.UUID   `json:"user_id"`
+	FullName   *string     `json:"full_name,omitempty"`
+	Email      *string     `json:"email,omitempty"`
+	Status     *UserStatus `json:"status,omitempty"`
+	OccurredAt time.Time   `json:"occurred_at"`
+}
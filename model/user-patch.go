@@ -0,0 +1,59 @@
+package model
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// UserField names a user attribute that can be changed through a UserPatch.
+type UserField string
+
+const (
+	UserFieldFullName     UserField = "full_name"
+	UserFieldPhoneNumber  UserField = "phone_number"
+	UserFieldPasswordHash UserField = "password_hash"
+	UserFieldEmail        UserField = "email"
+	UserFieldDateOfBirth  UserField = "date_of_birth"
+	UserFieldLocale       UserField = "locale"
+	UserFieldTimezone     UserField = "timezone"
+	UserFieldAvatarKey    UserField = "avatar_key"
+)
+
+// UserPatch is a partial update of a user. Only the fields present in Fields are written, a field mapped
+// to nil is set to NULL.
+type UserPatch struct {
+	UserID uuid.UUID
+	Fields map[UserField]interface{}
+	// Version is the version the patch is based on, zero skips the check.
+	Version int64
+}
+
+func NewUserPatch(userID uuid.UUID) UserPatch {
+	return UserPatch{
+		UserID: userID,
+		Fields: map[UserField]interface{}{},
+	}
+}
+
+func (p UserPatch) Set(field UserField, value interface{}) {
+	p.Fields[field] = value
+}
+
+// StringValue returns the new value of a string field. ok is false when the field is not patched or cleared.
+func (p UserPatch) StringValue(field UserField) (value string, ok bool) {
+	value, ok = p.Fields[field].(string)
+	return value, ok
+}
+
+// SortedFields returns the patched fields in a stable order, so that equal patches produce equal queries.
+func (p UserPatch) SortedFields() []UserField {
+	fields := make([]UserField, 0, len(p.Fields))
+	for field := range p.Fields {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i] < fields[j]
+	})
+	return fields
+}
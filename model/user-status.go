@@ -0,0 +1,35 @@
+package model
+
+type UserStatus string
+
+const (
+	UserStatusPending   UserStatus = "pending"
+	UserStatusActive    UserStatus = "active"
+	UserStatusSuspended UserStatus = "suspended"
+	UserStatusLocked    UserStatus = "locked"
+	UserStatusDeleted   UserStatus = "deleted"
+)
+
+// userStatusTransitions lists, for every status, the statuses an account may move to next.
+// A deleted account can only become active again until it is anonymized.
+var userStatusTransitions = map[UserStatus][]UserStatus{
+	UserStatusPending:   {UserStatusActive, UserStatusDeleted},
+	UserStatusActive:    {UserStatusSuspended, UserStatusLocked, UserStatusDeleted},
+	UserStatusSuspended: {UserStatusActive, UserStatusDeleted},
+	UserStatusLocked:    {UserStatusActive, UserStatusDeleted},
+	UserStatusDeleted:   {UserStatusActive},
+}
+
+func (s UserStatus) IsValid() bool {
+	_, ok := userStatusTransitions[s]
+	return ok
+}
+
+func (s UserStatus) CanTransitionTo(next UserStatus) bool {
+	for _, allowed := range userStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
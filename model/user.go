@@ -1,12 +1,59 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
 type User struct {
-	ID           uuid.UUID
-	PhoneNumber  string
-	FullName     string
-	PasswordHash string
+	ID              uuid.UUID
+	PhoneNumber     string
+	FullName        string
+	PasswordHash    string
+	Status          UserStatus
+	StatusChangedAt time.Time
+	// TokensRevokedAt invalidates every access token issued before it.
+	TokensRevokedAt *time.Time
+	AnonymizedAt    *time.Time
+	Email           *string
+	// EmailVerifiedAt is cleared whenever the email changes. Nothing sets it yet, see the README.
+	EmailVerifiedAt *time.Time
+	// DateOfBirth only carries a date, its time is midnight UTC.
+	DateOfBirth *time.Time
+	// Locale is a canonical BCP 47 language tag.
+	Locale *string
+	// Timezone is an IANA time zone name.
+	Timezone *string
+	// AvatarKey is the blob key prefix the avatar thumbnails are stored under.
+	AvatarKey *string
+	// LastLoginAt is derived from the login log.
+	LastLoginAt *time.Time
+	// Version is incremented on every write. Update only applies when it matches the stored version,
+	// zero skips the check.
+	Version   int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// UserAccessState is the part of a user deciding whether its access tokens are accepted.
+type UserAccessState struct {
+	Status          UserStatus
+	TokensRevokedAt *time.Time
+}
+
+// PhoneNumberReencryption is the outcome of re-encrypting a batch of phone numbers.
+type PhoneNumberReencryption struct {
+	// Size is the number of users in the batch, LastUserID the highest ID among them.
+	Size        int
+	LastUserID  uuid.UUID
+	Reencrypted int
+	// Normalized lists the users whose phone number was rewritten in E.164.
+	Normalized []uuid.UUID
+	// Conflicts lists the users whose phone number is already used by another user. Their numbers stay as they
+	// are until the duplicate has been resolved.
+	Conflicts []uuid.UUID
+	// Invalid lists the users whose phone number was found not to parse in this batch. They are encrypted as
+	// stored and flagged, so they are not reported again; their users log in with the number as stored.
+	Invalid []uuid.UUID
 }
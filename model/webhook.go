@@ -0,0 +1,59 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookSubscription is a partner endpoint that receives signed user events.
+type WebhookSubscription struct {
+	ID     uuid.UUID
+	URL    string
+	Secret string
+	// EventTypes lists the delivered event types, every event type is delivered when it is empty.
+	EventTypes []OutboxEventType
+	Active     bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending      WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered    WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryDeadLettered WebhookDeliveryStatus = "dead_lettered"
+)
+
+// WebhookDelivery is one event queued for one subscription, together with the outcome of its last attempt.
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	EventID        uuid.UUID
+	EventType      OutboxEventType
+	Payload        json.RawMessage
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode *int
+	LastError      *string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+type WebhookDeliveryFilter struct {
+	SubscriptionID uuid.UUID
+	Status         *WebhookDeliveryStatus
+	// Before only returns deliveries created before the given time, it is used for paging.
+	Before *time.Time
+	Limit  int
+}
+
+// WebhookDeliveryResult is the outcome of a single delivery attempt.
+type WebhookDeliveryResult struct {
+	// StatusCode is nil when no response was received.
+	StatusCode *int
+	Error      string
+}
@@ -0,0 +1,170 @@
+// Package phonenumber parses phone numbers written in national or international notation and normalizes
+// them to E.164. The numbering rules of the supported countries live in countries.json.
+package phonenumber
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultRegion is the region of the numbers the service stores when they are written without an
+	// international prefix.
+	DefaultRegion = "ID"
+
+	// maxE164Digits is the maximum number of digits of an E.164 number, country calling code included.
+	maxE164Digits = 15
+)
+
+var (
+	ErrInvalidCharacters  = errors.New("phone number must only contain digits, spaces, dashes, dots, parentheses and a leading +")
+	ErrUnsupportedCountry = errors.New("phone number country is not supported")
+	ErrInvalidLength      = errors.New("phone number length is invalid for its country")
+	ErrInvalidPrefix      = errors.New("phone number prefix is invalid for its country")
+)
+
+// Country holds the numbering rules of one country.
+type Country struct {
+	// Region is the ISO 3166-1 alpha-2 code of the country.
+	Region      string `json:"region"`
+	Name        string `json:"name"`
+	CallingCode string `json:"calling_code"`
+	// TrunkPrefix is dialled before the national number within the country, e.g. the 0 of 0812.
+	TrunkPrefix string `json:"trunk_prefix"`
+	// MinLength and MaxLength bound the digits of the national number, without trunk prefix.
+	MinLength int `json:"min_length"`
+	MaxLength int `json:"max_length"`
+	// LeadingDigits lists the prefixes a national number may start with, empty allows any.
+	LeadingDigits []string `json:"leading_digits"`
+}
+
+// PhoneNumber is a parsed and validated phone number.
+type PhoneNumber struct {
+	Country        Country
+	NationalNumber string
+}
+
+// E164 returns the canonical form of the number, e.g. +6281234567890.
+func (n PhoneNumber) E164() string {
+	return "+" + n.Country.CallingCode + n.NationalNumber
+}
+
+//go:embed countries.json
+var countriesJSON []byte
+
+var countries = mustLoadCountries(countriesJSON)
+
+func mustLoadCountries(data []byte) map[string]Country {
+	var list []Country
+	if err := json.Unmarshal(data, &list); err != nil {
+		panic(fmt.Sprintf("phonenumber: invalid countries.json: %s", err.Error()))
+	}
+
+	result := map[string]Country{}
+	for _, country := range list {
+		result[country.Region] = country
+	}
+	return result
+}
+
+// LookupCountry returns the rules of the country with the given region code.
+func LookupCountry(region string) (Country, bool) {
+	country, ok := countries[strings.ToUpper(region)]
+	return country, ok
+}
+
+// Parse parses a number in international notation (+62 812..., 0062 812...) or, when it has no
+// international prefix, in the national notation of defaultRegion (0812...). Formatting characters are
+// ignored, so every notation of a number yields the same E164.
+func Parse(input string, defaultRegion string) (PhoneNumber, error) {
+	digits, international, err := stripFormatting(input)
+	if err != nil {
+		return PhoneNumber{}, err
+	}
+
+	var country Country
+	if international {
+		var ok bool
+		if country, digits, ok = splitCallingCode(digits); !ok {
+			return PhoneNumber{}, ErrUnsupportedCountry
+		}
+	} else {
+		var ok bool
+		if country, ok = LookupCountry(defaultRegion); !ok {
+			return PhoneNumber{}, ErrUnsupportedCountry
+		}
+	}
+
+	// The trunk prefix is often kept after the calling code as well, e.g. +62 0812...
+	if country.TrunkPrefix != "" {
+		digits = strings.TrimPrefix(digits, country.TrunkPrefix)
+	}
+
+	if len(digits) < country.MinLength || len(digits) > country.MaxLength || len(country.CallingCode)+len(digits) > maxE164Digits {
+		return PhoneNumber{}, ErrInvalidLength
+	}
+
+	if !hasAnyPrefix(digits, country.LeadingDigits) {
+		return PhoneNumber{}, ErrInvalidPrefix
+	}
+
+	return PhoneNumber{Country: country, NationalNumber: digits}, nil
+}
+
+// stripFormatting removes the formatting characters people commonly type and reports whether the number
+// carries an international prefix, either + or 00.
+func stripFormatting(input string) (string, bool, error) {
+	input = strings.TrimSpace(input)
+
+	international := strings.HasPrefix(input, "+")
+	if international {
+		input = input[1:]
+	}
+
+	var digits strings.Builder
+	for _, c := range input {
+		switch {
+		case c >= '0' && c <= '9':
+			digits.WriteRune(c)
+		case c == ' ' || c == '-' || c == '.' || c == '(' || c == ')':
+		default:
+			return "", false, ErrInvalidCharacters
+		}
+	}
+
+	result := digits.String()
+	if !international && strings.HasPrefix(result, "00") {
+		return result[2:], true, nil
+	}
+	if result == "" {
+		return "", false, ErrInvalidLength
+	}
+	return result, international, nil
+}
+
+// splitCallingCode finds the supported country whose calling code starts digits. Calling codes are prefix
+// free, so at most one country matches.
+func splitCallingCode(digits string) (Country, string, bool) {
+	for _, country := range countries {
+		if strings.HasPrefix(digits, country.CallingCode) {
+			return country, digits[len(country.CallingCode):], true
+		}
+	}
+	return Country{}, "", false
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package phonenumber_test
+
+import (
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/phonenumber"
+	"github.com/stretchr/testify/suite"
+)
+
+type PhoneNumberTestSuite struct {
+	suite.Suite
+}
+
+func TestPhoneNumber(t *testing.T) {
+	suite.Run(t, new(PhoneNumberTestSuite))
+}
+
+func (s *PhoneNumberTestSuite) TestParseGivenNotationsOfTheSameNumberShouldReturnTheSameE164() {
+	for _, input := range []string{"+6281234567890", "+62 812-3456-7890", "081234567890", "0812.3456.7890", "(0812) 3456 7890", "006281234567890", "+62 0812 3456 7890", "+6208123456 7890"} {
+		number, err := phonenumber.Parse(input, "ID")
+
+		s.Require().NoError(err, input)
+		s.Equal("+6281234567890", number.E164(), input)
+		s.Equal("ID", number.Country.Region, input)
+	}
+}
+
+func (s *PhoneNumberTestSuite) TestParseGivenInternationalNumberShouldUseItsCountry() {
+	malaysian, err := phonenumber.Parse("+60 12-345 6789", "ID")
+	s.Require().NoError(err)
+	s.Equal("+60123456789", malaysian.E164())
+
+	singaporean, err := phonenumber.Parse("+65 9123 4567", "ID")
+	s.Require().NoError(err)
+	s.Equal("+6591234567", singaporean.E164())
+}
+
+func (s *PhoneNumberTestSuite) TestParseGivenNationalNumberShouldUseDefaultRegion() {
+	number, err := phonenumber.Parse("9123 4567", "SG")
+
+	s.Require().NoError(err)
+	s.Equal("+6591234567", number.E164())
+}
+
+func (s *PhoneNumberTestSuite) TestParseGivenInvalidNumberShouldReturnError() {
+	testCases := map[string]error{
+		"+62 812 ab":        phonenumber.ErrInvalidCharacters,
+		"+1 202 555 0100":   phonenumber.ErrUnsupportedCountry,
+		"+65 1234 5678":     phonenumber.ErrInvalidPrefix,
+		"+65 9123 456":      phonenumber.ErrInvalidLength,
+		"0812 3456 7890 12": phonenumber.ErrInvalidLength,
+		"":                  phonenumber.ErrInvalidLength,
+	}
+
+	for input, expected := range testCases {
+		_, err := phonenumber.Parse(input, "ID")
+
+		s.Equal(expected, err, input)
+	}
+}
@@ -0,0 +1,28 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/SawitProRecruitment/UserService/model"
+)
+
+// MultiPublisher hands every event to several publishers in order. When one of them fails the event is
+// retried for all of them, so every publisher has to tolerate duplicates.
+type MultiPublisher struct {
+	publishers []Publisher
+}
+
+func NewMultiPublisher(publishers ...Publisher) *MultiPublisher {
+	return &MultiPublisher{
+		publishers: publishers,
+	}
+}
+
+func (p *MultiPublisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
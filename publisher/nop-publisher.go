@@ -0,0 +1,18 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/SawitProRecruitment/UserService/model"
+)
+
+// NopPublisher drops every event, for deployments where only the webhook subscriptions consume them.
+type NopPublisher struct{}
+
+func NewNopPublisher() NopPublisher {
+	return NopPublisher{}
+}
+
+func (NopPublisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	return nil
+}
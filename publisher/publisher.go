@@ -0,0 +1,35 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/google/uuid"
+)
+
+// Publisher delivers outbox events to other services. Delivery is at-least-once, consumers
+// deduplicate on the event id.
+type Publisher interface {
+	Publish(ctx context.Context, event model.OutboxEvent) error
+}
+
+// Envelope is the message shape every publisher sends.
+type Envelope struct {
+	ID          uuid.UUID             `json:"id"`
+	Type        model.OutboxEventType `json:"type"`
+	AggregateID uuid.UUID             `json:"aggregate_id"`
+	OccurredAt  time.Time             `json:"occurred_at"`
+	Payload     json.RawMessage       `json:"payload"`
+}
+
+func newEnvelope(event model.OutboxEvent) Envelope {
+	return Envelope{
+		ID:          event.ID,
+		Type:        event.EventType,
+		AggregateID: event.AggregateID,
+		OccurredAt:  event.CreatedAt.UTC(),
+		Payload:     event.Payload,
+	}
+}
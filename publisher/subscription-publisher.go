@@ -0,0 +1,33 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+)
+
+// SubscriptionPublisher fans events out to the webhook subscriptions registered by admins. It only queues
+// one delivery per subscription, the deliveries themselves are sent by the webhook dispatcher.
+type SubscriptionPublisher struct {
+	webhookRepository repository.WebhookRepository
+}
+
+func NewSubscriptionPublisher(webhookRepository repository.WebhookRepository) *SubscriptionPublisher {
+	return &SubscriptionPublisher{
+		webhookRepository: webhookRepository,
+	}
+}
+
+func (p *SubscriptionPublisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	body, err := json.Marshal(newEnvelope(event))
+	if err != nil {
+		return err
+	}
+
+	if err := p.webhookRepository.EnqueueDeliveries(ctx, event.ID, event.EventType, body); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/model"
+)
+
+type WebhookPublisherOptions struct {
+	URL string
+	// Timeout bounds a single delivery attempt.
+	Timeout time.Duration
+}
+
+// WebhookPublisher POSTs every event to a single URL. Any non-2xx response is treated as a failed delivery.
+type WebhookPublisher struct {
+	opts   *WebhookPublisherOptions
+	client *http.Client
+}
+
+func NewWebhookPublisher(opts WebhookPublisherOptions) *WebhookPublisher {
+	return &WebhookPublisher{
+		opts:   &opts,
+		client: &http.Client{Timeout: opts.Timeout},
+	}
+}
+
+func (p *WebhookPublisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	body, err := json.Marshal(newEnvelope(event))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", event.ID.String())
+	req.Header.Set("X-Event-Type", string(event.EventType))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/SawitProRecruitment/UserService/model"
+)
+
+// WriterPublisher writes every event as one JSON line. The events are written as they are, personal data
+// included, so the output has to be protected like the database.
+type WriterPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+	// file is closed by Close when the publisher owns it.
+	file *os.File
+}
+
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{
+		w: w,
+	}
+}
+
+func NewStdoutPublisher() *WriterPublisher {
+	return NewWriterPublisher(os.Stdout)
+}
+
+// NewFilePublisher appends events to the file at path, creating it when needed.
+func NewFilePublisher(path string) (*WriterPublisher, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewWriterPublisher(file)
+	p.file = file
+	return p, nil
+}
+
+// Close closes the file of a publisher created by NewFilePublisher, other writers are left open.
+func (p *WriterPublisher) Close() error {
+	if p.file == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.file.Close()
+}
+
+func (p *WriterPublisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	line, err := json.Marshal(newEnvelope(event))
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err = p.w.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/google/uuid"
+)
+
+// auditChainLockKey serialises appends so that every event links to the one inserted right before it.
+// Inside an ambient transaction the lock is held until that transaction ends.
+const auditChainLockKey = 4150632871
+
+type AuditEventRepositoryImplOptions struct {
+	DB           *sql.DB
+	QueryTimeout time.Duration
+	// Replicas serve the read-only queries when set.
+	Replicas *ReplicaRouter
+}
+
+type AuditEventRepositoryImpl struct {
+	opts *AuditEventRepositoryImplOptions
+}
+
+func NewAuditEventRepositoryImpl(opts AuditEventRepositoryImplOptions) *AuditEventRepositoryImpl {
+	return &AuditEventRepositoryImpl{
+		opts: &opts,
+	}
+}
+
+// Append links the event to the end of the hash chain and stores it.
+func (r *AuditEventRepositoryImpl) Append(ctx context.Context, event model.AuditEvent) (model.AuditEvent, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	event.ID = uuid.New()
+	// Postgres keeps microseconds, truncating here keeps the hash verifiable after a round trip.
+	event.CreatedAt = event.CreatedAt.Truncate(time.Microsecond)
+	if event.Changes == nil {
+		event.Changes = map[string]model.AuditChange{}
+	}
+
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return model.AuditEvent{}, common.WrapError(common.ErrUnexpectedError, err, "error encoding audit event changes")
+	}
+
+	query := `INSERT INTO audit_events (id, actor_type, actor_id, target_user_id, action, changes, ip_address, request_id, created_at, previous_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING sequence;`
+
+	err = withTx(ctx, r.opts.DB, func(tx dbConn) error {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1);`, auditChainLockKey); err != nil {
+			return err
+		}
+
+		event.PreviousHash = model.AuditGenesisHash
+		if err := tx.QueryRowContext(ctx, `SELECT hash FROM audit_events ORDER BY sequence DESC LIMIT 1;`).Scan(&event.PreviousHash); err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		event.Hash = event.ComputeHash()
+
+		return tx.QueryRowContext(ctx, query, event.ID.String(), event.ActorType, event.ActorID, event.TargetUserID, event.Action, changes,
+			event.IPAddress, event.RequestID, event.CreatedAt, event.PreviousHash, event.Hash).Scan(&event.Sequence)
+	})
+	if err != nil {
+		return model.AuditEvent{}, databaseError(err)
+	}
+	return event, nil
+}
+
+// Find returns the events matching the filter, newest first unless the filter asks for ascending order.
+func (r *AuditEventRepositoryImpl) Find(ctx context.Context, filter model.AuditEventFilter) ([]model.AuditEvent, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query, args := r.constructFindQueryAndArgs(filter)
+
+	var events []model.AuditEvent
+	var errDecode *common.CustomError
+	err := readOnly(ctx, r.opts.Replicas, r.opts.DB, func(db dbConn) error {
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		events = []model.AuditEvent{}
+		for rows.Next() {
+			var event model.AuditEvent
+			var changes []byte
+
+			if err := rows.Scan(&event.ID, &event.Sequence, &event.ActorType, &event.ActorID, &event.TargetUserID, &event.Action, &changes,
+				&event.IPAddress, &event.RequestID, &event.CreatedAt, &event.PreviousHash, &event.Hash); err != nil {
+				return err
+			}
+
+			if err := json.Unmarshal(changes, &event.Changes); err != nil {
+				errDecode = common.WrapError(common.ErrUnexpectedError, err, "error decoding audit event changes")
+				return errDecode
+			}
+			events = append(events, event)
+		}
+		return rows.Err()
+	})
+	if errDecode != nil {
+		return nil, errDecode
+	}
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	return events, nil
+}
+
+func (r *AuditEventRepositoryImpl) constructFindQueryAndArgs(filter model.AuditEventFilter) (string, []interface{}) {
+	query := `SELECT id, sequence, actor_type, actor_id, target_user_id, action, changes, ip_address, request_id, created_at, previous_hash, hash
+		FROM audit_events WHERE TRUE`
+	args := []interface{}{}
+
+	if filter.TargetUserID != nil {
+		args = append(args, filter.TargetUserID.String())
+		query += fmt.Sprintf(" AND target_user_id = $%d", len(args))
+	}
+
+	if filter.ActorID != nil {
+		args = append(args, filter.ActorID.String())
+		query += fmt.Sprintf(" AND actor_id = $%d", len(args))
+	}
+
+	if filter.Action != nil {
+		args = append(args, *filter.Action)
+		query += fmt.Sprintf(" AND action = $%d", len(args))
+	}
+
+	if filter.BeforeSequence > 0 {
+		args = append(args, filter.BeforeSequence)
+		query += fmt.Sprintf(" AND sequence < $%d", len(args))
+	}
+
+	if filter.AfterSequence > 0 {
+		args = append(args, filter.AfterSequence)
+		query += fmt.Sprintf(" AND sequence > $%d", len(args))
+	}
+
+	if filter.Ascending {
+		query += " ORDER BY sequence ASC"
+	} else {
+		query += " ORDER BY sequence DESC"
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" LIMIT $%d;", len(args))
+
+	return query, args
+}
@@ -0,0 +1,45 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditEventRepositoryTestSuite struct {
+	suite.Suite
+	db  *fakeDatabase
+	sut *repository.AuditEventRepositoryImpl
+}
+
+func (s *AuditEventRepositoryTestSuite) SetupTest() {
+	s.db = &fakeDatabase{}
+	s.sut = repository.NewAuditEventRepositoryImpl(repository.AuditEventRepositoryImplOptions{
+		DB: sql.OpenDB(s.db),
+	})
+}
+
+func TestAuditEventRepository(t *testing.T) {
+	suite.Run(t, new(AuditEventRepositoryTestSuite))
+}
+
+func (s *AuditEventRepositoryTestSuite) TestFindGivenCorruptChangesShouldReturnUnexpectedError() {
+	s.db.results = map[string][][]driver.Value{
+		"FROM audit_events": {{uuid.NewString(), int64(1), string(model.AuditActorUser), nil, nil, string(model.AuditActionProfileUpdated),
+			[]byte("not json"), "", "", time.Now(), model.AuditGenesisHash, "hash"}},
+	}
+
+	_, err := s.sut.Find(context.Background(), model.AuditEventFilter{Limit: 10})
+
+	s.Require().NotNil(err)
+	s.Equal(common.ErrUnexpectedError, err.ErrType)
+	s.Equal("error decoding audit event changes", err.Message)
+}
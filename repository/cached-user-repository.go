@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/cache"
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/logging"
+	"github.com/SawitProRecruitment/UserService/metrics"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/google/uuid"
+)
+
+const (
+	userCacheKeyPrefix = "user:id:"
+
+	// userGenerationStripes is the number of generation counters users are spread over. Users sharing a
+	// counter only skip caching a load now and then.
+	userGenerationStripes = 256
+)
+
+var userCacheLookupsTotal = metrics.Default.NewCounter("user_cache_lookups_total",
+	"User lookups by whether the user cache had them.", "result")
+
+type CachedUserRepositoryOptions struct {
+	UserRepository UserRepository
+	Cache          cache.Cache
+	// TTL bounds how stale a cached user gets when an invalidation is missed, such as when another instance
+	// with its own in-process cache changes the user. LastLoginAt, which changes without going through the
+	// repository, lags behind by up to TTL as well.
+	TTL time.Duration
+	// LoadTimeout bounds a load shared by concurrent lookups, which does not end with the request that
+	// started it. Zero leaves it unbounded.
+	LoadTimeout time.Duration
+}
+
+// CachedUserRepository serves user lookups by ID from a cache and forwards everything else to the wrapped
+// repository. Only users read from the primary are cached, a replica may still have the user as it was
+// before a write. Lookups that have to read the primary, such as those authenticating a request, bypass
+// the cache so that suspensions and revoked tokens take effect at once.
+//
+// Users returned by GetByUserID never carry their password hash, which is kept out of the cache. Password
+// checks read the user by phone number, or by ID inside a transaction. The cached users do include their
+// phone number, which profile lookups return. It stays in process memory with the default in-process cache,
+// as it does while any request uses it. A shared cache plugged in through cache.Cache has to encrypt what
+// it stores.
+//
+// Deleted users are not cached either, so that no instance still has one in its cache when it is anonymized.
+//
+// Writes invalidate the users they touch right away, and again once their transaction has been committed
+// because lookups outside the transaction keep reading the old row until then. An invalidation also moves
+// the user to a new generation, and a load that started in an older one does not cache what it read. Lookups
+// inside a transaction bypass the cache, as they may see writes that are not committed yet.
+type CachedUserRepository struct {
+	opts        *CachedUserRepositoryOptions
+	group       cache.Group
+	generations [userGenerationStripes]userGeneration
+}
+
+// userGeneration counts the invalidations of the users sharing it.
+type userGeneration struct {
+	mu sync.Mutex
+	n  uint64
+}
+
+func NewCachedUserRepository(opts CachedUserRepositoryOptions) *CachedUserRepository {
+	return &CachedUserRepository{
+		opts: &opts,
+	}
+}
+
+func (r *CachedUserRepository) Save(ctx context.Context, user model.User) (uuid.UUID, *common.CustomError) {
+	return r.opts.UserRepository.Save(ctx, user)
+}
+
+// GetByPhoneNumber is not cached. It serves logins, which need the password hash the cache does not keep,
+// and leaving it out keeps phone numbers out of the cache keys.
+func (r *CachedUserRepository) GetByPhoneNumber(ctx context.Context, phoneNumber string) (*model.User, *common.CustomError) {
+	return r.opts.UserRepository.GetByPhoneNumber(ctx, phoneNumber)
+}
+
+func (r *CachedUserRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*model.User, *common.CustomError) {
+	if inTransaction(ctx) {
+		return r.opts.UserRepository.GetByUserID(ctx, userID)
+	}
+	if common.ReadsFromPrimary(ctx) {
+		user, err := r.opts.UserRepository.GetByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		loaded := *user
+		loaded.PasswordHash = ""
+		return &loaded, nil
+	}
+	return r.getByUserID(ctx, userID)
+}
+
+// GetAccessState is not cached, it decides whether access tokens are accepted.
+func (r *CachedUserRepository) GetAccessState(ctx context.Context, userID uuid.UUID) (model.UserAccessState, *common.CustomError) {
+	return r.opts.UserRepository.GetAccessState(ctx, userID)
+}
+
+func (r *CachedUserRepository) Update(ctx context.Context, user model.User) *common.CustomError {
+	err := r.opts.UserRepository.Update(ctx, user)
+	// A failed conditional update means the cached user may be outdated, so it is dropped either way.
+	r.invalidate(ctx, user.ID)
+	return err
+}
+
+func (r *CachedUserRepository) Patch(ctx context.Context, patch model.UserPatch) *common.CustomError {
+	err := r.opts.UserRepository.Patch(ctx, patch)
+	r.invalidate(ctx, patch.UserID)
+	return err
+}
+
+func (r *CachedUserRepository) UpdateStatus(ctx context.Context, userID uuid.UUID, from, to model.UserStatus, changedAt time.Time) *common.CustomError {
+	err := r.opts.UserRepository.UpdateStatus(ctx, userID, from, to, changedAt)
+	r.invalidate(ctx, userID)
+	return err
+}
+
+func (r *CachedUserRepository) RevokeTokens(ctx context.Context, userID uuid.UUID, revokedAt time.Time) *common.CustomError {
+	err := r.opts.UserRepository.RevokeTokens(ctx, userID, revokedAt)
+	r.invalidate(ctx, userID)
+	return err
+}
+
+func (r *CachedUserRepository) GetDeletedBefore(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, *common.CustomError) {
+	return r.opts.UserRepository.GetDeletedBefore(ctx, before, limit)
+}
+
+func (r *CachedUserRepository) Anonymize(ctx context.Context, userID uuid.UUID, anonymizedAt time.Time) *common.CustomError {
+	err := r.opts.UserRepository.Anonymize(ctx, userID, anonymizedAt)
+	r.invalidate(ctx, userID)
+	return err
+}
+
+// ReencryptPhoneNumbers invalidates the users whose phone number was normalized, the others keep their data.
+func (r *CachedUserRepository) ReencryptPhoneNumbers(ctx context.Context, after uuid.UUID, limit int) (model.PhoneNumberReencryption, *common.CustomError) {
+	result, err := r.opts.UserRepository.ReencryptPhoneNumbers(ctx, after, limit)
+	for _, userID := range result.Normalized {
+		r.invalidate(ctx, userID)
+	}
+	return result, err
+}
+
+func (r *CachedUserRepository) getByUserID(ctx context.Context, userID uuid.UUID) (*model.User, *common.CustomError) {
+	key := userCacheKeyPrefix + userID.String()
+	if value, ok := r.get(ctx, key); ok {
+		var user model.User
+		if err := json.Unmarshal(value, &user); err == nil {
+			userCacheLookupsTotal.Inc("hit")
+			return &user, nil
+		}
+		logging.FromContext(ctx).Warn("error decoding cached user", "user_id", userID)
+	}
+	userCacheLookupsTotal.Inc("miss")
+
+	return r.load(ctx, userID)
+}
+
+type userLoadResult struct {
+	user *model.User
+	err  *common.CustomError
+}
+
+// load reads the user once for all concurrent lookups that read from the same databases and caches it,
+// unless a replica served it, it is deleted or it was invalidated in the meantime. Every caller gets a copy
+// of the user without its password hash. The shared read runs detached from the context of the lookup that
+// started it, so that one client going away does not fail the lookups that joined it.
+func (r *CachedUserRepository) load(ctx context.Context, userID uuid.UUID) (*model.User, *common.CustomError) {
+	key := userID.String()
+	if common.WrotePrimary(ctx) {
+		key = "primary:" + key
+	}
+
+	result, ok := r.group.Do(key, func() interface{} {
+		generation := r.generation(userID)
+
+		loadCtx, cancel := withQueryTimeout(detach(ctx), r.opts.LoadTimeout)
+		defer cancel()
+		readCtx, fromReplica := withReplicaReads(loadCtx)
+		user, err := r.opts.UserRepository.GetByUserID(readCtx, userID)
+		if err != nil {
+			return userLoadResult{err: err}
+		}
+
+		loaded := *user
+		loaded.PasswordHash = ""
+		if !fromReplica.Load() && loaded.Status != model.UserStatusDeleted {
+			r.setIfCurrent(loadCtx, &loaded, generation)
+		}
+		return userLoadResult{user: &loaded}
+	}).(userLoadResult)
+	if !ok {
+		return nil, common.NewCustomError(common.ErrUnexpectedError, "loading user failed")
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	user := *result.user
+	return &user, nil
+}
+
+// invalidate drops the cached user, and again once the transaction of ctx has been committed.
+func (r *CachedUserRepository) invalidate(ctx context.Context, userID uuid.UUID) {
+	r.drop(ctx, userID)
+	if inTransaction(ctx) {
+		afterCommit(ctx, func() { r.drop(ctx, userID) })
+	}
+}
+
+// drop moves the user to a new generation and deletes the cached user.
+func (r *CachedUserRepository) drop(ctx context.Context, userID uuid.UUID) {
+	stripe := r.stripe(userID)
+	stripe.mu.Lock()
+	stripe.n++
+	stripe.mu.Unlock()
+
+	r.delete(ctx, userCacheKeyPrefix+userID.String())
+}
+
+func (r *CachedUserRepository) generation(userID uuid.UUID) uint64 {
+	stripe := r.stripe(userID)
+	stripe.mu.Lock()
+	defer stripe.mu.Unlock()
+	return stripe.n
+}
+
+// setIfCurrent caches the user unless it has been invalidated since generation was read. Holding the lock
+// while writing keeps an invalidation from running between the check and the write.
+func (r *CachedUserRepository) setIfCurrent(ctx context.Context, user *model.User, generation uint64) {
+	stripe := r.stripe(user.ID)
+	stripe.mu.Lock()
+	defer stripe.mu.Unlock()
+	if stripe.n == generation {
+		r.set(ctx, user)
+	}
+}
+
+func (r *CachedUserRepository) stripe(userID uuid.UUID) *userGeneration {
+	return &r.generations[binary.BigEndian.Uint64(userID[8:])%userGenerationStripes]
+}
+
+func (r *CachedUserRepository) get(ctx context.Context, key string) ([]byte, bool) {
+	value, ok, err := r.opts.Cache.Get(ctx, key)
+	if err != nil {
+		logging.FromContext(ctx).Warn("error reading user cache", "error", err)
+		return nil, false
+	}
+	return value, ok
+}
+
+func (r *CachedUserRepository) set(ctx context.Context, user *model.User) {
+	value, err := json.Marshal(user)
+	if err != nil {
+		logging.FromContext(ctx).Warn("error encoding user for the cache", "user_id", user.ID, "error", err)
+		return
+	}
+
+	if err := r.opts.Cache.Set(ctx, userCacheKeyPrefix+user.ID.String(), value, r.opts.TTL); err != nil {
+		logging.FromContext(ctx).Warn("error writing user cache", "error", err)
+	}
+}
+
+// delete drops keys from the cache. A failure is logged as an error, since the cache serves outdated users
+// until they expire.
+func (r *CachedUserRepository) delete(ctx context.Context, keys ...string) {
+	if err := r.opts.Cache.Delete(ctx, keys...); err != nil {
+		logging.FromContext(ctx).Error("error invalidating user cache", "keys", keys, "error", err)
+	}
+}
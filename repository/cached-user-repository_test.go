@@ -0,0 +1,279 @@
+package repository_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/cache"
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type CachedUserRepositoryTestSuite struct {
+	suite.Suite
+	ctrl           *gomock.Controller
+	userRepository *repository.MockUserRepository
+	sut            *repository.CachedUserRepository
+}
+
+func (s *CachedUserRepositoryTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.userRepository = repository.NewMockUserRepository(s.ctrl)
+	s.sut = repository.NewCachedUserRepository(repository.CachedUserRepositoryOptions{
+		UserRepository: s.userRepository,
+		Cache:          cache.NewLRUCache(cache.LRUCacheOptions{}),
+		TTL:            time.Minute,
+	})
+}
+
+func (s *CachedUserRepositoryTestSuite) AfterTest(suiteName, testName string) {
+	s.ctrl.Finish()
+}
+
+func TestCachedUserRepository(t *testing.T) {
+	suite.Run(t, new(CachedUserRepositoryTestSuite))
+}
+
+func (s *CachedUserRepositoryTestSuite) newUser() model.User {
+	return model.User{
+		ID:          uuid.New(),
+		PhoneNumber: "+628111111111",
+		FullName:    "Jasuke",
+		Status:      model.UserStatusActive,
+		Version:     1,
+	}
+}
+
+func (s *CachedUserRepositoryTestSuite) TestGetByUserIDShouldServeRepeatedLookupsFromCache() {
+	ctx := context.Background()
+	user := s.newUser()
+
+	s.userRepository.EXPECT().GetByUserID(gomock.Any(), gomock.Eq(user.ID)).Return(&user, nil).Times(1)
+
+	first, err := s.sut.GetByUserID(ctx, user.ID)
+	s.Require().Nil(err)
+	first.FullName = "changed by the caller"
+
+	second, err := s.sut.GetByUserID(ctx, user.ID)
+	s.Require().Nil(err)
+	s.Equal(user.FullName, second.FullName)
+	s.Equal(user.ID, second.ID)
+}
+
+func (s *CachedUserRepositoryTestSuite) TestGetByUserIDAfterUpdateShouldReloadUser() {
+	ctx := context.Background()
+	user := s.newUser()
+	updated := user
+	updated.FullName = "Jasuke Updated"
+	updated.Version = 2
+
+	gomock.InOrder(
+		s.userRepository.EXPECT().GetByUserID(gomock.Any(), gomock.Eq(user.ID)).Return(&user, nil),
+		s.userRepository.EXPECT().Update(gomock.Any(), gomock.Eq(updated)).Return(nil),
+		s.userRepository.EXPECT().GetByUserID(gomock.Any(), gomock.Eq(user.ID)).Return(&updated, nil),
+	)
+
+	_, err := s.sut.GetByUserID(ctx, user.ID)
+	s.Require().Nil(err)
+	s.Require().Nil(s.sut.Update(ctx, updated))
+
+	result, err := s.sut.GetByUserID(ctx, user.ID)
+	s.Require().Nil(err)
+	s.Equal("Jasuke Updated", result.FullName)
+}
+
+func (s *CachedUserRepositoryTestSuite) TestReencryptPhoneNumbersShouldReloadNormalizedUsers() {
+	ctx := context.Background()
+	user := s.newUser()
+	user.PhoneNumber = "+6208111111111"
+	normalized := user
+	normalized.PhoneNumber = "+628111111111"
+
+	gomock.InOrder(
+		s.userRepository.EXPECT().GetByUserID(gomock.Any(), gomock.Eq(user.ID)).Return(&user, nil),
+		s.userRepository.EXPECT().ReencryptPhoneNumbers(gomock.Any(), uuid.Nil, 10).
+			Return(model.PhoneNumberReencryption{Size: 1, LastUserID: user.ID, Reencrypted: 1, Normalized: []uuid.UUID{user.ID}}, nil),
+		s.userRepository.EXPECT().GetByUserID(gomock.Any(), gomock.Eq(user.ID)).Return(&normalized, nil),
+	)
+
+	_, err := s.sut.GetByUserID(ctx, user.ID)
+	s.Require().Nil(err)
+	_, err = s.sut.ReencryptPhoneNumbers(ctx, uuid.Nil, 10)
+	s.Require().Nil(err)
+
+	reloaded, err := s.sut.GetByUserID(ctx, user.ID)
+	s.Require().Nil(err)
+	s.Equal(normalized.PhoneNumber, reloaded.PhoneNumber)
+}
+
+func (s *CachedUserRepositoryTestSuite) TestGetByPhoneNumberShouldNotBeCached() {
+	ctx := context.Background()
+	user := s.newUser()
+	user.PasswordHash = "password hash"
+
+	s.userRepository.EXPECT().GetByPhoneNumber(gomock.Any(), gomock.Eq(user.PhoneNumber)).Return(&user, nil).Times(2)
+
+	for i := 0; i < 2; i++ {
+		result, err := s.sut.GetByPhoneNumber(ctx, user.PhoneNumber)
+		s.Require().Nil(err)
+		s.Equal("password hash", result.PasswordHash)
+	}
+}
+
+func (s *CachedUserRepositoryTestSuite) TestGetByUserIDShouldNotReturnPasswordHash() {
+	ctx := context.Background()
+	user := s.newUser()
+	user.PasswordHash = "password hash"
+
+	s.userRepository.EXPECT().GetByUserID(gomock.Any(), gomock.Eq(user.ID)).Return(&user, nil).Times(1)
+
+	for i := 0; i < 2; i++ {
+		result, err := s.sut.GetByUserID(ctx, user.ID)
+		s.Require().Nil(err)
+		s.Empty(result.PasswordHash)
+	}
+}
+
+func (s *CachedUserRepositoryTestSuite) TestGetByUserIDGivenDeletedUserShouldNotCacheIt() {
+	ctx := context.Background()
+	user := s.newUser()
+	user.Status = model.UserStatusDeleted
+
+	s.userRepository.EXPECT().GetByUserID(gomock.Any(), gomock.Eq(user.ID)).Return(&user, nil).Times(2)
+
+	for i := 0; i < 2; i++ {
+		_, err := s.sut.GetByUserID(ctx, user.ID)
+		s.Require().Nil(err)
+	}
+}
+
+func (s *CachedUserRepositoryTestSuite) TestGetByUserIDGivenPrimaryReadsShouldBypassCache() {
+	user := s.newUser()
+	suspended := user
+	suspended.Status = model.UserStatusSuspended
+
+	gomock.InOrder(
+		s.userRepository.EXPECT().GetByUserID(gomock.Any(), gomock.Eq(user.ID)).Return(&user, nil),
+		s.userRepository.EXPECT().GetByUserID(gomock.Any(), gomock.Eq(user.ID)).DoAndReturn(func(ctx context.Context, userID uuid.UUID) (*model.User, *common.CustomError) {
+			s.True(common.ReadsFromPrimary(ctx))
+			return &suspended, nil
+		}),
+	)
+
+	_, err := s.sut.GetByUserID(context.Background(), user.ID)
+	s.Require().Nil(err)
+
+	result, err := s.sut.GetByUserID(common.WithPrimaryReads(context.Background()), user.ID)
+	s.Require().Nil(err)
+	s.Equal(model.UserStatusSuspended, result.Status)
+}
+
+func (s *CachedUserRepositoryTestSuite) TestGetByUserIDGivenInvalidationDuringLoadShouldNotCacheUser() {
+	user := s.newUser()
+	suspended := user
+	suspended.Status = model.UserStatusSuspended
+	loading := make(chan struct{})
+	release := make(chan struct{})
+
+	gomock.InOrder(
+		s.userRepository.EXPECT().GetByUserID(gomock.Any(), gomock.Eq(user.ID)).DoAndReturn(func(ctx context.Context, userID uuid.UUID) (*model.User, *common.CustomError) {
+			close(loading)
+			<-release
+			return &user, nil
+		}),
+		s.userRepository.EXPECT().UpdateStatus(gomock.Any(), user.ID, model.UserStatusActive, model.UserStatusSuspended, gomock.Any()).Return(nil),
+		s.userRepository.EXPECT().GetByUserID(gomock.Any(), gomock.Eq(user.ID)).Return(&suspended, nil),
+	)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := s.sut.GetByUserID(context.Background(), user.ID)
+		s.Nil(err)
+	}()
+	<-loading
+
+	s.Require().Nil(s.sut.UpdateStatus(context.Background(), user.ID, model.UserStatusActive, model.UserStatusSuspended, time.Now()))
+	close(release)
+	<-done
+
+	result, err := s.sut.GetByUserID(context.Background(), user.ID)
+	s.Require().Nil(err)
+	s.Equal(model.UserStatusSuspended, result.Status)
+}
+
+func (s *CachedUserRepositoryTestSuite) TestGetByUserIDGivenCancelledLookupShouldNotFailJoinedLookups() {
+	user := s.newUser()
+	ctx, cancel := context.WithCancel(context.Background())
+	loading := make(chan struct{})
+	release := make(chan struct{})
+	joined := make(chan struct{})
+	repository.OnLoadJoined(s.sut, func() { close(joined) })
+
+	s.userRepository.EXPECT().GetByUserID(gomock.Any(), gomock.Eq(user.ID)).DoAndReturn(func(ctx context.Context, userID uuid.UUID) (*model.User, *common.CustomError) {
+		close(loading)
+		<-release
+		if ctx.Err() != nil {
+			return nil, common.WrapError(common.ErrUnexpectedError, ctx.Err(), "error getting user")
+		}
+		return &user, nil
+	}).Times(1)
+
+	cancelled := make(chan struct{})
+	go func() {
+		defer close(cancelled)
+		_, _ = s.sut.GetByUserID(ctx, user.ID)
+	}()
+	<-loading
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		result, err := s.sut.GetByUserID(context.Background(), user.ID)
+		s.Nil(err)
+		s.Equal(user.ID, result.ID)
+	}()
+	<-joined
+
+	cancel()
+	close(release)
+	<-done
+	<-cancelled
+}
+
+func (s *CachedUserRepositoryTestSuite) TestGetByUserIDGivenConcurrentMissesShouldLoadOnce() {
+	ctx := context.Background()
+	user := s.newUser()
+	release := make(chan struct{})
+
+	s.userRepository.EXPECT().GetByUserID(gomock.Any(), gomock.Eq(user.ID)).DoAndReturn(func(ctx context.Context, userID uuid.UUID) (*model.User, *common.CustomError) {
+		<-release
+		return &user, nil
+	}).Times(1)
+
+	joined := make(chan struct{})
+	repository.OnLoadJoined(s.sut, func() { joined <- struct{}{} })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := s.sut.GetByUserID(ctx, user.ID)
+			s.Nil(err)
+			s.Equal(user.ID, result.ID)
+		}()
+	}
+
+	for i := 1; i < 10; i++ {
+		<-joined
+	}
+	close(release)
+	wg.Wait()
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/lib/pq"
+)
+
+const (
+	postgreSQLUniqueViolationErrCode      pq.ErrorCode = "23505"
+	postgreSQLForeignKeyViolationErrCode  pq.ErrorCode = "23503"
+	postgreSQLSerializationFailureErrCode pq.ErrorCode = "40001"
+	postgreSQLDeadlockDetectedErrCode     pq.ErrorCode = "40P01"
+	// postgreSQLConnectionExceptionErrClass covers errors like connection_failure, while
+	// admin_shutdown and crash_shutdown are reported when the server goes away.
+	postgreSQLConnectionExceptionErrClass pq.ErrorClass = "08"
+	postgreSQLAdminShutdownErrCode        pq.ErrorCode  = "57P01"
+	postgreSQLCrashShutdownErrCode        pq.ErrorCode  = "57P02"
+	postgreSQLCannotConnectNowErrCode     pq.ErrorCode  = "57P03"
+	// postgreSQLQueryCanceledErrCode is reported when a statement timeout or a cancelled context stopped
+	// the query.
+	postgreSQLQueryCanceledErrCode pq.ErrorCode = "57014"
+)
+
+// IsUniqueViolation reports whether err was caused by a unique constraint violation.
+func IsUniqueViolation(err error) bool {
+	return hasPQErrorCode(err, postgreSQLUniqueViolationErrCode)
+}
+
+// IsForeignKeyViolation reports whether err was caused by a row referencing a row that does not exist.
+func IsForeignKeyViolation(err error) bool {
+	return hasPQErrorCode(err, postgreSQLForeignKeyViolationErrCode)
+}
+
+// IsSerializationFailure reports whether the database aborted a transaction because of a concurrent one,
+// which includes deadlocks. Running the transaction again is expected to succeed.
+func IsSerializationFailure(err error) bool {
+	return hasPQErrorCode(err, postgreSQLSerializationFailureErrCode, postgreSQLDeadlockDetectedErrCode)
+}
+
+// IsTimeout reports whether err was caused by a deadline, of the context or of the connection, expiring
+// before the database answered. A query stopped because its context was cancelled fails with the same
+// code as a statement timeout, so callers check their context for context.Canceled first.
+func IsTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) ||
+		hasPQErrorCode(err, postgreSQLQueryCanceledErrCode) ||
+		(errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// IsConnectionLost reports whether err was caused by the database being unreachable or closing the
+// connection, rather than by the query.
+func IsConnectionLost(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code.Class() == postgreSQLConnectionExceptionErrClass ||
+			hasPQErrorCode(err, postgreSQLAdminShutdownErrCode, postgreSQLCrashShutdownErrCode, postgreSQLCannotConnectNowErrCode)
+	}
+
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.As(err, &netErr)
+}
+
+// isReplicaFailure reports whether a replica failed a read that the primary is expected to answer: a
+// recovery conflict cancelling the query to replay changes from the primary, or a statement timeout while
+// the replica is busy replaying them.
+func isReplicaFailure(err error) bool {
+	return hasPQErrorCode(err, postgreSQLSerializationFailureErrCode, postgreSQLQueryCanceledErrCode)
+}
+
+func hasPQErrorCode(err error, codes ...pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	for _, code := range codes {
+		if pqErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// databaseError classifies a database error into the error type clients are told about. The message never
+// quotes err, which may contain SQL or personal data, but err is kept as the cause for the logs.
+func databaseError(err error) *common.CustomError {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return common.WrapError(common.ErrCanceled, err, "request was cancelled")
+	case IsSerializationFailure(err):
+		return common.WrapError(common.ErrTransactionConflict, err, "transaction conflicted with a concurrent request, please retry")
+	case IsUniqueViolation(err):
+		return common.WrapError(common.ErrEntityAlreadyExists, err, "entity already exists")
+	case IsForeignKeyViolation(err):
+		return common.WrapError(common.ErrEntityNotFound, err, "referenced entity does not exist")
+	case IsTimeout(err):
+		return common.WrapError(common.ErrTimeout, err, "database did not answer in time, please retry later")
+	case IsConnectionLost(err):
+		return common.WrapError(common.ErrUnavailable, err, "database is unavailable, please retry later")
+	default:
+		return common.WrapError(common.ErrUnexpectedError, err, "unexpected database error")
+	}
+}
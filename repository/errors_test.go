@@ -0,0 +1,57 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/suite"
+)
+
+type ErrorsTestSuite struct {
+	suite.Suite
+}
+
+func TestErrors(t *testing.T) {
+	suite.Run(t, new(ErrorsTestSuite))
+}
+
+func (s *ErrorsTestSuite) TestClassifiersShouldMatchWrappedPQErrors() {
+	wrap := func(code pq.ErrorCode) error {
+		return fmt.Errorf("inserting user: %w", &pq.Error{Code: code})
+	}
+
+	s.True(repository.IsUniqueViolation(wrap("23505")))
+	s.True(repository.IsForeignKeyViolation(wrap("23503")))
+	s.True(repository.IsSerializationFailure(wrap("40001")))
+	s.True(repository.IsSerializationFailure(wrap("40P01")))
+	s.True(repository.IsConnectionLost(wrap("08006")))
+	s.True(repository.IsConnectionLost(wrap("57P01")))
+
+	s.False(repository.IsUniqueViolation(wrap("23503")))
+	s.False(repository.IsConnectionLost(wrap("23505")))
+	s.False(repository.IsUniqueViolation(errors.New("23505")))
+}
+
+func (s *ErrorsTestSuite) TestIsTimeoutShouldMatchDeadlinesAndCanceledQueries() {
+	s.True(repository.IsTimeout(fmt.Errorf("query: %w", context.DeadlineExceeded)))
+	s.True(repository.IsTimeout(&pq.Error{Code: "57014"}))
+	s.False(repository.IsTimeout(context.Canceled))
+}
+
+func (s *ErrorsTestSuite) TestDatabaseErrorGivenCanceledContextShouldNotReportTimeout() {
+	err := repository.DatabaseError(fmt.Errorf("query: %w", context.Canceled))
+
+	s.Equal(common.ErrCanceled, err.ErrType)
+}
+
+func (s *ErrorsTestSuite) TestIsConnectionLostShouldMatchDriverErrors() {
+	s.True(repository.IsConnectionLost(driver.ErrBadConn))
+	s.True(repository.IsConnectionLost(fmt.Errorf("query: %w", driver.ErrBadConn)))
+	s.False(repository.IsConnectionLost(errors.New("syntax error")))
+}
@@ -0,0 +1,9 @@
+package repository
+
+// OnLoadJoined makes r call fn whenever a lookup joins a load of the same user that is already running.
+func OnLoadJoined(r *CachedUserRepository, fn func()) {
+	r.group.OnJoin = func(string) { fn() }
+}
+
+// DatabaseError exposes the classification of database errors.
+var DatabaseError = databaseError
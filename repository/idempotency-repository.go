@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+)
+
+// maxClaimAttempts bounds Claim when the record it conflicts with is deleted before it can be read.
+const maxClaimAttempts = 3
+
+type IdempotencyRepositoryImplOptions struct {
+	DB           *sql.DB
+	QueryTimeout time.Duration
+}
+
+type IdempotencyRepositoryImpl struct {
+	opts *IdempotencyRepositoryImplOptions
+}
+
+func NewIdempotencyRepositoryImpl(opts IdempotencyRepositoryImplOptions) *IdempotencyRepositoryImpl {
+	return &IdempotencyRepositoryImpl{
+		opts: &opts,
+	}
+}
+
+// Claim stores record as in progress, unless the key of the caller already has a record that has not expired
+// at now. That record is returned instead, and nil when the key has been claimed.
+func (r *IdempotencyRepositoryImpl) Claim(ctx context.Context, record model.IdempotencyRecord, now time.Time) (*model.IdempotencyRecord, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	claimQuery := `INSERT INTO idempotency_keys (principal, idempotency_key, fingerprint, expires_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (principal, idempotency_key) DO UPDATE SET fingerprint = EXCLUDED.fingerprint, status_code = NULL, content_type = NULL,
+		body = NULL, created_at = now(), expires_at = EXCLUDED.expires_at WHERE idempotency_keys.expires_at <= $5
+		RETURNING idempotency_key;`
+	selectQuery := `SELECT fingerprint, COALESCE(status_code, 0), COALESCE(content_type, ''), body, expires_at FROM idempotency_keys
+		WHERE principal = $1 AND idempotency_key = $2;`
+
+	for attempt := 0; attempt < maxClaimAttempts; attempt++ {
+		var key string
+		err := conn(ctx, r.opts.DB).QueryRowContext(ctx, claimQuery, record.Principal, record.Key, record.Fingerprint, record.ExpiresAt, now).Scan(&key)
+		if err == nil {
+			return nil, nil
+		}
+		if err != sql.ErrNoRows {
+			return nil, databaseError(err)
+		}
+
+		existing := model.IdempotencyRecord{Principal: record.Principal, Key: record.Key}
+		err = conn(ctx, r.opts.DB).QueryRowContext(ctx, selectQuery, record.Principal, record.Key).
+			Scan(&existing.Fingerprint, &existing.StatusCode, &existing.ContentType, &existing.Body, &existing.ExpiresAt)
+		if err == nil {
+			return &existing, nil
+		}
+		// The record expired and was deleted in between, claiming it again succeeds.
+		if err != sql.ErrNoRows {
+			return nil, databaseError(err)
+		}
+	}
+	return nil, common.NewCustomError(common.ErrTransactionConflict, "idempotency key changed concurrently, please retry")
+}
+
+// Complete stores the response of a claimed record.
+func (r *IdempotencyRepositoryImpl) Complete(ctx context.Context, record model.IdempotencyRecord) *common.CustomError {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `UPDATE idempotency_keys SET status_code = $4, content_type = $5, body = $6, expires_at = $7
+		WHERE principal = $1 AND idempotency_key = $2 AND fingerprint = $3 AND status_code IS NULL;`
+
+	_, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, record.Principal, record.Key, record.Fingerprint,
+		record.StatusCode, record.ContentType, record.Body, record.ExpiresAt)
+	if err != nil {
+		return databaseError(err)
+	}
+	return nil
+}
+
+// Release deletes a claimed record that has no response, so that the key can be claimed again.
+func (r *IdempotencyRepositoryImpl) Release(ctx context.Context, record model.IdempotencyRecord) *common.CustomError {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `DELETE FROM idempotency_keys WHERE principal = $1 AND idempotency_key = $2 AND fingerprint = $3 AND status_code IS NULL;`
+
+	if _, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, record.Principal, record.Key, record.Fingerprint); err != nil {
+		return databaseError(err)
+	}
+	return nil
+}
+
+// DeleteExpired deletes up to limit records that expired before the given time and returns how many it
+// deleted.
+func (r *IdempotencyRepositoryImpl) DeleteExpired(ctx context.Context, before time.Time, limit int) (int, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `DELETE FROM idempotency_keys WHERE (principal, idempotency_key) IN
+		(SELECT principal, idempotency_key FROM idempotency_keys WHERE expires_at < $1 LIMIT $2);`
+
+	result, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, databaseError(err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, databaseError(err)
+	}
+	return int(deleted), nil
+}
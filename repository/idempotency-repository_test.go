@@ -0,0 +1,126 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/stretchr/testify/suite"
+)
+
+type IdempotencyRepositoryTestSuite struct {
+	suite.Suite
+	db     *fakeDatabase
+	sut    *repository.IdempotencyRepositoryImpl
+	now    time.Time
+	record model.IdempotencyRecord
+}
+
+func (s *IdempotencyRepositoryTestSuite) SetupTest() {
+	s.db = &fakeDatabase{}
+	s.sut = repository.NewIdempotencyRepositoryImpl(repository.IdempotencyRepositoryImplOptions{
+		DB: sql.OpenDB(s.db),
+	})
+	s.now = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.record = model.IdempotencyRecord{
+		Principal:   "principal",
+		Key:         "5f1c2a9e-register",
+		Fingerprint: []byte("fingerprint"),
+		ExpiresAt:   s.now.Add(time.Minute),
+	}
+}
+
+func TestIdempotencyRepository(t *testing.T) {
+	suite.Run(t, new(IdempotencyRepositoryTestSuite))
+}
+
+func isClaim(query string) bool {
+	return strings.HasPrefix(strings.TrimSpace(query), "INSERT INTO idempotency_keys")
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestClaimGivenNewKeyShouldClaimIt() {
+	s.db.respond = func(query string, args []driver.Value) [][]driver.Value {
+		if isClaim(query) {
+			return [][]driver.Value{{s.record.Key}}
+		}
+		return nil
+	}
+
+	existing, err := s.sut.Claim(context.Background(), s.record, s.now)
+
+	s.Require().Nil(err)
+	s.Nil(existing)
+	s.Len(s.db.queries(), 1)
+}
+
+// A record only gives way to a new claim once it has expired, which the claim leaves to the database by
+// comparing expires_at with the time it passes.
+func (s *IdempotencyRepositoryTestSuite) TestClaimShouldOnlyReplaceExpiredRecords() {
+	s.db.respond = func(query string, args []driver.Value) [][]driver.Value {
+		if isClaim(query) {
+			return [][]driver.Value{{s.record.Key}}
+		}
+		return nil
+	}
+
+	_, err := s.sut.Claim(context.Background(), s.record, s.now)
+	s.Require().Nil(err)
+
+	claim := strings.Join(strings.Fields(s.db.queries()[0]), " ")
+	s.Contains(claim, "ON CONFLICT (principal, idempotency_key) DO UPDATE")
+	s.Contains(claim, "WHERE idempotency_keys.expires_at <= $5")
+	args := s.db.argumentsOf("INSERT INTO idempotency_keys")[0]
+	s.Equal(s.record.ExpiresAt, args[3])
+	s.Equal(s.now, args[4])
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestClaimGivenUnexpiredRecordShouldReturnIt() {
+	s.db.respond = func(query string, args []driver.Value) [][]driver.Value {
+		if isClaim(query) {
+			return nil
+		}
+		return [][]driver.Value{{[]byte("other fingerprint"), int64(201), "application/json", []byte(`{}`), s.now.Add(time.Hour)}}
+	}
+
+	existing, err := s.sut.Claim(context.Background(), s.record, s.now)
+
+	s.Require().Nil(err)
+	s.Require().NotNil(existing)
+	s.Equal([]byte("other fingerprint"), existing.Fingerprint)
+	s.Equal(201, existing.StatusCode)
+	s.Equal(s.now.Add(time.Hour), existing.ExpiresAt)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestClaimGivenRecordDeletedMeanwhileShouldClaimAgain() {
+	claims := 0
+	s.db.respond = func(query string, args []driver.Value) [][]driver.Value {
+		if isClaim(query) {
+			claims++
+			if claims == 2 {
+				return [][]driver.Value{{s.record.Key}}
+			}
+		}
+		return nil
+	}
+
+	existing, err := s.sut.Claim(context.Background(), s.record, s.now)
+
+	s.Require().Nil(err)
+	s.Nil(existing)
+	s.Equal(2, claims)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestClaimGivenRecordKeepsChangingShouldReturnConflict() {
+	existing, err := s.sut.Claim(context.Background(), s.record, s.now)
+
+	s.Nil(existing)
+	s.Require().NotNil(err)
+	s.Equal(common.ErrTransactionConflict, err.ErrType)
+	s.Len(s.db.argumentsOf("INSERT INTO idempotency_keys"), 3)
+}
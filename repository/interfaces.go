@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/SawitProRecruitment/UserService/common"
 	"github.com/SawitProRecruitment/UserService/model"
@@ -12,9 +14,57 @@ type UserRepository interface {
 	Save(ctx context.Context, user model.User) (uuid.UUID, *common.CustomError)
 	GetByPhoneNumber(ctx context.Context, phoneNumber string) (*model.User, *common.CustomError)
 	GetByUserID(ctx context.Context, userID uuid.UUID) (*model.User, *common.CustomError)
+	GetAccessState(ctx context.Context, userID uuid.UUID) (model.UserAccessState, *common.CustomError)
 	Update(ctx context.Context, user model.User) *common.CustomError
+	Patch(ctx context.Context, patch model.UserPatch) *common.CustomError
+	UpdateStatus(ctx context.Context, userID uuid.UUID, from, to model.UserStatus, changedAt time.Time) *common.CustomError
+	RevokeTokens(ctx context.Context, userID uuid.UUID, revokedAt time.Time) *common.CustomError
+	GetDeletedBefore(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, *common.CustomError)
+	Anonymize(ctx context.Context, userID uuid.UUID, anonymizedAt time.Time) *common.CustomError
+	ReencryptPhoneNumbers(ctx context.Context, after uuid.UUID, limit int) (model.PhoneNumberReencryption, *common.CustomError)
 }
 
 type LoginLogRepository interface {
 	Save(ctx context.Context, log model.LoginLog) (uuid.UUID, *common.CustomError)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.LoginLog, *common.CustomError)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) *common.CustomError
+}
+
+type AuditEventRepository interface {
+	Append(ctx context.Context, event model.AuditEvent) (model.AuditEvent, *common.CustomError)
+	Find(ctx context.Context, filter model.AuditEventFilter) ([]model.AuditEvent, *common.CustomError)
+}
+
+type OutboxRepository interface {
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEvent, *common.CustomError)
+	MarkPublished(ctx context.Context, eventID uuid.UUID, publishedAt time.Time) *common.CustomError
+	MarkFailed(ctx context.Context, eventID uuid.UUID, lastError string, nextAttemptAt time.Time) *common.CustomError
+	DeletePublishedBefore(ctx context.Context, before time.Time, limit int) (int, *common.CustomError)
+}
+
+type WebhookRepository interface {
+	SaveSubscription(ctx context.Context, subscription model.WebhookSubscription) (uuid.UUID, *common.CustomError)
+	GetSubscription(ctx context.Context, subscriptionID uuid.UUID) (*model.WebhookSubscription, *common.CustomError)
+	GetSubscriptions(ctx context.Context) ([]model.WebhookSubscription, *common.CustomError)
+	UpdateSubscription(ctx context.Context, subscription model.WebhookSubscription) *common.CustomError
+	DeleteSubscription(ctx context.Context, subscriptionID uuid.UUID) *common.CustomError
+	EnqueueDeliveries(ctx context.Context, eventID uuid.UUID, eventType model.OutboxEventType, payload json.RawMessage) *common.CustomError
+	ClaimPendingDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, *common.CustomError)
+	MarkDelivered(ctx context.Context, deliveryID uuid.UUID, statusCode int, deliveredAt time.Time) *common.CustomError
+	MarkFailed(ctx context.Context, deliveryID uuid.UUID, result model.WebhookDeliveryResult, nextAttemptAt time.Time) *common.CustomError
+	MarkDeadLettered(ctx context.Context, deliveryID uuid.UUID, result model.WebhookDeliveryResult) *common.CustomError
+	GetDelivery(ctx context.Context, subscriptionID, deliveryID uuid.UUID) (*model.WebhookDelivery, *common.CustomError)
+	GetDeliveries(ctx context.Context, filter model.WebhookDeliveryFilter) ([]model.WebhookDelivery, *common.CustomError)
+	Redeliver(ctx context.Context, deliveryID uuid.UUID, nextAttemptAt time.Time) *common.CustomError
+}
+
+type IdempotencyRepository interface {
+	Claim(ctx context.Context, record model.IdempotencyRecord, now time.Time) (*model.IdempotencyRecord, *common.CustomError)
+	Complete(ctx context.Context, record model.IdempotencyRecord) *common.CustomError
+	Release(ctx context.Context, record model.IdempotencyRecord) *common.CustomError
+	DeleteExpired(ctx context.Context, before time.Time, limit int) (int, *common.CustomError)
+}
+
+type TransactionManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) *common.CustomError) *common.CustomError
 }
@@ -6,7 +6,9 @@ package repository
 
 import (
 	context "context"
+	json "encoding/json"
 	reflect "reflect"
+	time "time"
 
 	common "github.com/SawitProRecruitment/UserService/common"
 	model "github.com/SawitProRecruitment/UserService/model"
@@ -37,6 +39,35 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Anonymize mocks base method.
+func (m *MockUserRepository) Anonymize(ctx context.Context, userID uuid.UUID, anonymizedAt time.Time) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Anonymize", ctx, userID, anonymizedAt)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// Anonymize indicates an expected call of Anonymize.
+func (mr *MockUserRepositoryMockRecorder) Anonymize(ctx, userID, anonymizedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Anonymize", reflect.TypeOf((*MockUserRepository)(nil).Anonymize), ctx, userID, anonymizedAt)
+}
+
+// GetAccessState mocks base method.
+func (m *MockUserRepository) GetAccessState(ctx context.Context, userID uuid.UUID) (model.UserAccessState, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccessState", ctx, userID)
+	ret0, _ := ret[0].(model.UserAccessState)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// GetAccessState indicates an expected call of GetAccessState.
+func (mr *MockUserRepositoryMockRecorder) GetAccessState(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessState", reflect.TypeOf((*MockUserRepository)(nil).GetAccessState), ctx, userID)
+}
+
 // GetByPhoneNumber mocks base method.
 func (m *MockUserRepository) GetByPhoneNumber(ctx context.Context, phoneNumber string) (*model.User, *common.CustomError) {
 	m.ctrl.T.Helper()
@@ -67,6 +98,64 @@ func (mr *MockUserRepositoryMockRecorder) GetByUserID(ctx, userID interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockUserRepository)(nil).GetByUserID), ctx, userID)
 }
 
+// GetDeletedBefore mocks base method.
+func (m *MockUserRepository) GetDeletedBefore(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedBefore", ctx, before, limit)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// GetDeletedBefore indicates an expected call of GetDeletedBefore.
+func (mr *MockUserRepositoryMockRecorder) GetDeletedBefore(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedBefore", reflect.TypeOf((*MockUserRepository)(nil).GetDeletedBefore), ctx, before, limit)
+}
+
+// Patch mocks base method.
+func (m *MockUserRepository) Patch(ctx context.Context, patch model.UserPatch) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, patch)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockUserRepositoryMockRecorder) Patch(ctx, patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockUserRepository)(nil).Patch), ctx, patch)
+}
+
+// ReencryptPhoneNumbers mocks base method.
+func (m *MockUserRepository) ReencryptPhoneNumbers(ctx context.Context, after uuid.UUID, limit int) (model.PhoneNumberReencryption, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReencryptPhoneNumbers", ctx, after, limit)
+	ret0, _ := ret[0].(model.PhoneNumberReencryption)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// ReencryptPhoneNumbers indicates an expected call of ReencryptPhoneNumbers.
+func (mr *MockUserRepositoryMockRecorder) ReencryptPhoneNumbers(ctx, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReencryptPhoneNumbers", reflect.TypeOf((*MockUserRepository)(nil).ReencryptPhoneNumbers), ctx, after, limit)
+}
+
+// RevokeTokens mocks base method.
+func (m *MockUserRepository) RevokeTokens(ctx context.Context, userID uuid.UUID, revokedAt time.Time) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeTokens", ctx, userID, revokedAt)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// RevokeTokens indicates an expected call of RevokeTokens.
+func (mr *MockUserRepositoryMockRecorder) RevokeTokens(ctx, userID, revokedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeTokens", reflect.TypeOf((*MockUserRepository)(nil).RevokeTokens), ctx, userID, revokedAt)
+}
+
 // Save mocks base method.
 func (m *MockUserRepository) Save(ctx context.Context, user model.User) (uuid.UUID, *common.CustomError) {
 	m.ctrl.T.Helper()
@@ -96,6 +185,20 @@ func (mr *MockUserRepositoryMockRecorder) Update(ctx, user interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepository)(nil).Update), ctx, user)
 }
 
+// UpdateStatus mocks base method.
+func (m *MockUserRepository) UpdateStatus(ctx context.Context, userID uuid.UUID, from, to model.UserStatus, changedAt time.Time) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, userID, from, to, changedAt)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockUserRepositoryMockRecorder) UpdateStatus(ctx, userID, from, to, changedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockUserRepository)(nil).UpdateStatus), ctx, userID, from, to, changedAt)
+}
+
 // MockLoginLogRepository is a mock of LoginLogRepository interface.
 type MockLoginLogRepository struct {
 	ctrl     *gomock.Controller
@@ -119,6 +222,35 @@ func (m *MockLoginLogRepository) EXPECT() *MockLoginLogRepositoryMockRecorder {
 	return m.recorder
 }
 
+// DeleteByUserID mocks base method.
+func (m *MockLoginLogRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUserID", ctx, userID)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// DeleteByUserID indicates an expected call of DeleteByUserID.
+func (mr *MockLoginLogRepositoryMockRecorder) DeleteByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUserID", reflect.TypeOf((*MockLoginLogRepository)(nil).DeleteByUserID), ctx, userID)
+}
+
+// GetByUserID mocks base method.
+func (m *MockLoginLogRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.LoginLog, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID)
+	ret0, _ := ret[0].([]model.LoginLog)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockLoginLogRepositoryMockRecorder) GetByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockLoginLogRepository)(nil).GetByUserID), ctx, userID)
+}
+
 // Save mocks base method.
 func (m *MockLoginLogRepository) Save(ctx context.Context, log model.LoginLog) (uuid.UUID, *common.CustomError) {
 	m.ctrl.T.Helper()
@@ -133,3 +265,466 @@ func (mr *MockLoginLogRepositoryMockRecorder) Save(ctx, log interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockLoginLogRepository)(nil).Save), ctx, log)
 }
+
+// MockAuditEventRepository is a mock of AuditEventRepository interface.
+type MockAuditEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditEventRepositoryMockRecorder
+}
+
+// MockAuditEventRepositoryMockRecorder is the mock recorder for MockAuditEventRepository.
+type MockAuditEventRepositoryMockRecorder struct {
+	mock *MockAuditEventRepository
+}
+
+// NewMockAuditEventRepository creates a new mock instance.
+func NewMockAuditEventRepository(ctrl *gomock.Controller) *MockAuditEventRepository {
+	mock := &MockAuditEventRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditEventRepository) EXPECT() *MockAuditEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *MockAuditEventRepository) Append(ctx context.Context, event model.AuditEvent) (model.AuditEvent, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", ctx, event)
+	ret0, _ := ret[0].(model.AuditEvent)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockAuditEventRepositoryMockRecorder) Append(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockAuditEventRepository)(nil).Append), ctx, event)
+}
+
+// Find mocks base method.
+func (m *MockAuditEventRepository) Find(ctx context.Context, filter model.AuditEventFilter) ([]model.AuditEvent, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, filter)
+	ret0, _ := ret[0].([]model.AuditEvent)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockAuditEventRepositoryMockRecorder) Find(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockAuditEventRepository)(nil).Find), ctx, filter)
+}
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimPending mocks base method.
+func (m *MockOutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEvent, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPending", ctx, limit, lease)
+	ret0, _ := ret[0].([]model.OutboxEvent)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// ClaimPending indicates an expected call of ClaimPending.
+func (mr *MockOutboxRepositoryMockRecorder) ClaimPending(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPending", reflect.TypeOf((*MockOutboxRepository)(nil).ClaimPending), ctx, limit, lease)
+}
+
+// DeletePublishedBefore mocks base method.
+func (m *MockOutboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time, limit int) (int, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePublishedBefore", ctx, before, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// DeletePublishedBefore indicates an expected call of DeletePublishedBefore.
+func (mr *MockOutboxRepositoryMockRecorder) DeletePublishedBefore(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePublishedBefore", reflect.TypeOf((*MockOutboxRepository)(nil).DeletePublishedBefore), ctx, before, limit)
+}
+
+// MarkFailed mocks base method.
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, eventID uuid.UUID, lastError string, nextAttemptAt time.Time) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, eventID, lastError, nextAttemptAt)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockOutboxRepositoryMockRecorder) MarkFailed(ctx, eventID, lastError, nextAttemptAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockOutboxRepository)(nil).MarkFailed), ctx, eventID, lastError, nextAttemptAt)
+}
+
+// MarkPublished mocks base method.
+func (m *MockOutboxRepository) MarkPublished(ctx context.Context, eventID uuid.UUID, publishedAt time.Time) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPublished", ctx, eventID, publishedAt)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// MarkPublished indicates an expected call of MarkPublished.
+func (mr *MockOutboxRepositoryMockRecorder) MarkPublished(ctx, eventID, publishedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPublished", reflect.TypeOf((*MockOutboxRepository)(nil).MarkPublished), ctx, eventID, publishedAt)
+}
+
+// MockWebhookRepository is a mock of WebhookRepository interface.
+type MockWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryMockRecorder
+}
+
+// MockWebhookRepositoryMockRecorder is the mock recorder for MockWebhookRepository.
+type MockWebhookRepositoryMockRecorder struct {
+	mock *MockWebhookRepository
+}
+
+// NewMockWebhookRepository creates a new mock instance.
+func NewMockWebhookRepository(ctrl *gomock.Controller) *MockWebhookRepository {
+	mock := &MockWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepository) EXPECT() *MockWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimPendingDeliveries mocks base method.
+func (m *MockWebhookRepository) ClaimPendingDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPendingDeliveries", ctx, limit, lease)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// ClaimPendingDeliveries indicates an expected call of ClaimPendingDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) ClaimPendingDeliveries(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPendingDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).ClaimPendingDeliveries), ctx, limit, lease)
+}
+
+// DeleteSubscription mocks base method.
+func (m *MockWebhookRepository) DeleteSubscription(ctx context.Context, subscriptionID uuid.UUID) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscription", ctx, subscriptionID)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// DeleteSubscription indicates an expected call of DeleteSubscription.
+func (mr *MockWebhookRepositoryMockRecorder) DeleteSubscription(ctx, subscriptionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockWebhookRepository)(nil).DeleteSubscription), ctx, subscriptionID)
+}
+
+// EnqueueDeliveries mocks base method.
+func (m *MockWebhookRepository) EnqueueDeliveries(ctx context.Context, eventID uuid.UUID, eventType model.OutboxEventType, payload json.RawMessage) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueDeliveries", ctx, eventID, eventType, payload)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// EnqueueDeliveries indicates an expected call of EnqueueDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) EnqueueDeliveries(ctx, eventID, eventType, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).EnqueueDeliveries), ctx, eventID, eventType, payload)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockWebhookRepository) GetDeliveries(ctx context.Context, filter model.WebhookDeliveryFilter) ([]model.WebhookDelivery, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, filter)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) GetDeliveries(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).GetDeliveries), ctx, filter)
+}
+
+// GetDelivery mocks base method.
+func (m *MockWebhookRepository) GetDelivery(ctx context.Context, subscriptionID, deliveryID uuid.UUID) (*model.WebhookDelivery, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelivery", ctx, subscriptionID, deliveryID)
+	ret0, _ := ret[0].(*model.WebhookDelivery)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// GetDelivery indicates an expected call of GetDelivery.
+func (mr *MockWebhookRepositoryMockRecorder) GetDelivery(ctx, subscriptionID, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelivery", reflect.TypeOf((*MockWebhookRepository)(nil).GetDelivery), ctx, subscriptionID, deliveryID)
+}
+
+// GetSubscription mocks base method.
+func (m *MockWebhookRepository) GetSubscription(ctx context.Context, subscriptionID uuid.UUID) (*model.WebhookSubscription, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", ctx, subscriptionID)
+	ret0, _ := ret[0].(*model.WebhookSubscription)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockWebhookRepositoryMockRecorder) GetSubscription(ctx, subscriptionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockWebhookRepository)(nil).GetSubscription), ctx, subscriptionID)
+}
+
+// GetSubscriptions mocks base method.
+func (m *MockWebhookRepository) GetSubscriptions(ctx context.Context) ([]model.WebhookSubscription, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptions", ctx)
+	ret0, _ := ret[0].([]model.WebhookSubscription)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// GetSubscriptions indicates an expected call of GetSubscriptions.
+func (mr *MockWebhookRepositoryMockRecorder) GetSubscriptions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptions", reflect.TypeOf((*MockWebhookRepository)(nil).GetSubscriptions), ctx)
+}
+
+// MarkDeadLettered mocks base method.
+func (m *MockWebhookRepository) MarkDeadLettered(ctx context.Context, deliveryID uuid.UUID, result model.WebhookDeliveryResult) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDeadLettered", ctx, deliveryID, result)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// MarkDeadLettered indicates an expected call of MarkDeadLettered.
+func (mr *MockWebhookRepositoryMockRecorder) MarkDeadLettered(ctx, deliveryID, result interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDeadLettered", reflect.TypeOf((*MockWebhookRepository)(nil).MarkDeadLettered), ctx, deliveryID, result)
+}
+
+// MarkDelivered mocks base method.
+func (m *MockWebhookRepository) MarkDelivered(ctx context.Context, deliveryID uuid.UUID, statusCode int, deliveredAt time.Time) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDelivered", ctx, deliveryID, statusCode, deliveredAt)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// MarkDelivered indicates an expected call of MarkDelivered.
+func (mr *MockWebhookRepositoryMockRecorder) MarkDelivered(ctx, deliveryID, statusCode, deliveredAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDelivered", reflect.TypeOf((*MockWebhookRepository)(nil).MarkDelivered), ctx, deliveryID, statusCode, deliveredAt)
+}
+
+// MarkFailed mocks base method.
+func (m *MockWebhookRepository) MarkFailed(ctx context.Context, deliveryID uuid.UUID, result model.WebhookDeliveryResult, nextAttemptAt time.Time) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, deliveryID, result, nextAttemptAt)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockWebhookRepositoryMockRecorder) MarkFailed(ctx, deliveryID, result, nextAttemptAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockWebhookRepository)(nil).MarkFailed), ctx, deliveryID, result, nextAttemptAt)
+}
+
+// Redeliver mocks base method.
+func (m *MockWebhookRepository) Redeliver(ctx context.Context, deliveryID uuid.UUID, nextAttemptAt time.Time) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, deliveryID, nextAttemptAt)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockWebhookRepositoryMockRecorder) Redeliver(ctx, deliveryID, nextAttemptAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockWebhookRepository)(nil).Redeliver), ctx, deliveryID, nextAttemptAt)
+}
+
+// SaveSubscription mocks base method.
+func (m *MockWebhookRepository) SaveSubscription(ctx context.Context, subscription model.WebhookSubscription) (uuid.UUID, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSubscription", ctx, subscription)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// SaveSubscription indicates an expected call of SaveSubscription.
+func (mr *MockWebhookRepositoryMockRecorder) SaveSubscription(ctx, subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSubscription", reflect.TypeOf((*MockWebhookRepository)(nil).SaveSubscription), ctx, subscription)
+}
+
+// UpdateSubscription mocks base method.
+func (m *MockWebhookRepository) UpdateSubscription(ctx context.Context, subscription model.WebhookSubscription) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSubscription", ctx, subscription)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// UpdateSubscription indicates an expected call of UpdateSubscription.
+func (mr *MockWebhookRepositoryMockRecorder) UpdateSubscription(ctx, subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscription", reflect.TypeOf((*MockWebhookRepository)(nil).UpdateSubscription), ctx, subscription)
+}
+
+// MockIdempotencyRepository is a mock of IdempotencyRepository interface.
+type MockIdempotencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRepositoryMockRecorder
+}
+
+// MockIdempotencyRepositoryMockRecorder is the mock recorder for MockIdempotencyRepository.
+type MockIdempotencyRepositoryMockRecorder struct {
+	mock *MockIdempotencyRepository
+}
+
+// NewMockIdempotencyRepository creates a new mock instance.
+func NewMockIdempotencyRepository(ctrl *gomock.Controller) *MockIdempotencyRepository {
+	mock := &MockIdempotencyRepository{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyRepository) EXPECT() *MockIdempotencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockIdempotencyRepository) Claim(ctx context.Context, record model.IdempotencyRecord, now time.Time) (*model.IdempotencyRecord, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, record, now)
+	ret0, _ := ret[0].(*model.IdempotencyRecord)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockIdempotencyRepositoryMockRecorder) Claim(ctx, record, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockIdempotencyRepository)(nil).Claim), ctx, record, now)
+}
+
+// Complete mocks base method.
+func (m *MockIdempotencyRepository) Complete(ctx context.Context, record model.IdempotencyRecord) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, record)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyRepositoryMockRecorder) Complete(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotencyRepository)(nil).Complete), ctx, record)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockIdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time, limit int) (int, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, before, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockIdempotencyRepositoryMockRecorder) DeleteExpired(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockIdempotencyRepository)(nil).DeleteExpired), ctx, before, limit)
+}
+
+// Release mocks base method.
+func (m *MockIdempotencyRepository) Release(ctx context.Context, record model.IdempotencyRecord) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, record)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIdempotencyRepositoryMockRecorder) Release(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIdempotencyRepository)(nil).Release), ctx, record)
+}
+
+// MockTransactionManager is a mock of TransactionManager interface.
+type MockTransactionManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionManagerMockRecorder
+}
+
+// MockTransactionManagerMockRecorder is the mock recorder for MockTransactionManager.
+type MockTransactionManagerMockRecorder struct {
+	mock *MockTransactionManager
+}
+
+// NewMockTransactionManager creates a new mock instance.
+func NewMockTransactionManager(ctrl *gomock.Controller) *MockTransactionManager {
+	mock := &MockTransactionManager{ctrl: ctrl}
+	mock.recorder = &MockTransactionManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionManager) EXPECT() *MockTransactionManagerMockRecorder {
+	return m.recorder
+}
+
+// WithinTransaction mocks base method.
+func (m *MockTransactionManager) WithinTransaction(ctx context.Context, fn func(context.Context) *common.CustomError) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTransaction", ctx, fn)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// WithinTransaction indicates an expected call of WithinTransaction.
+func (mr *MockTransactionManagerMockRecorder) WithinTransaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTransaction", reflect.TypeOf((*MockTransactionManager)(nil).WithinTransaction), ctx, fn)
+}
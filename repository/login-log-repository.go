@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/SawitProRecruitment/UserService/common"
 	"github.com/SawitProRecruitment/UserService/model"
@@ -10,7 +11,10 @@ import (
 )
 
 type LoginLogRepositoryImplOptions struct {
-	DB *sql.DB
+	DB           *sql.DB
+	QueryTimeout time.Duration
+	// Replicas serve the read-only queries when set.
+	Replicas *ReplicaRouter
 }
 
 type LoginLogRepositoryImpl struct {
@@ -24,12 +28,59 @@ func NewLoginLogRepositoryImpl(opts LoginLogRepositoryImplOptions) *LoginLogRepo
 }
 
 func (r *LoginLogRepositoryImpl) Save(ctx context.Context, log model.LoginLog) (uuid.UUID, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
 	query := `INSERT INTO login_logs (id, user_id, login_at) VALUES ($1, $2, $3);`
 
 	log.ID = uuid.New()
 
-	if _, err := r.opts.DB.ExecContext(ctx, query, log.ID.String(), log.UserID.String(), log.LoginAt); err != nil {
-		return uuid.Nil, common.NewCustomError(common.ErrUnexpectedError, err.Error())
+	if _, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, log.ID.String(), log.UserID.String(), log.LoginAt); err != nil {
+		return uuid.Nil, databaseError(err)
 	}
 	return log.ID, nil
 }
+
+func (r *LoginLogRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.LoginLog, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `SELECT id, login_at FROM login_logs WHERE user_id = $1 ORDER BY login_at DESC;`
+
+	var logs []model.LoginLog
+	err := readOnly(ctx, r.opts.Replicas, r.opts.DB, func(db dbConn) error {
+		rows, err := db.QueryContext(ctx, query, userID.String())
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		logs = []model.LoginLog{}
+		for rows.Next() {
+			log := model.LoginLog{
+				UserID: userID,
+			}
+			if err := rows.Scan(&log.ID, &log.LoginAt); err != nil {
+				return err
+			}
+			logs = append(logs, log)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	return logs, nil
+}
+
+func (r *LoginLogRepositoryImpl) DeleteByUserID(ctx context.Context, userID uuid.UUID) *common.CustomError {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `DELETE FROM login_logs WHERE user_id = $1;`
+
+	if _, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, userID.String()); err != nil {
+		return databaseError(err)
+	}
+	return nil
+}
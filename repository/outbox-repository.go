@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type OutboxRepositoryImplOptions struct {
+	DB           *sql.DB
+	QueryTimeout time.Duration
+}
+
+type OutboxRepositoryImpl struct {
+	opts *OutboxRepositoryImplOptions
+}
+
+func NewOutboxRepositoryImpl(opts OutboxRepositoryImplOptions) *OutboxRepositoryImpl {
+	return &OutboxRepositoryImpl{
+		opts: &opts,
+	}
+}
+
+// ClaimPending leases up to limit events that are due for delivery. A claimed event is not handed out
+// again until the lease expires, so an event whose relay dies mid-flight is retried by another one.
+func (r *OutboxRepositoryImpl) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEvent, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `UPDATE outbox_events SET next_attempt_at = now() + $2 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE published_at IS NULL AND next_attempt_at <= now()
+			ORDER BY created_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, aggregate_id, payload, created_at, attempts;`
+
+	rows, err := conn(ctx, r.opts.DB).QueryContext(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	defer rows.Close()
+
+	events := []model.OutboxEvent{}
+	for rows.Next() {
+		var event model.OutboxEvent
+		if err := rows.Scan(&event.ID, &event.EventType, &event.AggregateID, &event.Payload, &event.CreatedAt, &event.Attempts); err != nil {
+			return nil, databaseError(err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, databaseError(err)
+	}
+	return events, nil
+}
+
+func (r *OutboxRepositoryImpl) MarkPublished(ctx context.Context, eventID uuid.UUID, publishedAt time.Time) *common.CustomError {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `UPDATE outbox_events SET published_at = $2, attempts = attempts + 1, last_error = NULL WHERE id = $1;`
+
+	if _, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, eventID.String(), publishedAt); err != nil {
+		return databaseError(err)
+	}
+	return nil
+}
+
+func (r *OutboxRepositoryImpl) MarkFailed(ctx context.Context, eventID uuid.UUID, lastError string, nextAttemptAt time.Time) *common.CustomError {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `UPDATE outbox_events SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3 WHERE id = $1;`
+
+	if _, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, eventID.String(), lastError, nextAttemptAt); err != nil {
+		return databaseError(err)
+	}
+	return nil
+}
+
+// DeletePublishedBefore deletes up to limit events published before the given time and returns how many it
+// deleted.
+func (r *OutboxRepositoryImpl) DeletePublishedBefore(ctx context.Context, before time.Time, limit int) (int, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `DELETE FROM outbox_events WHERE id IN (SELECT id FROM outbox_events WHERE published_at < $1 LIMIT $2);`
+
+	result, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, databaseError(err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, databaseError(err)
+	}
+	return int(deleted), nil
+}
+
+// scrubUserEvents removes the personal data of a user from the outbox events about it and from their webhook
+// deliveries, which hold the whole envelope with the event payload under "payload".
+func scrubUserEvents(ctx context.Context, tx dbConn, userID uuid.UUID) error {
+	outboxQuery := `UPDATE outbox_events SET payload = payload - $2::text[] WHERE aggregate_id = $1 AND payload ?| $2::text[];`
+	deliveryQuery := `UPDATE webhook_deliveries SET payload = jsonb_set(payload, '{payload}', (payload -> 'payload') - $2::text[])
+		WHERE payload ->> 'aggregate_id' = $1 AND payload -> 'payload' ?| $2::text[];`
+
+	fields := pq.Array(model.PersonalUserEventFields)
+	if _, err := tx.ExecContext(ctx, outboxQuery, userID.String(), fields); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, deliveryQuery, userID.String(), fields)
+	return err
+}
+
+// insertOutboxEvent stores an event in the transaction of the write it describes.
+func insertOutboxEvent(ctx context.Context, tx dbConn, eventType model.OutboxEventType, payload model.UserEventPayload) error {
+	query := `INSERT INTO outbox_events (id, event_type, aggregate_id, payload, created_at) VALUES ($1, $2, $3, $4, $5);`
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, query, uuid.New().String(), eventType, payload.UserID.String(), data, payload.OccurredAt)
+	return err
+}
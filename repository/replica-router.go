@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/logging"
+)
+
+const defaultReplicaCheckInterval = 5 * time.Second
+
+// replicaLagQuery returns how far a replica is behind the primary in seconds. A replica that has replayed
+// everything it received is not behind, even when the primary has been idle since its last transaction.
+const replicaLagQuery = `SELECT CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0) END;`
+
+type ReplicaRouterOptions struct {
+	Replicas []*sql.DB
+	// CheckInterval is how often the replicas are checked, every check gets as much time. Defaults to 5
+	// seconds.
+	CheckInterval time.Duration
+	// MaxLag is how far a replica may fall behind the primary before reads avoid it, zero for no limit.
+	MaxLag time.Duration
+	// Logger defaults to logging.Default().
+	Logger *logging.Logger
+}
+
+// ReplicaRouter spreads read-only queries over the healthy read replicas. Replicas start out unhealthy and
+// are checked by Run, so reads go to the primary until a replica has passed its first check.
+type ReplicaRouter struct {
+	opts    *ReplicaRouterOptions
+	healthy []atomic.Bool
+	next    atomic.Uint64
+}
+
+func NewReplicaRouter(opts ReplicaRouterOptions) *ReplicaRouter {
+	if opts.CheckInterval == 0 {
+		opts.CheckInterval = defaultReplicaCheckInterval
+	}
+	if opts.Logger == nil {
+		opts.Logger = logging.Default()
+	}
+	return &ReplicaRouter{
+		opts:    &opts,
+		healthy: make([]atomic.Bool, len(opts.Replicas)),
+	}
+}
+
+// Run checks the replicas until ctx is cancelled.
+func (r *ReplicaRouter) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.opts.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		for index, replica := range r.opts.Replicas {
+			r.setHealth(index, r.checkReplica(ctx, replica))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *ReplicaRouter) checkReplica(ctx context.Context, replica *sql.DB) error {
+	ctx, cancel := context.WithTimeout(ctx, r.opts.CheckInterval)
+	defer cancel()
+
+	var lagSeconds float64
+	if err := replica.QueryRowContext(ctx, replicaLagQuery).Scan(&lagSeconds); err != nil {
+		return err
+	}
+
+	lag := time.Duration(lagSeconds * float64(time.Second))
+	if r.opts.MaxLag > 0 && lag > r.opts.MaxLag {
+		return fmt.Errorf("replica is %s behind the primary", lag.Round(time.Millisecond))
+	}
+	return nil
+}
+
+// setHealth marks a replica healthy when err is nil and unhealthy otherwise, logging every change.
+func (r *ReplicaRouter) setHealth(index int, err error) {
+	healthy := err == nil
+	if r.healthy[index].Swap(healthy) == healthy {
+		return
+	}
+
+	if healthy {
+		r.opts.Logger.Info("database replica is healthy", "replica", index)
+	} else {
+		r.opts.Logger.Warn("database replica is unhealthy, reading from the primary instead", "replica", index, "error", err)
+	}
+}
+
+// replica picks the next healthy replica, ok is false when there is none.
+func (r *ReplicaRouter) replica() (index int, db *sql.DB, ok bool) {
+	count := uint64(len(r.opts.Replicas))
+	start := r.next.Add(1)
+	for i := uint64(0); i < count; i++ {
+		index := int((start + i) % count)
+		if r.healthy[index].Load() {
+			return index, r.opts.Replicas[index], true
+		}
+	}
+	return 0, nil, false
+}
+
+// readOnly runs the read-only fn on a healthy replica of router, or on primary when there is none, when
+// ctx carries a transaction, asks for primary reads or already wrote to the primary. fn runs again on the primary when the
+// replica cannot be reached, which marks the replica unhealthy until it passes its next check.
+func readOnly(ctx context.Context, router *ReplicaRouter, primary *sql.DB, fn func(db dbConn) error) error {
+	if router == nil || inTransaction(ctx) || common.ReadsFromPrimary(ctx) || common.WrotePrimary(ctx) {
+		return fn(conn(ctx, primary))
+	}
+
+	index, replica, ok := router.replica()
+	if !ok {
+		return fn(conn(ctx, primary))
+	}
+
+	err := fn(tracedConn{replica})
+	if err != nil && IsConnectionLost(err) {
+		router.setHealth(index, err)
+		return fn(conn(ctx, primary))
+	}
+	if err != nil && ctx.Err() == nil && isReplicaFailure(err) {
+		return fn(conn(ctx, primary))
+	}
+	if served, ok := ctx.Value(replicaReadKey{}).(*atomic.Bool); ok {
+		served.Store(true)
+	}
+	return err
+}
+
+type replicaReadKey struct{}
+
+// withReplicaReads returns a context that records whether a read made with it was served by a replica,
+// which may lag behind the primary.
+func withReplicaReads(ctx context.Context) (context.Context, *atomic.Bool) {
+	served := new(atomic.Bool)
+	return context.WithValue(ctx, replicaReadKey{}, served), served
+}
@@ -0,0 +1,330 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeDatabase records the statements it receives. Lag queries return no lag, every other query returns
+// the rows respond returns for it when set, else the rows of the first entry in results its text contains,
+// or no rows. Statements fail with the error execError returns for them, commits with commitError.
+// Transactions are recorded apart from the statements, as BEGIN, COMMIT and ROLLBACK.
+type fakeDatabase struct {
+	mu           sync.Mutex
+	statements   []string
+	arguments    [][]driver.Value
+	transactions []string
+	isolation    []driver.IsolationLevel
+	unreachable  bool
+	results      map[string][][]driver.Value
+	respond      func(query string, args []driver.Value) [][]driver.Value
+	execError    func(query string, args []driver.Value) error
+	commitError  func() error
+}
+
+func (d *fakeDatabase) Connect(ctx context.Context) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.unreachable {
+		return nil, driver.ErrBadConn
+	}
+	return fakeConn{d}, nil
+}
+
+func (d *fakeDatabase) Driver() driver.Driver { return nil }
+
+func (d *fakeDatabase) setUnreachable() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.unreachable = true
+}
+
+// received returns the statements received so far, leaving out replica checks.
+func (d *fakeDatabase) received() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var statements []string
+	for _, statement := range d.statements {
+		if !strings.Contains(statement, "pg_last_wal_receive_lsn") {
+			statements = append(statements, strings.Fields(statement)[0]+" "+strings.Fields(statement)[2])
+		}
+	}
+	return statements
+}
+
+// queries returns every statement received so far in full.
+func (d *fakeDatabase) queries() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.statements...)
+}
+
+// argumentsOf returns the arguments of every statement received so far that contains text.
+func (d *fakeDatabase) argumentsOf(text string) [][]driver.Value {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var arguments [][]driver.Value
+	for i, statement := range d.statements {
+		if strings.Contains(statement, text) {
+			arguments = append(arguments, d.arguments[i])
+		}
+	}
+	return arguments
+}
+
+// transactionsReceived returns the transaction commands received so far.
+func (d *fakeDatabase) transactionsReceived() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.transactions...)
+}
+
+// isolationLevels returns the isolation level of every transaction begun so far.
+func (d *fakeDatabase) isolationLevels() []driver.IsolationLevel {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]driver.IsolationLevel(nil), d.isolation...)
+}
+
+func (d *fakeDatabase) recordTransaction(command string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.transactions = append(d.transactions, command)
+}
+
+func (d *fakeDatabase) record(query string, args []driver.Value) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.unreachable {
+		return driver.ErrBadConn
+	}
+	d.statements = append(d.statements, query)
+	d.arguments = append(d.arguments, args)
+	return nil
+}
+
+func (d *fakeDatabase) result(query string, args []driver.Value) [][]driver.Value {
+	if d.respond != nil {
+		return d.respond(query, args)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for text, values := range d.results {
+		if strings.Contains(query, text) {
+			return append([][]driver.Value(nil), values...)
+		}
+	}
+	return nil
+}
+
+type fakeConn struct {
+	db *fakeDatabase
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.db, query}, nil }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.db.mu.Lock()
+	c.db.isolation = append(c.db.isolation, opts.Isolation)
+	c.db.mu.Unlock()
+	c.db.recordTransaction("BEGIN")
+	return fakeTx{c.db}, nil
+}
+
+type fakeTx struct {
+	db *fakeDatabase
+}
+
+func (t fakeTx) Commit() error {
+	t.db.recordTransaction("COMMIT")
+	if t.db.commitError != nil {
+		return t.db.commitError()
+	}
+	return nil
+}
+
+func (t fakeTx) Rollback() error {
+	t.db.recordTransaction("ROLLBACK")
+	return nil
+}
+
+type fakeStmt struct {
+	db    *fakeDatabase
+	query string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if err := s.db.record(s.query, args); err != nil {
+		return nil, err
+	}
+	if s.db.execError != nil {
+		if err := s.db.execError(s.query, args); err != nil {
+			return nil, err
+		}
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if err := s.db.record(s.query, args); err != nil {
+		return nil, err
+	}
+	if s.db.execError != nil {
+		if err := s.db.execError(s.query, args); err != nil {
+			return nil, err
+		}
+	}
+	if strings.Contains(s.query, "pg_last_wal_receive_lsn") {
+		return &fakeRows{values: [][]driver.Value{{float64(0)}}}, nil
+	}
+	return &fakeRows{values: s.db.result(s.query, args)}, nil
+}
+
+type fakeRows struct {
+	values [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	if len(r.values) == 0 {
+		return []string{"value"}
+	}
+	return make([]string, len(r.values[0]))
+}
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+type ReplicaRouterTestSuite struct {
+	suite.Suite
+	primary            *fakeDatabase
+	replica            *fakeDatabase
+	cancel             context.CancelFunc
+	loginLogRepository *repository.LoginLogRepositoryImpl
+	userRepository     *repository.UserRepositoryImpl
+}
+
+func (s *ReplicaRouterTestSuite) SetupTest() {
+	s.primary = &fakeDatabase{}
+	s.replica = &fakeDatabase{}
+
+	router := repository.NewReplicaRouter(repository.ReplicaRouterOptions{
+		Replicas:      []*sql.DB{sql.OpenDB(s.replica)},
+		CheckInterval: time.Hour,
+	})
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	go router.Run(ctx)
+
+	s.loginLogRepository = repository.NewLoginLogRepositoryImpl(repository.LoginLogRepositoryImplOptions{
+		DB:       sql.OpenDB(s.primary),
+		Replicas: router,
+	})
+	s.userRepository = repository.NewUserRepository(repository.UserRepositoryImplOptions{
+		DB:       sql.OpenDB(s.primary),
+		Replicas: router,
+	})
+
+	// The replica serves reads once its first check has passed.
+	s.Require().Eventually(func() bool {
+		_, err := s.loginLogRepository.GetByUserID(context.Background(), uuid.New())
+		return err == nil && len(s.replica.received()) > 0
+	}, time.Second, time.Millisecond)
+}
+
+func (s *ReplicaRouterTestSuite) TearDownTest() {
+	s.cancel()
+}
+
+func TestReplicaRouter(t *testing.T) {
+	suite.Run(t, new(ReplicaRouterTestSuite))
+}
+
+func (s *ReplicaRouterTestSuite) TestGetByUserIDShouldReadFromReplica() {
+	_, err := s.loginLogRepository.GetByUserID(context.Background(), uuid.New())
+
+	s.Nil(err)
+	s.Empty(s.primary.received())
+}
+
+func (s *ReplicaRouterTestSuite) TestGetByUserIDAfterWriteInSameRequestShouldReadFromPrimary() {
+	ctx := common.WithReadYourWrites(context.Background())
+
+	_, err := s.loginLogRepository.Save(ctx, model.LoginLog{UserID: uuid.New(), LoginAt: time.Now()})
+	s.Require().Nil(err)
+	_, err = s.loginLogRepository.GetByUserID(ctx, uuid.New())
+
+	s.Nil(err)
+	s.Equal([]string{"INSERT login_logs", "SELECT login_at"}, s.primary.received())
+}
+
+func (s *ReplicaRouterTestSuite) TestGetByUserIDGivenPrimaryReadsShouldReadFromPrimary() {
+	_, err := s.loginLogRepository.GetByUserID(common.WithPrimaryReads(context.Background()), uuid.New())
+
+	s.Nil(err)
+	s.Equal([]string{"SELECT login_at"}, s.primary.received())
+}
+
+func (s *ReplicaRouterTestSuite) TestGetAccessStateShouldReadFromPrimary() {
+	s.primary.results = map[string][][]driver.Value{"SELECT status, tokens_revoked_at": {{"active", nil}}}
+
+	state, err := s.userRepository.GetAccessState(context.Background(), uuid.New())
+
+	s.Require().Nil(err)
+	s.Equal(model.UserStatusActive, state.Status)
+	s.Equal([]string{"SELECT tokens_revoked_at"}, s.primary.received())
+}
+
+func (s *ReplicaRouterTestSuite) TestGetByUserIDGivenReplicaFailureShouldFallBackToPrimary() {
+	for _, code := range []pq.ErrorCode{"40001", "57014"} {
+		s.replica.execError = func(query string, args []driver.Value) error {
+			return &pq.Error{Code: code}
+		}
+		primaryReads := len(s.primary.received())
+
+		_, err := s.loginLogRepository.GetByUserID(context.Background(), uuid.New())
+
+		s.Nil(err, code)
+		s.Len(s.primary.received(), primaryReads+1, code)
+	}
+}
+
+func (s *ReplicaRouterTestSuite) TestGetByUserIDGivenUnreachableReplicaShouldFallBackToPrimary() {
+	s.replica.setUnreachable()
+
+	_, err := s.loginLogRepository.GetByUserID(context.Background(), uuid.New())
+	s.Require().Nil(err)
+	_, err = s.loginLogRepository.GetByUserID(context.Background(), uuid.New())
+	s.Require().Nil(err)
+
+	s.Equal([]string{"SELECT login_at", "SELECT login_at"}, s.primary.received())
+}
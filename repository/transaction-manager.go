@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/tracing"
+)
+
+const defaultTransactionRetryBackoff = 10 * time.Millisecond
+
+type TransactionManagerImplOptions struct {
+	DB *sql.DB
+	// Isolation is the isolation level of new transactions, the database default (read committed) when zero.
+	Isolation sql.IsolationLevel
+	// MaxRetries is how often a transaction aborted by a serialization failure or deadlock is retried.
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, it doubles with every further retry.
+	RetryBackoff time.Duration
+}
+
+// TransactionManagerImpl runs functions in a database transaction stored in the context. Every repository
+// call made with that context joins the transaction instead of using its own connection.
+type TransactionManagerImpl struct {
+	opts *TransactionManagerImplOptions
+}
+
+func NewTransactionManagerImpl(opts TransactionManagerImplOptions) *TransactionManagerImpl {
+	if opts.RetryBackoff == 0 {
+		opts.RetryBackoff = defaultTransactionRetryBackoff
+	}
+	return &TransactionManagerImpl{
+		opts: &opts,
+	}
+}
+
+// WithinTransaction runs fn in a transaction that is committed when fn returns nil and rolled back otherwise.
+// When ctx already carries a transaction fn simply joins it, the outermost call decides about the commit.
+// fn may run more than once, so it must not have side effects outside the database.
+func (m *TransactionManagerImpl) WithinTransaction(ctx context.Context, fn func(ctx context.Context) *common.CustomError) *common.CustomError {
+	if _, ok := ctx.Value(common.KeyTransaction).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	backoff := m.opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := m.runOnce(ctx, fn)
+		if err == nil || err.ErrType != common.ErrTransactionConflict || attempt >= m.opts.MaxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return databaseError(ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (m *TransactionManagerImpl) runOnce(ctx context.Context, fn func(ctx context.Context) *common.CustomError) (errTx *common.CustomError) {
+	ctx, span := tracing.Start(ctx, "db transaction", tracing.AsChildOnly())
+	defer func() {
+		if errTx != nil {
+			span.SetError(errTx.Message)
+		}
+		span.End()
+	}()
+
+	tx, err := m.opts.DB.BeginTx(ctx, &sql.TxOptions{Isolation: m.opts.Isolation})
+	if err != nil {
+		return databaseError(err)
+	}
+	defer tx.Rollback()
+	common.MarkPrimaryWrite(ctx)
+
+	var hooks []func()
+	txCtx := context.WithValue(ctx, common.KeyTransaction, tx)
+	txCtx = context.WithValue(txCtx, afterCommitKey{}, &hooks)
+	if err := fn(txCtx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return databaseError(err)
+	}
+	for _, hook := range hooks {
+		hook()
+	}
+	return nil
+}
+
+type afterCommitKey struct{}
+
+// afterCommit runs fn once the ambient transaction of ctx has been committed, and never when it is rolled
+// back. Without an ambient transaction fn runs right away.
+func afterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(afterCommitKey{}).(*[]func())
+	if !ok {
+		fn()
+		return
+	}
+	*hooks = append(*hooks, fn)
+}
+
+// inTransaction reports whether ctx carries an ambient transaction.
+func inTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(common.KeyTransaction).(*sql.Tx)
+	return ok
+}
+
+// dbConn is the part of *sql.DB and *sql.Tx the repositories use.
+type dbConn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn returns the ambient transaction of ctx, or db when there is none.
+func conn(ctx context.Context, db *sql.DB) dbConn {
+	if tx, ok := ctx.Value(common.KeyTransaction).(*sql.Tx); ok {
+		return tracedConn{tx}
+	}
+	return tracedConn{db}
+}
+
+// withQueryTimeout bounds a repository operation, including reading its rows, so that a stuck connection
+// cannot hold a request forever. A zero timeout leaves ctx as it is.
+func withQueryTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// withTx runs fn in the ambient transaction of ctx, or in a new transaction that is committed when fn
+// succeeds and rolled back otherwise.
+func withTx(ctx context.Context, db *sql.DB, fn func(tx dbConn) error) error {
+	if tx, ok := ctx.Value(common.KeyTransaction).(*sql.Tx); ok {
+		return fn(tracedConn{tx})
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	common.MarkPrimaryWrite(ctx)
+
+	if err := fn(tracedConn{tx}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// tracedConn records a span for every query of a traced request. Spans of queries returning rows end when
+// the first row is available, reading the rows is not included. Statements other than SELECT are recorded
+// as writes to the primary, see common.WithReadYourWrites.
+type tracedConn struct {
+	conn dbConn
+}
+
+func (c tracedConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	markWrite(ctx, query)
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	result, err := c.conn.ExecContext(ctx, query, args...)
+	setQueryError(span, err)
+	return result, err
+}
+
+func (c tracedConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	markWrite(ctx, query)
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	rows, err := c.conn.QueryContext(ctx, query, args...)
+	setQueryError(span, err)
+	return rows, err
+}
+
+func (c tracedConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	markWrite(ctx, query)
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	row := c.conn.QueryRowContext(ctx, query, args...)
+	setQueryError(span, row.Err())
+	return row
+}
+
+// startQuerySpan names the span after the SQL operation. The statement is recorded without its arguments,
+// which hold personal data.
+func startQuerySpan(ctx context.Context, query string) (context.Context, *tracing.Span) {
+	operation := queryOperation(query)
+
+	ctx, span := tracing.Start(ctx, "db "+operation, tracing.WithSpanKind(tracing.SpanKindClient), tracing.AsChildOnly())
+	span.SetAttribute("db.system", "postgresql")
+	span.SetAttribute("db.operation", operation)
+	span.SetAttribute("db.statement", query)
+	return ctx, span
+}
+
+// markWrite records statements other than SELECT as writes to the primary.
+func markWrite(ctx context.Context, query string) {
+	if queryOperation(query) != "SELECT" {
+		common.MarkPrimaryWrite(ctx)
+	}
+}
+
+// queryOperation returns the SQL command of query, such as SELECT or UPDATE.
+func queryOperation(query string) string {
+	if fields := strings.Fields(query); len(fields) > 0 {
+		return strings.ToUpper(fields[0])
+	}
+	return "QUERY"
+}
+
+func setQueryError(span *tracing.Span, err error) {
+	if err != nil && err != sql.ErrNoRows {
+		span.SetError(err.Error())
+	}
+}
+
+// detachedContext keeps the values of its parent but not its deadline or cancellation.
+type detachedContext struct {
+	parent context.Context
+}
+
+// detach returns a context with the values of ctx that is never cancelled.
+func detach(ctx context.Context) context.Context {
+	return detachedContext{parent: ctx}
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
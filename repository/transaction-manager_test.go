@@ -0,0 +1,186 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/suite"
+)
+
+type TransactionManagerTestSuite struct {
+	suite.Suite
+	db                 *fakeDatabase
+	otherDB            *fakeDatabase
+	loginLogRepository *repository.LoginLogRepositoryImpl
+	sut                *repository.TransactionManagerImpl
+}
+
+func (s *TransactionManagerTestSuite) SetupTest() {
+	s.db = &fakeDatabase{}
+	s.otherDB = &fakeDatabase{}
+	// The repository has a database of its own, so its statements only reach s.db through the transaction.
+	s.loginLogRepository = repository.NewLoginLogRepositoryImpl(repository.LoginLogRepositoryImplOptions{
+		DB: sql.OpenDB(s.otherDB),
+	})
+	s.sut = s.newTransactionManager(repository.TransactionManagerImplOptions{MaxRetries: 2, RetryBackoff: time.Millisecond})
+}
+
+func (s *TransactionManagerTestSuite) newTransactionManager(opts repository.TransactionManagerImplOptions) *repository.TransactionManagerImpl {
+	opts.DB = sql.OpenDB(s.db)
+	return repository.NewTransactionManagerImpl(opts)
+}
+
+func TestTransactionManager(t *testing.T) {
+	suite.Run(t, new(TransactionManagerTestSuite))
+}
+
+func (s *TransactionManagerTestSuite) TestWithinTransactionShouldCommitWhenFnSucceeds() {
+	err := s.sut.WithinTransaction(context.Background(), func(ctx context.Context) *common.CustomError {
+		return s.loginLogRepository.DeleteByUserID(ctx, uuid.New())
+	})
+
+	s.Require().Nil(err)
+	s.Equal([]string{"BEGIN", "COMMIT"}, s.db.transactionsReceived())
+	s.Equal([]string{"DELETE login_logs"}, s.db.received())
+	s.Empty(s.otherDB.queries())
+}
+
+func (s *TransactionManagerTestSuite) TestWithinTransactionShouldRollBackWhenFnFails() {
+	err := s.sut.WithinTransaction(context.Background(), func(ctx context.Context) *common.CustomError {
+		if err := s.loginLogRepository.DeleteByUserID(ctx, uuid.New()); err != nil {
+			return err
+		}
+		return common.NewCustomError(common.ErrInvalidInput, "invalid request params")
+	})
+
+	s.Equal(common.ErrInvalidInput, err.ErrType)
+	s.Equal([]string{"BEGIN", "ROLLBACK"}, s.db.transactionsReceived())
+}
+
+func (s *TransactionManagerTestSuite) TestWithinTransactionGivenAmbientTransactionShouldJoinIt() {
+	err := s.sut.WithinTransaction(context.Background(), func(ctx context.Context) *common.CustomError {
+		return s.sut.WithinTransaction(ctx, func(ctx context.Context) *common.CustomError {
+			return s.loginLogRepository.DeleteByUserID(ctx, uuid.New())
+		})
+	})
+
+	s.Require().Nil(err)
+	s.Equal([]string{"BEGIN", "COMMIT"}, s.db.transactionsReceived())
+	s.Equal([]string{"DELETE login_logs"}, s.db.received())
+}
+
+// An inner failure is returned without being retried, so the outermost call rolls the whole transaction back.
+func (s *TransactionManagerTestSuite) TestWithinTransactionGivenAmbientTransactionShouldLeaveRollbackToOutermostCall() {
+	calls := 0
+	err := s.sut.WithinTransaction(context.Background(), func(ctx context.Context) *common.CustomError {
+		return s.sut.WithinTransaction(ctx, func(ctx context.Context) *common.CustomError {
+			calls++
+			return common.NewCustomError(common.ErrInvalidInput, "invalid request params")
+		})
+	})
+
+	s.Equal(common.ErrInvalidInput, err.ErrType)
+	s.Equal(1, calls)
+	s.Equal([]string{"BEGIN", "ROLLBACK"}, s.db.transactionsReceived())
+}
+
+func (s *TransactionManagerTestSuite) TestWithinTransactionShouldUseConfiguredIsolation() {
+	s.sut = s.newTransactionManager(repository.TransactionManagerImplOptions{Isolation: sql.LevelSerializable})
+
+	err := s.sut.WithinTransaction(context.Background(), func(ctx context.Context) *common.CustomError { return nil })
+
+	s.Require().Nil(err)
+	s.Equal([]driver.IsolationLevel{driver.IsolationLevel(sql.LevelSerializable)}, s.db.isolationLevels())
+}
+
+func (s *TransactionManagerTestSuite) TestWithinTransactionGivenConflictShouldRetry() {
+	calls := 0
+	err := s.sut.WithinTransaction(context.Background(), func(ctx context.Context) *common.CustomError {
+		calls++
+		if calls < 3 {
+			return common.NewCustomError(common.ErrTransactionConflict, "transaction conflicted with a concurrent request, please retry")
+		}
+		return nil
+	})
+
+	s.Require().Nil(err)
+	s.Equal(3, calls)
+	s.Equal([]string{"BEGIN", "ROLLBACK", "BEGIN", "ROLLBACK", "BEGIN", "COMMIT"}, s.db.transactionsReceived())
+}
+
+func (s *TransactionManagerTestSuite) TestWithinTransactionGivenSerializationFailureOnCommitShouldRetry() {
+	commits := 0
+	s.db.commitError = func() error {
+		commits++
+		if commits == 1 {
+			return &pq.Error{Code: "40001"}
+		}
+		return nil
+	}
+
+	calls := 0
+	err := s.sut.WithinTransaction(context.Background(), func(ctx context.Context) *common.CustomError {
+		calls++
+		return nil
+	})
+
+	s.Require().Nil(err)
+	s.Equal(2, calls)
+}
+
+func (s *TransactionManagerTestSuite) TestWithinTransactionGivenPersistentConflictShouldGiveUpAfterMaxRetries() {
+	calls := 0
+	err := s.sut.WithinTransaction(context.Background(), func(ctx context.Context) *common.CustomError {
+		calls++
+		return common.NewCustomError(common.ErrTransactionConflict, "transaction conflicted with a concurrent request, please retry")
+	})
+
+	s.Equal(common.ErrTransactionConflict, err.ErrType)
+	s.Equal(3, calls)
+}
+
+func (s *TransactionManagerTestSuite) TestWithinTransactionGivenOtherErrorShouldNotRetry() {
+	calls := 0
+	err := s.sut.WithinTransaction(context.Background(), func(ctx context.Context) *common.CustomError {
+		calls++
+		return common.NewCustomError(common.ErrEntityNotFound, "user not found")
+	})
+
+	s.Equal(common.ErrEntityNotFound, err.ErrType)
+	s.Equal(1, calls)
+}
+
+// The retries wait 10ms and 20ms, so three attempts take at least 30ms.
+func (s *TransactionManagerTestSuite) TestWithinTransactionShouldDoubleTheBackoffOnEveryRetry() {
+	s.sut = s.newTransactionManager(repository.TransactionManagerImplOptions{MaxRetries: 2, RetryBackoff: 10 * time.Millisecond})
+
+	start := time.Now()
+	err := s.sut.WithinTransaction(context.Background(), func(ctx context.Context) *common.CustomError {
+		return common.NewCustomError(common.ErrTransactionConflict, "transaction conflicted with a concurrent request, please retry")
+	})
+
+	s.Equal(common.ErrTransactionConflict, err.ErrType)
+	s.GreaterOrEqual(time.Since(start), 30*time.Millisecond)
+}
+
+func (s *TransactionManagerTestSuite) TestWithinTransactionGivenCancelledContextShouldStopRetrying() {
+	s.sut = s.newTransactionManager(repository.TransactionManagerImplOptions{MaxRetries: 2, RetryBackoff: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	err := s.sut.WithinTransaction(ctx, func(ctx context.Context) *common.CustomError {
+		calls++
+		cancel()
+		return common.NewCustomError(common.ErrTransactionConflict, "transaction conflicted with a concurrent request, please retry")
+	})
+
+	s.NotNil(err)
+	s.Equal(1, calls)
+}
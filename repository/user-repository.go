@@ -3,20 +3,51 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/encryption"
 	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/phonenumber"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
 const (
-	postgreSQLConflictErrCode pq.ErrorCode = "23505"
+	userEmailUniqueIndex = "users_email_unique_index"
+
+	anonymizedFullName = "Deleted User"
 )
 
+// patchableUserColumns maps the fields accepted by Patch to their columns. The phone number is encrypted
+// into the phone_number_* columns instead.
+var patchableUserColumns = map[model.UserField]string{
+	model.UserFieldFullName:     "full_name",
+	model.UserFieldPhoneNumber:  "phone_number",
+	model.UserFieldPasswordHash: "password_hash",
+	model.UserFieldEmail:        "email",
+	model.UserFieldDateOfBirth:  "date_of_birth",
+	model.UserFieldLocale:       "locale",
+	model.UserFieldTimezone:     "timezone",
+	model.UserFieldAvatarKey:    "avatar_key",
+}
+
+// userColumns lists the columns read by scanUser, in scan order.
+const userColumns = `id, phone_number, phone_number_ciphertext, full_name, password_hash, status, status_changed_at, tokens_revoked_at, anonymized_at,
+	email, email_verified_at, date_of_birth, locale, timezone, avatar_key, (SELECT MAX(login_at) FROM login_logs WHERE user_id = users.id),
+	version, created_at, updated_at`
+
 type UserRepositoryImplOptions struct {
-	DB *sql.DB
+	DB           *sql.DB
+	QueryTimeout time.Duration
+	// PhoneNumberKeyring encrypts phone numbers, bound to the ID of their user.
+	PhoneNumberKeyring *encryption.Keyring
+	// PhoneNumberIndex finds users by phone number and keeps phone numbers unique.
+	PhoneNumberIndex *encryption.BlindIndex
+	// Replicas serve the read-only queries when set.
+	Replicas *ReplicaRouter
 }
 
 type UserRepositoryImpl struct {
@@ -30,93 +61,527 @@ func NewUserRepository(opts UserRepositoryImplOptions) *UserRepositoryImpl {
 }
 
 func (r *UserRepositoryImpl) Save(ctx context.Context, user model.User) (uuid.UUID, *common.CustomError) {
-	query := `INSERT INTO users (id, phone_number, full_name, password_hash) VALUES ($1, $2, $3, $4);`
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `INSERT INTO users (id, phone_number_ciphertext, phone_number_key_id, phone_number_hash, full_name, password_hash)
+		VALUES ($1, $2, $3, $4, $5, $6);`
 
 	user.ID = uuid.New()
 
-	if _, err := r.opts.DB.ExecContext(ctx, query, user.ID.String(), user.PhoneNumber, user.FullName, user.PasswordHash); err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			if pqErr.Code == postgreSQLConflictErrCode {
-				return uuid.Nil, common.NewCustomError(common.ErrEntityAlreadyExists, "phone number is already used")
-			}
+	phoneNumber, errEncrypt := r.encryptPhoneNumber(user.ID, user.PhoneNumber)
+	if errEncrypt != nil {
+		return uuid.Nil, errEncrypt
+	}
+
+	err := withTx(ctx, r.opts.DB, func(tx dbConn) error {
+		if err := checkPlaintextPhoneNumber(ctx, tx, user.ID, user.PhoneNumber); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, query, user.ID.String(), phoneNumber.ciphertext, phoneNumber.keyID, phoneNumber.hash, user.FullName, user.PasswordHash)
+		if err != nil {
+			return err
 		}
-		return uuid.Nil, common.NewCustomError(common.ErrUnexpectedError, err.Error())
+
+		return insertOutboxEvent(ctx, tx, model.OutboxEventUserRegistered, model.UserEventPayload{
+			UserID:     user.ID,
+			FullName:   &user.FullName,
+			OccurredAt: time.Now(),
+		})
+	})
+	if err != nil {
+		if errConflict := userConflictError(err); errConflict != nil {
+			return uuid.Nil, errConflict
+		}
+		return uuid.Nil, databaseError(err)
 	}
 	return user.ID, nil
 }
 
 func (r *UserRepositoryImpl) GetByPhoneNumber(ctx context.Context, phoneNumber string) (*model.User, *common.CustomError) {
-	query := `SELECT id, full_name, password_hash FROM users WHERE phone_number = $1;`
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
 
-	user := model.User{
-		PhoneNumber: phoneNumber,
-	}
+	// Numbers the re-encryption job has not encrypted yet are still matched in plaintext.
+	query := `SELECT ` + userColumns + ` FROM users WHERE phone_number_hash = $1 OR phone_number = $2;`
 
-	if err := r.opts.DB.QueryRowContext(ctx, query, user.PhoneNumber).Scan(&user.ID, &user.FullName, &user.PasswordHash); err != nil {
+	user, err := r.scanUser(conn(ctx, r.opts.DB).QueryRowContext(ctx, query, r.opts.PhoneNumberIndex.Compute(phoneNumber), phoneNumber))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.NewCustomError(common.ErrEntityNotFound, "user does not exist in database")
 		}
-		return nil, common.NewCustomError(common.ErrUnexpectedError, err.Error())
+		return nil, databaseError(err)
 	}
 	return &user, nil
 }
 
 func (r *UserRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UUID) (*model.User, *common.CustomError) {
-	query := `SELECT phone_number, full_name, password_hash FROM users WHERE id = $1;`
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
 
-	user := model.User{
-		ID: userID,
-	}
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1;`
 
-	if err := r.opts.DB.QueryRowContext(ctx, query, user.ID.String()).Scan(&user.PhoneNumber, &user.FullName, &user.PasswordHash); err != nil {
+	var user model.User
+	err := readOnly(ctx, r.opts.Replicas, r.opts.DB, func(db dbConn) error {
+		var err error
+		user, err = r.scanUser(db.QueryRowContext(ctx, query, userID.String()))
+		return err
+	})
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.NewCustomError(common.ErrEntityNotFound, "user does not exist in database")
 		}
-		return nil, common.NewCustomError(common.ErrUnexpectedError, err.Error())
+		return nil, databaseError(err)
 	}
 	return &user, nil
 }
 
+// GetAccessState reads the status and token revocation of a user from the primary, so that a suspension or
+// revocation is seen at once. It reads far less than GetByUserID, which may be served by a replica.
+func (r *UserRepositoryImpl) GetAccessState(ctx context.Context, userID uuid.UUID) (model.UserAccessState, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `SELECT status, tokens_revoked_at FROM users WHERE id = $1;`
+
+	var state model.UserAccessState
+	err := conn(ctx, r.opts.DB).QueryRowContext(ctx, query, userID.String()).Scan(&state.Status, &state.TokensRevokedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.UserAccessState{}, common.NewCustomError(common.ErrEntityNotFound, "user does not exist in database")
+		}
+		return model.UserAccessState{}, databaseError(err)
+	}
+	return state, nil
+}
+
+// Update writes the non-empty fields of user as a patch, user.Version is checked as in Patch.
 func (r *UserRepositoryImpl) Update(ctx context.Context, user model.User) *common.CustomError {
-	query, args := r.constructUpdateQueryAndArgs(user)
+	patch := model.NewUserPatch(user.ID)
+	patch.Version = user.Version
+
+	if user.FullName != "" {
+		patch.Set(model.UserFieldFullName, user.FullName)
+	}
+	if user.PhoneNumber != "" {
+		patch.Set(model.UserFieldPhoneNumber, user.PhoneNumber)
+	}
+	if user.PasswordHash != "" {
+		patch.Set(model.UserFieldPasswordHash, user.PasswordHash)
+	}
 
-	if _, err := r.opts.DB.ExecContext(ctx, query, args...); err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			if pqErr.Code == postgreSQLConflictErrCode {
-				return common.NewCustomError(common.ErrEntityAlreadyExists, "phone number is already used")
+	return r.Patch(ctx, patch)
+}
+
+// Patch writes the fields present in the patch. When patch.Version is set the update only applies while the
+// stored version still matches it, otherwise ErrPreconditionFailed is returned.
+func (r *UserRepositoryImpl) Patch(ctx context.Context, patch model.UserPatch) *common.CustomError {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	if len(patch.Fields) == 0 {
+		return nil
+	}
+
+	query, args, errQuery := r.constructPatchQueryAndArgs(patch)
+	if errQuery != nil {
+		return errQuery
+	}
+
+	payload := model.UserEventPayload{
+		UserID:     patch.UserID,
+		OccurredAt: time.Now(),
+	}
+	if fullName, ok := patch.StringValue(model.UserFieldFullName); ok {
+		payload.FullName = &fullName
+	}
+	if email, ok := patch.StringValue(model.UserFieldEmail); ok {
+		payload.Email = &email
+	}
+
+	var rowsAffected int64
+	err := withTx(ctx, r.opts.DB, func(tx dbConn) error {
+		if phoneNumber, ok := patch.StringValue(model.UserFieldPhoneNumber); ok {
+			if err := checkPlaintextPhoneNumber(ctx, tx, patch.UserID, phoneNumber); err != nil {
+				return err
 			}
 		}
-		return common.NewCustomError(common.ErrUnexpectedError, err.Error())
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected, err = result.RowsAffected(); err != nil || rowsAffected == 0 {
+			return err
+		}
+
+		if err := insertOutboxEvent(ctx, tx, model.OutboxEventUserProfileUpdated, payload); err != nil {
+			return err
+		}
+
+		if _, ok := patch.Fields[model.UserFieldPhoneNumber]; ok {
+			return insertOutboxEvent(ctx, tx, model.OutboxEventUserPhoneNumberChanged, model.UserEventPayload{
+				UserID:     patch.UserID,
+				OccurredAt: payload.OccurredAt,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		if errConflict := userConflictError(err); errConflict != nil {
+			return errConflict
+		}
+		return databaseError(err)
+	}
+	if rowsAffected == 0 {
+		if patch.Version != 0 {
+			return common.NewCustomError(common.ErrPreconditionFailed, "profile has been changed by another request")
+		}
+		return common.NewCustomError(common.ErrEntityNotFound, "user does not exist in database")
 	}
 	return nil
 }
 
-func (r *UserRepositoryImpl) constructUpdateQueryAndArgs(user model.User) (string, []interface{}) {
-	query := `UPDATE users SET %s WHERE id = $1;`
+// UpdateStatus moves the user from one status to another. The update only applies while the stored
+// status still equals from, so two concurrent transitions cannot both succeed. Anonymized users never
+// change status again.
+func (r *UserRepositoryImpl) UpdateStatus(ctx context.Context, userID uuid.UUID, from, to model.UserStatus, changedAt time.Time) *common.CustomError {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
 
-	setQuery := ""
-	args := []interface{}{user.ID.String()}
+	query := `UPDATE users SET status = $3, status_changed_at = $4, updated_at = $4, version = version + 1 WHERE id = $1 AND status = $2 AND anonymized_at IS NULL;`
 
-	if user.FullName != "" {
-		setQuery += fmt.Sprintf("full_name = $%d", len(args)+1)
-		args = append(args, user.FullName)
+	var rowsAffected int64
+	err := withTx(ctx, r.opts.DB, func(tx dbConn) error {
+		result, err := tx.ExecContext(ctx, query, userID.String(), from, to, changedAt)
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected, err = result.RowsAffected(); err != nil || rowsAffected == 0 {
+			return err
+		}
+
+		return insertOutboxEvent(ctx, tx, model.OutboxEventUserStatusChanged, model.UserEventPayload{
+			UserID:     userID,
+			Status:     &to,
+			OccurredAt: changedAt,
+		})
+	})
+	if err != nil {
+		return databaseError(err)
 	}
+	if rowsAffected == 0 {
+		return common.NewCustomError(common.ErrInvalidStatusTransition, "user status has been changed by another request")
+	}
+	return nil
+}
 
-	if user.PhoneNumber != "" {
-		if len(setQuery) > 0 {
-			setQuery += ", "
+func (r *UserRepositoryImpl) RevokeTokens(ctx context.Context, userID uuid.UUID, revokedAt time.Time) *common.CustomError {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `UPDATE users SET tokens_revoked_at = $2, updated_at = $2, version = version + 1 WHERE id = $1;`
+
+	if _, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, userID.String(), revokedAt); err != nil {
+		return databaseError(err)
+	}
+	return nil
+}
+
+// GetDeletedBefore returns up to limit users that were deleted before the given time and still hold
+// personal data, oldest deletion first.
+func (r *UserRepositoryImpl) GetDeletedBefore(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `SELECT id FROM users WHERE status = $1 AND anonymized_at IS NULL AND status_changed_at < $2 ORDER BY status_changed_at LIMIT $3;`
+
+	rows, err := conn(ctx, r.opts.DB).QueryContext(ctx, query, model.UserStatusDeleted, before, limit)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	defer rows.Close()
+
+	userIDs := []uuid.UUID{}
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, databaseError(err)
 		}
-		setQuery += fmt.Sprintf("phone_number = $%d", len(args)+1)
-		args = append(args, user.PhoneNumber)
+		userIDs = append(userIDs, userID)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, databaseError(err)
+	}
+	return userIDs, nil
+}
 
-	if user.PasswordHash != "" {
-		if len(setQuery) > 0 {
-			setQuery += ", "
+// Anonymize scrubs the personal data of a deleted user from its row and from the events about it, and
+// releases the phone number for reuse. The row itself is kept so that references to the user id stay valid.
+func (r *UserRepositoryImpl) Anonymize(ctx context.Context, userID uuid.UUID, anonymizedAt time.Time) *common.CustomError {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `UPDATE users SET full_name = $3, phone_number = NULL, phone_number_ciphertext = NULL, phone_number_key_id = NULL,
+		phone_number_hash = NULL, password_hash = '', email = NULL, email_verified_at = NULL,
+		date_of_birth = NULL, locale = NULL, timezone = NULL, avatar_key = NULL, anonymized_at = $4, updated_at = $4, version = version + 1 WHERE id = $1 AND status = $2 AND anonymized_at IS NULL;`
+
+	err := withTx(ctx, r.opts.DB, func(tx dbConn) error {
+		result, err := tx.ExecContext(ctx, query, userID.String(), model.UserStatusDeleted, anonymizedFullName, anonymizedAt)
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected, err := result.RowsAffected(); err != nil || rowsAffected == 0 {
+			return err
+		}
+
+		if err := scrubUserEvents(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		return insertOutboxEvent(ctx, tx, model.OutboxEventUserAnonymized, model.UserEventPayload{
+			UserID:     userID,
+			OccurredAt: anonymizedAt,
+		})
+	})
+	if err != nil {
+		return databaseError(err)
+	}
+	return nil
+}
+
+// ReencryptPhoneNumbers encrypts up to limit phone numbers stored in plaintext, under a key other than the
+// active one or not yet normalized with the active key, taking users in ID order from after on. Numbers are
+// normalized to E.164 on the way, the users keep their version. A number another user has registered since is
+// left as it is and reported in the conflicts, so that it does not hold up the rest of the batch. A number
+// that cannot be parsed is encrypted as stored and flagged as unparseable, so that it is not picked up again
+// until its key is rotated or the user changes it; it is reported as invalid the first time only.
+func (r *UserRepositoryImpl) ReencryptPhoneNumbers(ctx context.Context, after uuid.UUID, limit int) (model.PhoneNumberReencryption, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	selectQuery := `SELECT id, phone_number, phone_number_ciphertext, phone_number_unparseable FROM users
+		WHERE (phone_number IS NOT NULL OR phone_number_key_id <> $1
+			OR (phone_number_ciphertext IS NOT NULL AND NOT phone_number_normalized AND NOT phone_number_unparseable))
+		AND id > $2 ORDER BY id LIMIT $3 FOR UPDATE SKIP LOCKED;`
+	updateQuery := `UPDATE users SET phone_number = NULL, phone_number_ciphertext = $2, phone_number_key_id = $3, phone_number_hash = $4,
+		phone_number_normalized = $5, phone_number_unparseable = NOT $5 WHERE id = $1;`
+
+	var result model.PhoneNumberReencryption
+	var errEncrypt *common.CustomError
+	err := withTx(ctx, r.opts.DB, func(tx dbConn) error {
+		rows, err := tx.QueryContext(ctx, selectQuery, r.opts.PhoneNumberKeyring.ActiveKeyID(), after.String(), limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		type storedPhoneNumber struct {
+			userID      uuid.UUID
+			phoneNumber string
+			unparseable bool
+		}
+		var users []storedPhoneNumber
+		for rows.Next() {
+			var user storedPhoneNumber
+			var plaintextPhoneNumber sql.NullString
+			var phoneNumberCiphertext []byte
+			if err := rows.Scan(&user.userID, &plaintextPhoneNumber, &phoneNumberCiphertext, &user.unparseable); err != nil {
+				return err
+			}
+
+			if user.phoneNumber, err = r.decryptPhoneNumber(user.userID, plaintextPhoneNumber, phoneNumberCiphertext); err != nil {
+				errEncrypt = common.WrapError(common.ErrUnexpectedError, err, "error decrypting phone number")
+				return errEncrypt
+			}
+			users = append(users, user)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		result = model.PhoneNumberReencryption{Size: len(users)}
+		for _, user := range users {
+			result.LastUserID = user.userID
+
+			phoneNumber, normalized := user.phoneNumber, false
+			if parsed, err := phonenumber.Parse(user.phoneNumber, phonenumber.DefaultRegion); err == nil {
+				phoneNumber, normalized = parsed.E164(), true
+			} else if !user.unparseable {
+				result.Invalid = append(result.Invalid, user.userID)
+			}
+
+			encrypted, errPhone := r.encryptPhoneNumber(user.userID, phoneNumber)
+			if errPhone != nil {
+				errEncrypt = errPhone
+				return errEncrypt
+			}
+
+			// A savepoint per user keeps a conflicting number from rolling back the whole batch.
+			if _, err := tx.ExecContext(ctx, `SAVEPOINT reencrypt_phone_number;`); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, updateQuery, user.userID.String(), encrypted.ciphertext, encrypted.keyID, encrypted.hash, normalized)
+			if IsUniqueViolation(err) {
+				if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT reencrypt_phone_number;`); err != nil {
+					return err
+				}
+				result.Conflicts = append(result.Conflicts, user.userID)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT reencrypt_phone_number;`); err != nil {
+				return err
+			}
+			result.Reencrypted++
+			if phoneNumber != user.phoneNumber {
+				result.Normalized = append(result.Normalized, user.userID)
+			}
 		}
-		setQuery += fmt.Sprintf("password_hash = $%d", len(args)+1)
-		args = append(args, user.PasswordHash)
+		return nil
+	})
+	if errEncrypt != nil {
+		return model.PhoneNumberReencryption{}, errEncrypt
+	}
+	if err != nil {
+		return model.PhoneNumberReencryption{}, databaseError(err)
 	}
+	return result, nil
+}
+
+// constructPatchQueryAndArgs builds the UPDATE for the patched fields. Only fields listed in
+// patchableUserColumns can be written, so field names never reach the query unchecked.
+func (r *UserRepositoryImpl) constructPatchQueryAndArgs(patch model.UserPatch) (string, []interface{}, *common.CustomError) {
+	query := `UPDATE users SET %s WHERE id = $1%s;`
+
+	setQuery := "version = version + 1, updated_at = now()"
+	args := []interface{}{patch.UserID.String()}
+
+	for _, field := range patch.SortedFields() {
+		column, ok := patchableUserColumns[field]
+		if !ok {
+			return "", nil, common.NewCustomError(common.ErrUnexpectedError, fmt.Sprintf("user field %s cannot be patched", field))
+		}
+
+		if field == model.UserFieldPhoneNumber {
+			value, _ := patch.StringValue(field)
+			phoneNumber, errEncrypt := r.encryptPhoneNumber(patch.UserID, value)
+			if errEncrypt != nil {
+				return "", nil, errEncrypt
+			}
 
-	return fmt.Sprintf(query, setQuery), args
+			setQuery += fmt.Sprintf(", phone_number = NULL, phone_number_ciphertext = $%d, phone_number_key_id = $%d, phone_number_hash = $%d, phone_number_normalized = TRUE, phone_number_unparseable = FALSE",
+				len(args)+1, len(args)+2, len(args)+3)
+			args = append(args, phoneNumber.ciphertext, phoneNumber.keyID, phoneNumber.hash)
+			continue
+		}
+
+		setQuery += fmt.Sprintf(", %s = $%d", column, len(args)+1)
+		args = append(args, patch.Fields[field])
+
+		// A changed address has not been verified yet. Writing the same address again keeps the verification.
+		if field == model.UserFieldEmail {
+			setQuery += fmt.Sprintf(", email_verified_at = CASE WHEN lower(email) = lower($%d) THEN email_verified_at END", len(args))
+		}
+	}
+
+	whereQuery := ""
+	if patch.Version != 0 {
+		whereQuery = fmt.Sprintf(" AND version = $%d", len(args)+1)
+		args = append(args, patch.Version)
+	}
+
+	return fmt.Sprintf(query, setQuery, whereQuery), args, nil
+}
+
+func (r *UserRepositoryImpl) scanUser(row rowScanner) (model.User, error) {
+	var user model.User
+	var plaintextPhoneNumber sql.NullString
+	var phoneNumberCiphertext []byte
+
+	err := row.Scan(&user.ID, &plaintextPhoneNumber, &phoneNumberCiphertext, &user.FullName, &user.PasswordHash, &user.Status, &user.StatusChangedAt, &user.TokensRevokedAt, &user.AnonymizedAt,
+		&user.Email, &user.EmailVerifiedAt, &user.DateOfBirth, &user.Locale, &user.Timezone, &user.AvatarKey, &user.LastLoginAt,
+		&user.Version, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return user, err
+	}
+
+	user.PhoneNumber, err = r.decryptPhoneNumber(user.ID, plaintextPhoneNumber, phoneNumberCiphertext)
+	return user, err
+}
+
+// decryptPhoneNumber returns the phone number of a user from the plaintext or the encrypted column,
+// depending on whether it has been encrypted yet. Anonymized users have neither.
+func (r *UserRepositoryImpl) decryptPhoneNumber(userID uuid.UUID, plaintext sql.NullString, ciphertext []byte) (string, error) {
+	if ciphertext == nil {
+		return plaintext.String, nil
+	}
+
+	phoneNumber, err := r.opts.PhoneNumberKeyring.Decrypt(ciphertext, userID[:])
+	if err != nil {
+		return "", fmt.Errorf("error decrypting phone number of user %s: %w", userID, err)
+	}
+	return string(phoneNumber), nil
+}
+
+// encryptedPhoneNumber holds the values stored in the phone_number_* columns.
+type encryptedPhoneNumber struct {
+	ciphertext []byte
+	keyID      string
+	hash       []byte
+}
+
+func (r *UserRepositoryImpl) encryptPhoneNumber(userID uuid.UUID, phoneNumber string) (encryptedPhoneNumber, *common.CustomError) {
+	ciphertext, err := r.opts.PhoneNumberKeyring.Encrypt([]byte(phoneNumber), userID[:])
+	if err != nil {
+		return encryptedPhoneNumber{}, common.WrapError(common.ErrUnexpectedError, err, "error encrypting phone number")
+	}
+	return encryptedPhoneNumber{
+		ciphertext: ciphertext,
+		keyID:      r.opts.PhoneNumberKeyring.ActiveKeyID(),
+		hash:       r.opts.PhoneNumberIndex.Compute(phoneNumber),
+	}, nil
+}
+
+// errPhoneNumberTaken reports a phone number another user still has in plaintext.
+var errPhoneNumberTaken = errors.New("phone number is used by a user whose number has not been encrypted yet")
+
+// checkPlaintextPhoneNumber fails with errPhoneNumberTaken when a user other than userID has phoneNumber in
+// the plaintext column, which the unique index on phone_number_hash does not cover until the re-encryption job
+// has moved it.
+func checkPlaintextPhoneNumber(ctx context.Context, tx dbConn, userID uuid.UUID, phoneNumber string) error {
+	query := `SELECT EXISTS (SELECT 1 FROM users WHERE phone_number = $1 AND id <> $2);`
+
+	var taken bool
+	if err := tx.QueryRowContext(ctx, query, phoneNumber, userID.String()).Scan(&taken); err != nil {
+		return err
+	}
+	if taken {
+		return errPhoneNumberTaken
+	}
+	return nil
+}
+
+// userConflictError translates a unique violation into the field that is already taken.
+func userConflictError(err error) *common.CustomError {
+	if errors.Is(err, errPhoneNumberTaken) {
+		return common.WrapError(common.ErrEntityAlreadyExists, err, "phone number is already used")
+	}
+
+	var pqErr *pq.Error
+	if !IsUniqueViolation(err) || !errors.As(err, &pqErr) {
+		return nil
+	}
+
+	if pqErr.Constraint == userEmailUniqueIndex {
+		return common.WrapError(common.ErrEntityAlreadyExists, err, "email is already used")
+	}
+	return common.WrapError(common.ErrEntityAlreadyExists, err, "phone number is already used")
 }
@@ -0,0 +1,185 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/encryption"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserRepositoryTestSuite struct {
+	suite.Suite
+	db      *fakeDatabase
+	keyring *encryption.Keyring
+	index   *encryption.BlindIndex
+	sut     *repository.UserRepositoryImpl
+}
+
+func (s *UserRepositoryTestSuite) SetupTest() {
+	var err error
+	s.keyring, err = encryption.NewKeyring([]encryption.Key{{ID: "2024-06", Secret: bytes.Repeat([]byte{1}, encryption.KeySize)}})
+	s.Require().NoError(err)
+	s.index, err = encryption.NewBlindIndex(bytes.Repeat([]byte{2}, encryption.MinBlindIndexKeySize))
+	s.Require().NoError(err)
+
+	s.db = &fakeDatabase{}
+	s.sut = repository.NewUserRepository(repository.UserRepositoryImplOptions{
+		DB:                 sql.OpenDB(s.db),
+		PhoneNumberKeyring: s.keyring,
+		PhoneNumberIndex:   s.index,
+	})
+}
+
+func TestUserRepository(t *testing.T) {
+	suite.Run(t, new(UserRepositoryTestSuite))
+}
+
+func (s *UserRepositoryTestSuite) TestAnonymizeShouldScrubUserRowAndEvents() {
+	s.Require().Nil(s.sut.Anonymize(context.Background(), uuid.New(), time.Now()))
+
+	var scrubbed []string
+	for _, query := range s.db.queries() {
+		fields := strings.Fields(query)
+		if fields[0] == "UPDATE" {
+			scrubbed = append(scrubbed, fields[1])
+		}
+	}
+	s.Equal([]string{"users", "outbox_events", "webhook_deliveries"}, scrubbed)
+
+	anonymize := strings.Join(strings.Fields(s.db.queries()[0]), " ")
+	for _, column := range []string{"phone_number", "phone_number_ciphertext", "phone_number_hash", "email", "date_of_birth", "avatar_key"} {
+		s.Contains(anonymize, " "+column+" = NULL", column)
+	}
+}
+
+// Every event field holding personal data has to be scrubbed by Anonymize, which removes the fields listed
+// in PersonalUserEventFields.
+func (s *UserRepositoryTestSuite) TestPersonalUserEventFieldsShouldCoverEveryPersonalField() {
+	fullName := "Jasuke"
+	email := "jasuke@example.com"
+	status := model.UserStatusActive
+	data, err := json.Marshal(model.UserEventPayload{UserID: uuid.New(), FullName: &fullName, Email: &email, Status: &status})
+	s.Require().NoError(err)
+
+	var payload map[string]interface{}
+	s.Require().NoError(json.Unmarshal(data, &payload))
+
+	nonPersonal := map[string]bool{"user_id": true, "status": true, "occurred_at": true}
+	for field := range payload {
+		if !nonPersonal[field] {
+			s.Contains(model.PersonalUserEventFields, field)
+		}
+	}
+}
+
+func (s *UserRepositoryTestSuite) TestReencryptPhoneNumbersShouldNormalizeLegacyPhoneNumbers() {
+	plaintextUserID := uuid.New()
+	encryptedUserID := uuid.New()
+	ciphertext, err := s.keyring.Encrypt([]byte("0812-3456-7890"), encryptedUserID[:])
+	s.Require().NoError(err)
+	s.db.results = map[string][][]driver.Value{
+		"SELECT id, phone_number, phone_number_ciphertext, phone_number_unparseable FROM users": {
+			{plaintextUserID.String(), "+620812345678", nil, false},
+			{encryptedUserID.String(), nil, ciphertext, false},
+		},
+	}
+
+	result, errReencrypt := s.sut.ReencryptPhoneNumbers(context.Background(), uuid.Nil, 10)
+
+	s.Require().Nil(errReencrypt)
+	s.Equal(2, result.Reencrypted)
+	s.Equal([]uuid.UUID{plaintextUserID, encryptedUserID}, result.Normalized)
+	s.Empty(result.Conflicts)
+	s.Empty(result.Invalid)
+
+	updates := s.db.argumentsOf("UPDATE users")
+	s.Require().Len(updates, 2)
+	for i, expected := range []struct {
+		userID      uuid.UUID
+		phoneNumber string
+	}{
+		{plaintextUserID, "+62812345678"},
+		{encryptedUserID, "+6281234567890"},
+	} {
+		phoneNumber, err := s.keyring.Decrypt(updates[i][1].([]byte), expected.userID[:])
+		s.Require().NoError(err)
+		s.Equal(expected.phoneNumber, string(phoneNumber))
+		s.Equal(s.index.Compute(expected.phoneNumber), updates[i][3])
+		s.Equal(true, updates[i][4])
+	}
+}
+
+func (s *UserRepositoryTestSuite) TestReencryptPhoneNumbersGivenNormalizedDuplicateShouldReportConflict() {
+	userID := uuid.New()
+	duplicateUserID := uuid.New()
+	s.db.results = map[string][][]driver.Value{
+		"SELECT id, phone_number, phone_number_ciphertext, phone_number_unparseable FROM users": {
+			{userID.String(), "+62812345678", nil, false},
+			{duplicateUserID.String(), "+620812345678", nil, false},
+		},
+	}
+	s.db.execError = func(query string, args []driver.Value) error {
+		if strings.HasPrefix(query, "UPDATE users") && args[0] == duplicateUserID.String() {
+			return &pq.Error{Code: "23505"}
+		}
+		return nil
+	}
+
+	result, err := s.sut.ReencryptPhoneNumbers(context.Background(), uuid.Nil, 10)
+
+	s.Require().Nil(err)
+	s.Equal(1, result.Reencrypted)
+	s.Equal([]uuid.UUID{duplicateUserID}, result.Conflicts)
+	s.Empty(result.Normalized)
+	s.Contains(s.db.queries(), "ROLLBACK TO SAVEPOINT reencrypt_phone_number;")
+}
+
+func (s *UserRepositoryTestSuite) TestReencryptPhoneNumbersGivenUnparseablePhoneNumberShouldReportIt() {
+	userID := uuid.New()
+	s.db.results = map[string][][]driver.Value{
+		"SELECT id, phone_number, phone_number_ciphertext, phone_number_unparseable FROM users": {{userID.String(), "12345", nil, false}},
+	}
+
+	result, err := s.sut.ReencryptPhoneNumbers(context.Background(), uuid.Nil, 10)
+
+	s.Require().Nil(err)
+	s.Equal(1, result.Reencrypted)
+	s.Equal([]uuid.UUID{userID}, result.Invalid)
+
+	updates := s.db.argumentsOf("UPDATE users")
+	s.Require().Len(updates, 1)
+	s.Equal(s.index.Compute("12345"), updates[0][3])
+	s.Equal(false, updates[0][4])
+}
+
+// A number flagged as unparseable is only selected again to move it to the active key, and is not reported twice.
+func (s *UserRepositoryTestSuite) TestReencryptPhoneNumbersGivenFlaggedPhoneNumberShouldNotReportItAgain() {
+	userID := uuid.New()
+	ciphertext, err := s.keyring.Encrypt([]byte("12345"), userID[:])
+	s.Require().NoError(err)
+	s.db.results = map[string][][]driver.Value{
+		"SELECT id, phone_number, phone_number_ciphertext, phone_number_unparseable FROM users": {{userID.String(), nil, ciphertext, true}},
+	}
+
+	result, errReencrypt := s.sut.ReencryptPhoneNumbers(context.Background(), uuid.Nil, 10)
+
+	s.Require().Nil(errReencrypt)
+	s.Equal(1, result.Reencrypted)
+	s.Empty(result.Invalid)
+	s.Contains(strings.Join(strings.Fields(s.db.queries()[0]), " "), "NOT phone_number_normalized AND NOT phone_number_unparseable")
+
+	updates := s.db.argumentsOf("UPDATE users")
+	s.Require().Len(updates, 1)
+	s.Equal(false, updates[0][4])
+}
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type WebhookRepositoryImplOptions struct {
+	DB           *sql.DB
+	QueryTimeout time.Duration
+}
+
+type WebhookRepositoryImpl struct {
+	opts *WebhookRepositoryImplOptions
+}
+
+func NewWebhookRepositoryImpl(opts WebhookRepositoryImplOptions) *WebhookRepositoryImpl {
+	return &WebhookRepositoryImpl{
+		opts: &opts,
+	}
+}
+
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at`
+
+func (r *WebhookRepositoryImpl) SaveSubscription(ctx context.Context, subscription model.WebhookSubscription) (uuid.UUID, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `INSERT INTO webhook_subscriptions (id, url, secret, event_types, active, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $6);`
+
+	subscription.ID = uuid.New()
+
+	if _, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, subscription.ID.String(), subscription.URL, subscription.Secret,
+		pq.Array(eventTypesToStrings(subscription.EventTypes)), subscription.Active, subscription.CreatedAt); err != nil {
+		return uuid.Nil, databaseError(err)
+	}
+	return subscription.ID, nil
+}
+
+func (r *WebhookRepositoryImpl) GetSubscription(ctx context.Context, subscriptionID uuid.UUID) (*model.WebhookSubscription, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `SELECT id, url, secret, event_types, active, created_at, updated_at FROM webhook_subscriptions WHERE id = $1;`
+
+	subscription, err := scanWebhookSubscription(conn(ctx, r.opts.DB).QueryRowContext(ctx, query, subscriptionID.String()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.NewCustomError(common.ErrEntityNotFound, "webhook does not exist in database")
+		}
+		return nil, databaseError(err)
+	}
+	return &subscription, nil
+}
+
+func (r *WebhookRepositoryImpl) GetSubscriptions(ctx context.Context) ([]model.WebhookSubscription, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `SELECT id, url, secret, event_types, active, created_at, updated_at FROM webhook_subscriptions ORDER BY created_at;`
+
+	rows, err := conn(ctx, r.opts.DB).QueryContext(ctx, query)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	defer rows.Close()
+
+	subscriptions := []model.WebhookSubscription{}
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, databaseError(err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, databaseError(err)
+	}
+	return subscriptions, nil
+}
+
+func (r *WebhookRepositoryImpl) UpdateSubscription(ctx context.Context, subscription model.WebhookSubscription) *common.CustomError {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `UPDATE webhook_subscriptions SET url = $2, secret = $3, event_types = $4, active = $5, updated_at = $6 WHERE id = $1;`
+
+	result, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, subscription.ID.String(), subscription.URL, subscription.Secret,
+		pq.Array(eventTypesToStrings(subscription.EventTypes)), subscription.Active, subscription.UpdatedAt)
+	if err != nil {
+		return databaseError(err)
+	}
+	return checkWebhookRowsAffected(result, "webhook does not exist in database")
+}
+
+// DeleteSubscription removes the subscription together with its delivery log.
+func (r *WebhookRepositoryImpl) DeleteSubscription(ctx context.Context, subscriptionID uuid.UUID) *common.CustomError {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `DELETE FROM webhook_subscriptions WHERE id = $1;`
+
+	result, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, subscriptionID.String())
+	if err != nil {
+		return databaseError(err)
+	}
+	return checkWebhookRowsAffected(result, "webhook does not exist in database")
+}
+
+// EnqueueDeliveries queues the event for every active subscription interested in its type. Enqueueing
+// the same event twice is a no-op, so the outbox relay may safely retry it.
+func (r *WebhookRepositoryImpl) EnqueueDeliveries(ctx context.Context, eventID uuid.UUID, eventType model.OutboxEventType, payload json.RawMessage) *common.CustomError {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `INSERT INTO webhook_deliveries (id, subscription_id, event_id, event_type, payload)
+		SELECT gen_random_uuid(), id, $1, $2, $3 FROM webhook_subscriptions
+		WHERE active AND (cardinality(event_types) = 0 OR $2 = ANY(event_types))
+		ON CONFLICT (subscription_id, event_id) DO NOTHING;`
+
+	if _, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, eventID.String(), eventType, []byte(payload)); err != nil {
+		return databaseError(err)
+	}
+	return nil
+}
+
+// ClaimPendingDeliveries leases up to limit due deliveries. Deliveries of inactive subscriptions stay
+// queued until the subscription is activated again.
+func (r *WebhookRepositoryImpl) ClaimPendingDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `UPDATE webhook_deliveries SET next_attempt_at = now() + $3 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT d.id FROM webhook_deliveries d JOIN webhook_subscriptions s ON s.id = d.subscription_id
+			WHERE d.status = $1 AND d.next_attempt_at <= now() AND s.active
+			ORDER BY d.next_attempt_at
+			LIMIT $2
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns + `;`
+
+	return r.queryDeliveries(ctx, query, model.WebhookDeliveryPending, limit, lease.Milliseconds())
+}
+
+func (r *WebhookRepositoryImpl) MarkDelivered(ctx context.Context, deliveryID uuid.UUID, statusCode int, deliveredAt time.Time) *common.CustomError {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `UPDATE webhook_deliveries SET status = $2, attempts = attempts + 1, last_status_code = $3, last_error = NULL, delivered_at = $4 WHERE id = $1;`
+
+	if _, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, deliveryID.String(), model.WebhookDeliveryDelivered, statusCode, deliveredAt); err != nil {
+		return databaseError(err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed attempt and schedules the next one.
+func (r *WebhookRepositoryImpl) MarkFailed(ctx context.Context, deliveryID uuid.UUID, result model.WebhookDeliveryResult, nextAttemptAt time.Time) *common.CustomError {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `UPDATE webhook_deliveries SET attempts = attempts + 1, last_status_code = $2, last_error = $3, next_attempt_at = $4 WHERE id = $1;`
+
+	if _, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, deliveryID.String(), result.StatusCode, result.Error, nextAttemptAt); err != nil {
+		return databaseError(err)
+	}
+	return nil
+}
+
+// MarkDeadLettered records the last failed attempt and stops retrying the delivery.
+func (r *WebhookRepositoryImpl) MarkDeadLettered(ctx context.Context, deliveryID uuid.UUID, result model.WebhookDeliveryResult) *common.CustomError {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `UPDATE webhook_deliveries SET status = $2, attempts = attempts + 1, last_status_code = $3, last_error = $4 WHERE id = $1;`
+
+	if _, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, deliveryID.String(), model.WebhookDeliveryDeadLettered, result.StatusCode, result.Error); err != nil {
+		return databaseError(err)
+	}
+	return nil
+}
+
+func (r *WebhookRepositoryImpl) GetDelivery(ctx context.Context, subscriptionID, deliveryID uuid.UUID) (*model.WebhookDelivery, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE subscription_id = $1 AND id = $2;`
+
+	deliveries, err := r.queryDeliveries(ctx, query, subscriptionID.String(), deliveryID.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, common.NewCustomError(common.ErrEntityNotFound, "webhook delivery does not exist in database")
+	}
+	return &deliveries[0], nil
+}
+
+// GetDeliveries returns the deliveries matching the filter, newest first.
+func (r *WebhookRepositoryImpl) GetDeliveries(ctx context.Context, filter model.WebhookDeliveryFilter) ([]model.WebhookDelivery, *common.CustomError) {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE subscription_id = $1`
+	args := []interface{}{filter.SubscriptionID.String()}
+
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		query += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+
+	if filter.Before != nil {
+		args = append(args, *filter.Before)
+		query += fmt.Sprintf(" AND created_at < $%d", len(args))
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d;", len(args))
+
+	return r.queryDeliveries(ctx, query, args...)
+}
+
+// Redeliver queues the delivery again with a fresh retry budget.
+func (r *WebhookRepositoryImpl) Redeliver(ctx context.Context, deliveryID uuid.UUID, nextAttemptAt time.Time) *common.CustomError {
+	ctx, cancel := withQueryTimeout(ctx, r.opts.QueryTimeout)
+	defer cancel()
+
+	query := `UPDATE webhook_deliveries SET status = $2, attempts = 0, next_attempt_at = $3, delivered_at = NULL WHERE id = $1;`
+
+	result, err := conn(ctx, r.opts.DB).ExecContext(ctx, query, deliveryID.String(), model.WebhookDeliveryPending, nextAttemptAt)
+	if err != nil {
+		return databaseError(err)
+	}
+	return checkWebhookRowsAffected(result, "webhook delivery does not exist in database")
+}
+
+func (r *WebhookRepositoryImpl) queryDeliveries(ctx context.Context, query string, args ...interface{}) ([]model.WebhookDelivery, *common.CustomError) {
+	rows, err := conn(ctx, r.opts.DB).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	defer rows.Close()
+
+	deliveries := []model.WebhookDelivery{}
+	for rows.Next() {
+		var delivery model.WebhookDelivery
+		var payload []byte
+
+		if err := rows.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &payload, &delivery.Status, &delivery.Attempts,
+			&delivery.NextAttemptAt, &delivery.LastStatusCode, &delivery.LastError, &delivery.CreatedAt, &delivery.DeliveredAt); err != nil {
+			return nil, databaseError(err)
+		}
+
+		delivery.Payload = payload
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, databaseError(err)
+	}
+	return deliveries, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWebhookSubscription(row rowScanner) (model.WebhookSubscription, error) {
+	var subscription model.WebhookSubscription
+	var eventTypes []string
+
+	if err := row.Scan(&subscription.ID, &subscription.URL, &subscription.Secret, pq.Array(&eventTypes), &subscription.Active,
+		&subscription.CreatedAt, &subscription.UpdatedAt); err != nil {
+		return model.WebhookSubscription{}, err
+	}
+
+	subscription.EventTypes = make([]model.OutboxEventType, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		subscription.EventTypes = append(subscription.EventTypes, model.OutboxEventType(eventType))
+	}
+	return subscription, nil
+}
+
+func eventTypesToStrings(eventTypes []model.OutboxEventType) []string {
+	result := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		result = append(result, string(eventType))
+	}
+	return result
+}
+
+func checkWebhookRowsAffected(result sql.Result, notFoundMessage string) *common.CustomError {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return databaseError(err)
+	}
+	if rowsAffected == 0 {
+		return common.NewCustomError(common.ErrEntityNotFound, notFoundMessage)
+	}
+	return nil
+}
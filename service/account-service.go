@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/SawitProRecruitment/UserService/storage"
+	"github.com/google/uuid"
+)
+
+type AccountServiceImpl struct {
+	userRepository       repository.UserRepository
+	loginLogRepository   repository.LoginLogRepository
+	auditEventRepository repository.AuditEventRepository
+	transactionManager   repository.TransactionManager
+	tokenManager         TokenManager
+	blobStore            storage.BlobStore
+	deletionGracePeriod  time.Duration
+}
+
+func NewAccountServiceImpl(userRepository repository.UserRepository, loginLogRepository repository.LoginLogRepository, auditEventRepository repository.AuditEventRepository, transactionManager repository.TransactionManager, tokenManager TokenManager, blobStore storage.BlobStore, deletionGracePeriod time.Duration) *AccountServiceImpl {
+	return &AccountServiceImpl{
+		userRepository:       userRepository,
+		loginLogRepository:   loginLogRepository,
+		auditEventRepository: auditEventRepository,
+		transactionManager:   transactionManager,
+		tokenManager:         tokenManager,
+		blobStore:            blobStore,
+		deletionGracePeriod:  deletionGracePeriod,
+	}
+}
+
+func (s *AccountServiceImpl) ChangeStatus(ctx context.Context, userID uuid.UUID, params generated.UpdateUserStatusRequest) *common.CustomError {
+	status := model.UserStatus(params.Status)
+	if !status.IsValid() {
+		return common.NewCustomError(common.ErrInvalidInput, "invalid request params", fmt.Sprintf("status %q is not supported", params.Status))
+	}
+
+	// The transition is checked against the current status, which a replica may not have yet.
+	user, err := s.userRepository.GetByUserID(common.WithPrimaryReads(ctx), userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Status == status {
+		return nil
+	}
+
+	if user.AnonymizedAt != nil || !user.Status.CanTransitionTo(status) {
+		return common.NewCustomError(common.ErrInvalidStatusTransition, fmt.Sprintf("user status cannot change from %s to %s", user.Status, status))
+	}
+
+	now := time.Now()
+	event := newAuditEvent(ctx, model.AuditActorAdmin, nil, userID, model.AuditActionStatusChanged, map[string]model.AuditChange{
+		"status": fieldChange(string(user.Status), string(status)),
+	})
+
+	return s.transactionManager.WithinTransaction(ctx, func(ctx context.Context) *common.CustomError {
+		if err := s.userRepository.UpdateStatus(ctx, userID, user.Status, status, now); err != nil {
+			return err
+		}
+
+		if status == model.UserStatusDeleted {
+			if err := s.userRepository.RevokeTokens(ctx, userID, now); err != nil {
+				return err
+			}
+		}
+
+		if _, err := s.auditEventRepository.Append(ctx, event); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// DeleteAccount marks the authenticated user as deleted and revokes every access token issued so far.
+// The personal data is kept until the grace period ends so that the user can still restore the account.
+func (s *AccountServiceImpl) DeleteAccount(ctx context.Context, params generated.DeleteAccountRequest) (generated.DeleteAccountResponse, *common.CustomError) {
+	user, err := authenticateUser(ctx, s.tokenManager, s.userRepository)
+	if err != nil {
+		return generated.DeleteAccountResponse{}, err
+	}
+
+	now := time.Now()
+	err = s.transactionManager.WithinTransaction(ctx, func(ctx context.Context) *common.CustomError {
+		// Reading the user inside the transaction gets its password hash, which the user cache does not keep.
+		current, err := s.userRepository.GetByUserID(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := comparePassword(ctx, current.PasswordHash, params.Password); err != nil {
+			return common.NewCustomError(common.ErrInvalidInput, "password is incorrect")
+		}
+
+		if err := s.userRepository.UpdateStatus(ctx, current.ID, current.Status, model.UserStatusDeleted, now); err != nil {
+			return err
+		}
+
+		if err := s.userRepository.RevokeTokens(ctx, current.ID, now); err != nil {
+			return err
+		}
+
+		event := newAuditEvent(ctx, model.AuditActorUser, &current.ID, current.ID, model.AuditActionAccountDeleted, map[string]model.AuditChange{
+			"status": fieldChange(string(current.Status), string(model.UserStatusDeleted)),
+		})
+		if _, err := s.auditEventRepository.Append(ctx, event); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return generated.DeleteAccountResponse{}, err
+	}
+
+	return generated.DeleteAccountResponse{RestorableUntil: now.Add(s.deletionGracePeriod)}, nil
+}
+
+// RestoreAccount reactivates a deleted account whose grace period has not ended yet.
+// Access tokens revoked by the deletion stay revoked, the user has to log in again.
+func (s *AccountServiceImpl) RestoreAccount(ctx context.Context, params generated.RestoreAccountRequest) *common.CustomError {
+	phoneNumber, _ := phoneNumberLookupKey(params.PhoneNumber)
+	user, err := s.userRepository.GetByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		if err.ErrType == common.ErrEntityNotFound {
+			return common.NewCustomError(common.ErrInvalidInput, "phone number or password is incorrect")
+		}
+		return err
+	}
+
+	if err := comparePassword(ctx, user.PasswordHash, params.Password); err != nil {
+		return common.NewCustomError(common.ErrInvalidInput, "phone number or password is incorrect")
+	}
+
+	if user.Status != model.UserStatusDeleted {
+		return common.NewCustomError(common.ErrInvalidStatusTransition, "account is not deleted")
+	}
+
+	if time.Since(user.StatusChangedAt) > s.deletionGracePeriod {
+		return common.NewCustomError(common.ErrAccountDeleted, "account can no longer be restored")
+	}
+
+	event := newAuditEvent(ctx, model.AuditActorUser, &user.ID, user.ID, model.AuditActionAccountRestored, map[string]model.AuditChange{
+		"status": fieldChange(string(model.UserStatusDeleted), string(model.UserStatusActive)),
+	})
+
+	return s.transactionManager.WithinTransaction(ctx, func(ctx context.Context) *common.CustomError {
+		if err := s.userRepository.UpdateStatus(ctx, user.ID, model.UserStatusDeleted, model.UserStatusActive, time.Now()); err != nil {
+			return err
+		}
+
+		if _, err := s.auditEventRepository.Append(ctx, event); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// AnonymizeDeletedAccounts scrubs up to limit accounts whose deletion grace period has ended and removes
+// their login history. It returns the number of anonymized accounts.
+func (s *AccountServiceImpl) AnonymizeDeletedAccounts(ctx context.Context, limit int) (int, *common.CustomError) {
+	now := time.Now()
+
+	userIDs, err := s.userRepository.GetDeletedBefore(ctx, now.Add(-s.deletionGracePeriod), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, userID := range userIDs {
+		event := newAuditEvent(ctx, model.AuditActorSystem, nil, userID, model.AuditActionAccountAnonymized, nil)
+
+		var avatarKey *string
+		err := s.transactionManager.WithinTransaction(ctx, func(ctx context.Context) *common.CustomError {
+			user, err := s.userRepository.GetByUserID(ctx, userID)
+			if err != nil {
+				return err
+			}
+			avatarKey = user.AvatarKey
+
+			if err := s.loginLogRepository.DeleteByUserID(ctx, userID); err != nil {
+				return err
+			}
+
+			if err := s.userRepository.Anonymize(ctx, userID, now); err != nil {
+				return err
+			}
+
+			if _, err := s.auditEventRepository.Append(ctx, event); err != nil {
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return i, err
+		}
+
+		// The thumbnails are served publicly, so they go once the user no longer refers to them.
+		if avatarKey != nil {
+			deleteAvatar(ctx, s.blobStore, *avatarKey)
+		}
+	}
+	return len(userIDs), nil
+}
@@ -0,0 +1,265 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/SawitProRecruitment/UserService/service"
+	"github.com/SawitProRecruitment/UserService/storage"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const deletionGracePeriod = 24 * time.Hour
+
+type AccountServiceTestSuite struct {
+	suite.Suite
+	ctrl                 *gomock.Controller
+	tokenManager         *service.MockTokenManager
+	userRepository       *repository.MockUserRepository
+	loginLogRepository   *repository.MockLoginLogRepository
+	auditEventRepository *repository.MockAuditEventRepository
+	transactionManager   *repository.MockTransactionManager
+	blobStore            *storage.MockBlobStore
+	sut                  *service.AccountServiceImpl
+}
+
+func (s *AccountServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.tokenManager = service.NewMockTokenManager(s.ctrl)
+	s.userRepository = repository.NewMockUserRepository(s.ctrl)
+	s.loginLogRepository = repository.NewMockLoginLogRepository(s.ctrl)
+	s.auditEventRepository = repository.NewMockAuditEventRepository(s.ctrl)
+	s.transactionManager = newPassThroughTransactionManager(s.ctrl)
+	s.blobStore = storage.NewMockBlobStore(s.ctrl)
+	s.sut = service.NewAccountServiceImpl(s.userRepository, s.loginLogRepository, s.auditEventRepository, s.transactionManager, s.tokenManager, s.blobStore, deletionGracePeriod)
+}
+
+func (s *AccountServiceTestSuite) AfterTest(suiteName, testName string) {
+	s.ctrl.Finish()
+}
+
+func TestAccountServiceImpl(t *testing.T) {
+	suite.Run(t, new(AccountServiceTestSuite))
+}
+
+func (s *AccountServiceTestSuite) TestChangeStatusGivenUnknownStatusShouldReturnInvalidInputError() {
+	err := s.sut.ChangeStatus(context.Background(), uuid.New(), generated.UpdateUserStatusRequest{Status: "banned"})
+
+	s.Equal(common.ErrInvalidInput, err.ErrType)
+}
+
+func (s *AccountServiceTestSuite) TestChangeStatusGivenDisallowedTransitionShouldReturnInvalidStatusTransitionError() {
+	ctx := context.Background()
+	userID := uuid.New()
+	user := model.User{ID: userID, Status: model.UserStatusDeleted}
+
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(userID)).Return(&user, nil)
+
+	err := s.sut.ChangeStatus(ctx, userID, generated.UpdateUserStatusRequest{Status: generated.UpdateUserStatusRequestStatusSuspended})
+
+	s.Equal(common.ErrInvalidStatusTransition, err.ErrType)
+}
+
+func (s *AccountServiceTestSuite) TestChangeStatusGivenAllowedTransitionShouldUpdateStatus() {
+	ctx := context.Background()
+	userID := uuid.New()
+	user := model.User{ID: userID, Status: model.UserStatusActive}
+
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(userID)).Return(&user, nil)
+	s.userRepository.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(userID), gomock.Eq(model.UserStatusActive), gomock.Eq(model.UserStatusSuspended), gomock.Any()).Return(nil)
+	s.auditEventRepository.EXPECT().Append(gomock.Eq(ctx), gomock.Any()).DoAndReturn(func(ctx context.Context, event model.AuditEvent) (model.AuditEvent, *common.CustomError) {
+		s.Equal(model.AuditActorAdmin, event.ActorType)
+		s.Equal(model.AuditActionStatusChanged, event.Action)
+		return event, nil
+	})
+
+	err := s.sut.ChangeStatus(ctx, userID, generated.UpdateUserStatusRequest{Status: generated.UpdateUserStatusRequestStatusSuspended})
+
+	s.Nil(err)
+}
+
+func (s *AccountServiceTestSuite) TestDeleteAccountGivenWrongPasswordShouldReturnInvalidInputError() {
+	accessToken := "access token"
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+	user := s.newUser("myPassw0rd!", model.UserStatusActive)
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: user.ID, IssuedAt: time.Now()}, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(user.ID)).Return(&user, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(ctx), gomock.Eq(user.ID)).Return(&user, nil)
+
+	_, err := s.sut.DeleteAccount(ctx, generated.DeleteAccountRequest{Password: "wrongPassw0rd!"})
+
+	s.Equal(common.ErrInvalidInput, err.ErrType)
+}
+
+func (s *AccountServiceTestSuite) TestDeleteAccountShouldMarkUserDeletedAndRevokeTokens() {
+	accessToken := "access token"
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+	user := s.newUser("myPassw0rd!", model.UserStatusActive)
+	// Cached users come without their password hash.
+	cached := user
+	cached.PasswordHash = ""
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: user.ID, IssuedAt: time.Now()}, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(user.ID)).Return(&cached, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(ctx), gomock.Eq(user.ID)).Return(&user, nil)
+	s.userRepository.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(user.ID), gomock.Eq(model.UserStatusActive), gomock.Eq(model.UserStatusDeleted), gomock.Any()).Return(nil)
+	s.userRepository.EXPECT().RevokeTokens(gomock.Eq(ctx), gomock.Eq(user.ID), gomock.Any()).Return(nil)
+	s.auditEventRepository.EXPECT().Append(gomock.Eq(ctx), gomock.Any()).Return(model.AuditEvent{}, nil)
+
+	result, err := s.sut.DeleteAccount(ctx, generated.DeleteAccountRequest{Password: "myPassw0rd!"})
+
+	s.Nil(err)
+	s.WithinDuration(time.Now().Add(deletionGracePeriod), result.RestorableUntil, time.Minute)
+}
+
+func (s *AccountServiceTestSuite) TestDeleteAccountGivenAuditFailureShouldFailTheTransaction() {
+	accessToken := "access token"
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+	user := s.newUser("myPassw0rd!", model.UserStatusActive)
+	auditErr := common.NewCustomError(common.ErrUnexpectedError, "audit failed")
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: user.ID, IssuedAt: time.Now()}, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(user.ID)).Return(&user, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(ctx), gomock.Eq(user.ID)).Return(&user, nil)
+	s.userRepository.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(user.ID), gomock.Eq(model.UserStatusActive), gomock.Eq(model.UserStatusDeleted), gomock.Any()).Return(nil)
+	s.userRepository.EXPECT().RevokeTokens(gomock.Eq(ctx), gomock.Eq(user.ID), gomock.Any()).Return(nil)
+	s.auditEventRepository.EXPECT().Append(gomock.Eq(ctx), gomock.Any()).Return(model.AuditEvent{}, auditErr)
+
+	_, err := s.sut.DeleteAccount(ctx, generated.DeleteAccountRequest{Password: "myPassw0rd!"})
+
+	s.Equal(auditErr, err)
+}
+
+func (s *AccountServiceTestSuite) TestDeleteAccountGivenRevokedTokenShouldReturnUnauthorizedError() {
+	accessToken := "access token"
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+	user := s.newUser("myPassw0rd!", model.UserStatusActive)
+	revokedAt := time.Now()
+	user.TokensRevokedAt = &revokedAt
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: user.ID, IssuedAt: revokedAt.Add(-time.Minute)}, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(user.ID)).Return(&user, nil)
+
+	_, err := s.sut.DeleteAccount(ctx, generated.DeleteAccountRequest{Password: "myPassw0rd!"})
+
+	s.Equal(common.ErrUnauthorized, err.ErrType)
+}
+
+func (s *AccountServiceTestSuite) TestRestoreAccountWithinGracePeriodShouldActivateUser() {
+	ctx := context.Background()
+	user := s.newUser("myPassw0rd!", model.UserStatusDeleted)
+	user.StatusChangedAt = time.Now().Add(-time.Hour)
+
+	s.userRepository.EXPECT().GetByPhoneNumber(gomock.Eq(ctx), gomock.Eq(user.PhoneNumber)).Return(&user, nil)
+	s.userRepository.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(user.ID), gomock.Eq(model.UserStatusDeleted), gomock.Eq(model.UserStatusActive), gomock.Any()).Return(nil)
+	s.auditEventRepository.EXPECT().Append(gomock.Eq(ctx), gomock.Any()).Return(model.AuditEvent{}, nil)
+
+	err := s.sut.RestoreAccount(ctx, generated.RestoreAccountRequest{PhoneNumber: user.PhoneNumber, Password: "myPassw0rd!"})
+
+	s.Nil(err)
+}
+
+// Numbers the re-encryption job could not parse are kept as stored, so they are looked up as written.
+func (s *AccountServiceTestSuite) TestRestoreAccountGivenUnparseablePhoneNumberShouldLookItUpAsWritten() {
+	ctx := context.Background()
+	user := s.newUser("myPassw0rd!", model.UserStatusDeleted)
+	user.PhoneNumber = "12345"
+	user.StatusChangedAt = time.Now().Add(-time.Hour)
+
+	s.userRepository.EXPECT().GetByPhoneNumber(gomock.Eq(ctx), gomock.Eq("12345")).Return(&user, nil)
+	s.userRepository.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(user.ID), gomock.Eq(model.UserStatusDeleted), gomock.Eq(model.UserStatusActive), gomock.Any()).Return(nil)
+	s.auditEventRepository.EXPECT().Append(gomock.Eq(ctx), gomock.Any()).Return(model.AuditEvent{}, nil)
+
+	err := s.sut.RestoreAccount(ctx, generated.RestoreAccountRequest{PhoneNumber: "12345", Password: "myPassw0rd!"})
+
+	s.Nil(err)
+}
+
+func (s *AccountServiceTestSuite) TestRestoreAccountAfterGracePeriodShouldReturnAccountDeletedError() {
+	ctx := context.Background()
+	user := s.newUser("myPassw0rd!", model.UserStatusDeleted)
+	user.StatusChangedAt = time.Now().Add(-2 * deletionGracePeriod)
+
+	s.userRepository.EXPECT().GetByPhoneNumber(gomock.Eq(ctx), gomock.Eq(user.PhoneNumber)).Return(&user, nil)
+
+	err := s.sut.RestoreAccount(ctx, generated.RestoreAccountRequest{PhoneNumber: user.PhoneNumber, Password: "myPassw0rd!"})
+
+	s.Equal(common.ErrAccountDeleted, err.ErrType)
+}
+
+func (s *AccountServiceTestSuite) TestAnonymizeDeletedAccountsShouldDeleteLoginLogsAndAnonymizeUsers() {
+	ctx := context.Background()
+	userIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	s.userRepository.EXPECT().GetDeletedBefore(gomock.Eq(ctx), gomock.Any(), gomock.Eq(10)).Return(userIDs, nil)
+	for _, userID := range userIDs {
+		s.userRepository.EXPECT().GetByUserID(gomock.Eq(ctx), gomock.Eq(userID)).Return(&model.User{ID: userID, Status: model.UserStatusDeleted}, nil)
+		s.loginLogRepository.EXPECT().DeleteByUserID(gomock.Eq(ctx), gomock.Eq(userID)).Return(nil)
+		s.userRepository.EXPECT().Anonymize(gomock.Eq(ctx), gomock.Eq(userID), gomock.Any()).Return(nil)
+		s.auditEventRepository.EXPECT().Append(gomock.Eq(ctx), gomock.Any()).Return(model.AuditEvent{}, nil)
+	}
+
+	count, err := s.sut.AnonymizeDeletedAccounts(ctx, 10)
+
+	s.Nil(err)
+	s.Equal(len(userIDs), count)
+}
+
+func (s *AccountServiceTestSuite) TestAnonymizeDeletedAccountsShouldDeleteAvatarAfterCommit() {
+	ctx := context.Background()
+	avatarKey := "avatars/jasuke"
+	user := model.User{ID: uuid.New(), Status: model.UserStatusDeleted, AvatarKey: &avatarKey}
+
+	s.userRepository.EXPECT().GetDeletedBefore(gomock.Eq(ctx), gomock.Any(), gomock.Eq(10)).Return([]uuid.UUID{user.ID}, nil)
+	anonymized := s.userRepository.EXPECT().GetByUserID(gomock.Eq(ctx), gomock.Eq(user.ID)).Return(&user, nil)
+	s.loginLogRepository.EXPECT().DeleteByUserID(gomock.Eq(ctx), gomock.Eq(user.ID)).Return(nil)
+	anonymized = s.userRepository.EXPECT().Anonymize(gomock.Eq(ctx), gomock.Eq(user.ID), gomock.Any()).Return(nil).After(anonymized)
+	s.auditEventRepository.EXPECT().Append(gomock.Eq(ctx), gomock.Any()).Return(model.AuditEvent{}, nil)
+	for _, name := range []string{"small", "medium", "large"} {
+		s.blobStore.EXPECT().Delete(gomock.Any(), avatarKey+"/"+name+".jpg").Return(nil).After(anonymized)
+	}
+
+	count, err := s.sut.AnonymizeDeletedAccounts(ctx, 10)
+
+	s.Nil(err)
+	s.Equal(1, count)
+}
+
+func (s *AccountServiceTestSuite) TestAnonymizeDeletedAccountsGivenFailureShouldKeepAvatar() {
+	ctx := context.Background()
+	avatarKey := "avatars/jasuke"
+	user := model.User{ID: uuid.New(), Status: model.UserStatusDeleted, AvatarKey: &avatarKey}
+
+	s.userRepository.EXPECT().GetDeletedBefore(gomock.Eq(ctx), gomock.Any(), gomock.Eq(10)).Return([]uuid.UUID{user.ID}, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(ctx), gomock.Eq(user.ID)).Return(&user, nil)
+	s.loginLogRepository.EXPECT().DeleteByUserID(gomock.Eq(ctx), gomock.Eq(user.ID)).Return(nil)
+	s.userRepository.EXPECT().Anonymize(gomock.Eq(ctx), gomock.Eq(user.ID), gomock.Any()).
+		Return(common.NewCustomError(common.ErrUnavailable, "database is unavailable, please retry later"))
+
+	count, err := s.sut.AnonymizeDeletedAccounts(ctx, 10)
+
+	s.Require().NotNil(err)
+	s.Equal(0, count)
+}
+
+func (s *AccountServiceTestSuite) newUser(password string, status model.UserStatus) model.User {
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	s.Require().NoError(err)
+
+	return model.User{
+		ID:           uuid.New(),
+		PhoneNumber:  "+628111111111",
+		FullName:     "Jasuke",
+		PasswordHash: string(passwordHash),
+		Status:       status,
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultAuditEventPageSize = 50
+	maxAuditEventPageSize     = 100
+	auditVerificationPageSize = 500
+)
+
+type AuditServiceImpl struct {
+	auditEventRepository repository.AuditEventRepository
+}
+
+func NewAuditServiceImpl(auditEventRepository repository.AuditEventRepository) *AuditServiceImpl {
+	return &AuditServiceImpl{
+		auditEventRepository: auditEventRepository,
+	}
+}
+
+func (s *AuditServiceImpl) ListEvents(ctx context.Context, params generated.GetApiV1AdminAuditEventsParams) (generated.AuditEventList, *common.CustomError) {
+	filter := model.AuditEventFilter{
+		TargetUserID: params.TargetUserId,
+		ActorID:      params.ActorId,
+		Limit:        defaultAuditEventPageSize,
+	}
+
+	if params.Action != nil {
+		action := model.AuditAction(*params.Action)
+		filter.Action = &action
+	}
+
+	if params.BeforeSequence != nil {
+		filter.BeforeSequence = *params.BeforeSequence
+	}
+
+	if params.Limit != nil {
+		if *params.Limit < 1 || *params.Limit > maxAuditEventPageSize {
+			return generated.AuditEventList{}, common.NewCustomError(common.ErrInvalidInput, "invalid request params", "limit must be between 1 and 100")
+		}
+		filter.Limit = *params.Limit
+	}
+
+	events, err := s.auditEventRepository.Find(ctx, filter)
+	if err != nil {
+		return generated.AuditEventList{}, err
+	}
+
+	result := generated.AuditEventList{
+		Events: make([]generated.AuditEvent, 0, len(events)),
+	}
+	for _, event := range events {
+		result.Events = append(result.Events, toAuditEventResponse(event))
+	}
+
+	if len(events) == filter.Limit {
+		result.NextBeforeSequence = &events[len(events)-1].Sequence
+	}
+	return result, nil
+}
+
+// VerifyChain walks the whole chain from the first event and checks that every event links to its
+// predecessor and still hashes to the stored value.
+func (s *AuditServiceImpl) VerifyChain(ctx context.Context) (generated.AuditChainVerification, *common.CustomError) {
+	result := generated.AuditChainVerification{Valid: true}
+
+	previousHash := model.AuditGenesisHash
+	var afterSequence int64
+
+	for {
+		events, err := s.auditEventRepository.Find(ctx, model.AuditEventFilter{
+			AfterSequence: afterSequence,
+			Ascending:     true,
+			Limit:         auditVerificationPageSize,
+		})
+		if err != nil {
+			return generated.AuditChainVerification{}, err
+		}
+
+		for _, event := range events {
+			result.CheckedEvents++
+
+			if event.PreviousHash != previousHash || event.ComputeHash() != event.Hash {
+				result.Valid = false
+				result.BrokenAtSequence = &event.Sequence
+				return result, nil
+			}
+
+			previousHash = event.Hash
+			afterSequence = event.Sequence
+		}
+
+		if len(events) < auditVerificationPageSize {
+			return result, nil
+		}
+	}
+}
+
+func toAuditEventResponse(event model.AuditEvent) generated.AuditEvent {
+	changes := make(map[string]generated.AuditChange, len(event.Changes))
+	for field, change := range event.Changes {
+		// Events recorded before personal data was left out still hold its values in the database.
+		if change.Redacted || personalAuditFields[field] {
+			redacted := true
+			changes[field] = generated.AuditChange{Redacted: &redacted}
+			continue
+		}
+		changes[field] = generated.AuditChange{Before: change.Before, After: change.After}
+	}
+
+	return generated.AuditEvent{
+		Id:           event.ID,
+		Sequence:     event.Sequence,
+		ActorType:    generated.AuditEventActorType(event.ActorType),
+		ActorId:      event.ActorID,
+		TargetUserId: event.TargetUserID,
+		Action:       string(event.Action),
+		Changes:      changes,
+		IpAddress:    event.IPAddress,
+		RequestId:    event.RequestID,
+		CreatedAt:    event.CreatedAt,
+		PreviousHash: event.PreviousHash,
+		Hash:         event.Hash,
+	}
+}
+
+// personalAuditFields hold personal data. Their changes are audited without values, since the append-only
+// audit log could not erase them when the user is anonymized.
+var personalAuditFields = map[string]bool{
+	"full_name":     true,
+	"phone_number":  true,
+	"email":         true,
+	"date_of_birth": true,
+	"avatar":        true,
+}
+
+// newAuditEvent builds an event about targetUserID, taking the client IP and request ID stored in ctx
+// by the handler. actorID is nil for admin and system actors. Changes to personal fields lose their values.
+func newAuditEvent(ctx context.Context, actorType model.AuditActorType, actorID *uuid.UUID, targetUserID uuid.UUID, action model.AuditAction, changes map[string]model.AuditChange) model.AuditEvent {
+	clientIP, _ := ctx.Value(common.KeyClientIP).(string)
+	requestID, _ := ctx.Value(common.KeyRequestID).(string)
+
+	for field := range changes {
+		if personalAuditFields[field] {
+			changes[field] = model.AuditChange{Redacted: true}
+		}
+	}
+
+	return model.AuditEvent{
+		ActorType:    actorType,
+		ActorID:      actorID,
+		TargetUserID: &targetUserID,
+		Action:       action,
+		Changes:      changes,
+		IPAddress:    clientIP,
+		RequestID:    requestID,
+		CreatedAt:    time.Now(),
+	}
+}
+
+// fieldChange records a change of a field that always has a value.
+func fieldChange(before, after string) model.AuditChange {
+	change := model.AuditChange{}
+	if before != "" {
+		change.Before = &before
+	}
+	if after != "" {
+		change.After = &after
+	}
+	return change
+}
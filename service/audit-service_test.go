@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/SawitProRecruitment/UserService/service"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditServiceTestSuite struct {
+	suite.Suite
+	ctrl                 *gomock.Controller
+	auditEventRepository *repository.MockAuditEventRepository
+	sut                  *service.AuditServiceImpl
+}
+
+func (s *AuditServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.auditEventRepository = repository.NewMockAuditEventRepository(s.ctrl)
+	s.sut = service.NewAuditServiceImpl(s.auditEventRepository)
+}
+
+func (s *AuditServiceTestSuite) AfterTest(suiteName, testName string) {
+	s.ctrl.Finish()
+}
+
+func TestAuditServiceImpl(t *testing.T) {
+	suite.Run(t, new(AuditServiceTestSuite))
+}
+
+func (s *AuditServiceTestSuite) TestVerifyChainGivenIntactChainShouldBeValid() {
+	ctx := context.Background()
+	events := s.newChain(3)
+
+	s.auditEventRepository.EXPECT().Find(gomock.Eq(ctx), gomock.Any()).Return(events, nil)
+
+	result, err := s.sut.VerifyChain(ctx)
+
+	s.Nil(err)
+	s.True(result.Valid)
+	s.Equal(int64(3), result.CheckedEvents)
+}
+
+func (s *AuditServiceTestSuite) TestVerifyChainGivenTamperedEventShouldReportIt() {
+	ctx := context.Background()
+	events := s.newChain(3)
+	tampered := "Someone Else"
+	events[1].Changes["full_name"] = model.AuditChange{After: &tampered}
+
+	s.auditEventRepository.EXPECT().Find(gomock.Eq(ctx), gomock.Any()).Return(events, nil)
+
+	result, err := s.sut.VerifyChain(ctx)
+
+	s.Nil(err)
+	s.False(result.Valid)
+	s.Equal(events[1].Sequence, *result.BrokenAtSequence)
+}
+
+func (s *AuditServiceTestSuite) TestListEventsGivenEventWithPersonalValuesShouldNotReturnThem() {
+	ctx := context.Background()
+	events := s.newChain(1)
+	status := string(model.UserStatusActive)
+	events[0].Changes["status"] = model.AuditChange{After: &status}
+
+	s.auditEventRepository.EXPECT().Find(gomock.Eq(ctx), gomock.Any()).Return(events, nil)
+
+	result, err := s.sut.ListEvents(ctx, generated.GetApiV1AdminAuditEventsParams{})
+
+	s.Nil(err)
+	s.Require().Len(result.Events, 1)
+	redacted := true
+	s.Equal(map[string]generated.AuditChange{
+		"full_name": {Redacted: &redacted},
+		"status":    {After: &status},
+	}, result.Events[0].Changes)
+}
+
+func (s *AuditServiceTestSuite) newChain(length int) []model.AuditEvent {
+	events := []model.AuditEvent{}
+	previousHash := model.AuditGenesisHash
+
+	for i := 0; i < length; i++ {
+		userID := uuid.New()
+		fullName := "Jasuke"
+
+		event := model.AuditEvent{
+			ID:           uuid.New(),
+			Sequence:     int64(i + 1),
+			ActorType:    model.AuditActorUser,
+			ActorID:      &userID,
+			TargetUserID: &userID,
+			Action:       model.AuditActionUserRegistered,
+			Changes:      map[string]model.AuditChange{"full_name": {After: &fullName}},
+			CreatedAt:    time.Now(),
+			PreviousHash: previousHash,
+		}
+		event.Hash = event.ComputeHash()
+
+		previousHash = event.Hash
+		events = append(events, event)
+	}
+	return events
+}
@@ -9,32 +9,45 @@ import (
 	"github.com/SawitProRecruitment/UserService/generated"
 	"github.com/SawitProRecruitment/UserService/model"
 	"github.com/SawitProRecruitment/UserService/repository"
-
-	"golang.org/x/crypto/bcrypt"
+	"github.com/SawitProRecruitment/UserService/tracing"
+	"github.com/google/uuid"
 )
 
 type AuthServiceImpl struct {
-	userRepository     repository.UserRepository
-	loginLogRepository repository.LoginLogRepository
-	tokenManager       TokenManager
+	userRepository       repository.UserRepository
+	loginRecorder        LoginRecorder
+	auditEventRepository repository.AuditEventRepository
+	transactionManager   repository.TransactionManager
+	tokenManager         TokenManager
+	passwordHashCost     int
 }
 
-func NewAuthServiceImpl(userRepository repository.UserRepository, loginLogRepository repository.LoginLogRepository, tokenManager TokenManager) *AuthServiceImpl {
+// NewAuthServiceImpl hashes new passwords with bcrypt at passwordHashCost.
+func NewAuthServiceImpl(userRepository repository.UserRepository, loginRecorder LoginRecorder, auditEventRepository repository.AuditEventRepository, transactionManager repository.TransactionManager, tokenManager TokenManager, passwordHashCost int) *AuthServiceImpl {
 	return &AuthServiceImpl{
-		userRepository:     userRepository,
-		loginLogRepository: loginLogRepository,
-		tokenManager:       tokenManager,
+		userRepository:       userRepository,
+		loginRecorder:        loginRecorder,
+		auditEventRepository: auditEventRepository,
+		transactionManager:   transactionManager,
+		tokenManager:         tokenManager,
+		passwordHashCost:     passwordHashCost,
 	}
 }
 
-func (s *AuthServiceImpl) Register(ctx context.Context, params generated.RegisterRequest) (generated.RegisterResponse, *common.CustomError) {
-	if err := s.validateRegisterRequest(params); err != nil {
-		return generated.RegisterResponse{}, err
+func (s *AuthServiceImpl) Register(ctx context.Context, params generated.RegisterRequest) (response generated.RegisterResponse, errRegister *common.CustomError) {
+	ctx, span := tracing.Start(ctx, "AuthService.Register")
+	defer func() { endSpan(span, errRegister) }()
+
+	params, errValidate := s.validateRegisterRequest(params)
+	if errValidate != nil {
+		registrationsTotal.Inc("invalid_input")
+		return generated.RegisterResponse{}, errValidate
 	}
 
-	passwordHash, err := bcrypt.GenerateFromPassword([]byte(params.Password), bcrypt.DefaultCost)
+	passwordHash, err := hashPassword(ctx, params.Password, s.passwordHashCost)
 	if err != nil {
-		return generated.RegisterResponse{}, common.NewCustomError(common.ErrUnexpectedError, err.Error())
+		registrationsTotal.Inc("error")
+		return generated.RegisterResponse{}, common.WrapError(common.ErrUnexpectedError, err, "error hashing password")
 	}
 
 	user := model.User{
@@ -43,60 +56,105 @@ func (s *AuthServiceImpl) Register(ctx context.Context, params generated.Registe
 		PasswordHash: string(passwordHash),
 	}
 
-	userID, errSave := s.userRepository.Save(ctx, user)
+	var userID uuid.UUID
+	errSave := s.transactionManager.WithinTransaction(ctx, func(ctx context.Context) *common.CustomError {
+		var err *common.CustomError
+		if userID, err = s.userRepository.Save(ctx, user); err != nil {
+			return err
+		}
+
+		event := newAuditEvent(ctx, model.AuditActorUser, &userID, userID, model.AuditActionUserRegistered, map[string]model.AuditChange{
+			"full_name":    fieldChange("", user.FullName),
+			"phone_number": fieldChange("", user.PhoneNumber),
+		})
+		if _, err := s.auditEventRepository.Append(ctx, event); err != nil {
+			return err
+		}
+		return nil
+	})
 	if errSave != nil {
+		if errSave.ErrType == common.ErrEntityAlreadyExists {
+			registrationsTotal.Inc("already_exists")
+		} else {
+			registrationsTotal.Inc("error")
+		}
 		return generated.RegisterResponse{}, errSave
 	}
+
+	registrationsTotal.Inc("success")
 	return generated.RegisterResponse{UserId: userID}, nil
 }
 
-func (s *AuthServiceImpl) validateRegisterRequest(params generated.RegisterRequest) *common.CustomError {
+// validateRegisterRequest returns params with the full name and the phone number normalized.
+func (s *AuthServiceImpl) validateRegisterRequest(params generated.RegisterRequest) (generated.RegisterRequest, *common.CustomError) {
 	errDetails := []string{}
 
-	params.FullName = strings.TrimSpace(params.FullName)
-	if err := validateFullName(params.FullName); err != nil {
+	fullName, err := normalizeFullName(params.FullName)
+	if err != nil {
 		errDetails = append(errDetails, err.Details...)
 	}
+	params.FullName = fullName
 
-	params.PhoneNumber = strings.TrimSpace(params.PhoneNumber)
-	if err := validatePhoneNumber(params.PhoneNumber); err != nil {
+	phoneNumber, err := normalizePhoneNumber(params.PhoneNumber)
+	if err != nil {
 		errDetails = append(errDetails, err.Details...)
 	}
+	params.PhoneNumber = phoneNumber
 
-	params.Password = strings.TrimSpace(params.Password)
-	if err := validatePassword(params.Password); err != nil {
+	if err := validatePassword(strings.TrimSpace(params.Password)); err != nil {
 		errDetails = append(errDetails, err.Details...)
 	}
 
 	if len(errDetails) != 0 {
-		return common.NewCustomError(common.ErrInvalidInput, "invalid request params", errDetails...)
+		return generated.RegisterRequest{}, common.NewCustomError(common.ErrInvalidInput, "invalid request params", errDetails...)
 	}
-	return nil
+	return params, nil
 }
 
-func (s *AuthServiceImpl) Login(ctx context.Context, params generated.LoginRequest) (generated.LoginResponse, *common.CustomError) {
-	user, err := s.userRepository.GetByPhoneNumber(ctx, params.PhoneNumber)
+func (s *AuthServiceImpl) Login(ctx context.Context, params generated.LoginRequest) (response generated.LoginResponse, err *common.CustomError) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer func() { endSpan(span, err) }()
+
+	phoneNumber, parsed := phoneNumberLookupKey(params.PhoneNumber)
+	user, err := s.userRepository.GetByPhoneNumber(ctx, phoneNumber)
 	if err != nil {
 		if err.ErrType == common.ErrEntityNotFound {
-			return generated.LoginResponse{}, common.NewCustomError(common.ErrInvalidInput, "phone number or password is incorrect")
+			reason := loginReasonUnknownUser
+			if !parsed {
+				reason = loginReasonInvalidPhoneNumber
+			}
+			return loginFailed(reason, common.NewCustomError(common.ErrInvalidInput, "phone number or password is incorrect"))
 		}
-		return generated.LoginResponse{}, err
+		return loginFailed(loginReasonError, err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(params.Password)); err != nil {
-		return generated.LoginResponse{}, common.NewCustomError(common.ErrInvalidInput, "phone number or password is incorrect")
+	if err := comparePassword(ctx, user.PasswordHash, params.Password); err != nil {
+		return loginFailed(loginReasonWrongPassword, common.NewCustomError(common.ErrInvalidInput, "phone number or password is incorrect"))
 	}
 
+	if err := checkUserStatus(user.Status); err != nil {
+		return loginFailed("account_"+string(user.Status), err)
+	}
+
+	_, tokenSpan := tracing.Start(ctx, "TokenManager.GenerateToken")
 	tokenString, err := s.tokenManager.GenerateToken(user.ID)
+	endSpan(tokenSpan, err)
 	if err != nil {
-		return generated.LoginResponse{}, err
+		return loginFailed(loginReasonError, err)
 	}
 
 	loginLog := model.LoginLog{
 		UserID:  user.ID,
 		LoginAt: time.Now(),
 	}
-	go s.loginLogRepository.Save(ctx, loginLog)
+	s.loginRecorder.Record(ctx, loginLog)
+	loginsTotal.Inc("success", "")
 
 	return generated.LoginResponse{UserId: user.ID, AccessToken: tokenString}, nil
 }
+
+// loginFailed counts a failed login by reason and returns err.
+func loginFailed(reason string, err *common.CustomError) (generated.LoginResponse, *common.CustomError) {
+	loginsTotal.Inc("failure", reason)
+	return generated.LoginResponse{}, err
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/SawitProRecruitment/UserService/tracing"
+)
+
+// authenticateUser resolves the access token stored in ctx into the user it was issued for.
+// Revoked tokens and accounts that are not active are rejected. The user is read from the primary database,
+// so that writes compare against its current data and a revocation or suspension takes effect at once.
+func authenticateUser(ctx context.Context, tokenManager TokenManager, userRepository repository.UserRepository) (*model.User, *common.CustomError) {
+	claims, err := validateAccessToken(ctx, tokenManager)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := userRepository.GetByUserID(common.WithPrimaryReads(ctx), claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkAccess(claims, model.UserAccessState{Status: user.Status, TokensRevokedAt: user.TokensRevokedAt}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// authenticateReader is authenticateUser for requests that only read the user. Only the status and token
+// revocation are read from the primary, the user itself may come from a replica or the user cache. A user
+// the replica does not have yet is read from the primary.
+func authenticateReader(ctx context.Context, tokenManager TokenManager, userRepository repository.UserRepository) (*model.User, *common.CustomError) {
+	claims, err := validateAccessToken(ctx, tokenManager)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := userRepository.GetAccessState(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccess(claims, state); err != nil {
+		return nil, err
+	}
+
+	user, err := userRepository.GetByUserID(ctx, claims.UserID)
+	if err != nil && err.ErrType == common.ErrEntityNotFound {
+		user, err = userRepository.GetByUserID(common.WithPrimaryReads(ctx), claims.UserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user.Status = state.Status
+	user.TokensRevokedAt = state.TokensRevokedAt
+	return user, nil
+}
+
+func validateAccessToken(ctx context.Context, tokenManager TokenManager) (TokenClaims, *common.CustomError) {
+	accessToken, ok := ctx.Value(common.KeyAccessToken).(string)
+	if !ok {
+		tokenValidationFailuresTotal.Inc("missing")
+		return TokenClaims{}, common.NewCustomError(common.ErrUnauthorized, "invalid access token")
+	}
+
+	_, span := tracing.Start(ctx, "TokenManager.ValidateToken")
+	claims, err := tokenManager.ValidateToken(accessToken)
+	endSpan(span, err)
+	if err != nil {
+		return TokenClaims{}, common.NewCustomError(common.ErrUnauthorized, err.Message)
+	}
+	return claims, nil
+}
+
+// checkAccess rejects tokens issued before the tokens of their user were revoked, and users that are not
+// active.
+func checkAccess(claims TokenClaims, state model.UserAccessState) *common.CustomError {
+	if state.TokensRevokedAt != nil && claims.IssuedAt.Before(*state.TokensRevokedAt) {
+		tokenValidationFailuresTotal.Inc("revoked")
+		return common.NewCustomError(common.ErrUnauthorized, "access token has been revoked")
+	}
+	return checkUserStatus(state.Status)
+}
+
+// checkUserStatus rejects every account that is not active, using a distinct error type per status
+// so that clients can tell a suspended account from a locked or deleted one.
+func checkUserStatus(status model.UserStatus) *common.CustomError {
+	switch status {
+	case model.UserStatusActive:
+		return nil
+	case model.UserStatusPending:
+		return common.NewCustomError(common.ErrAccountPending, "account is pending activation")
+	case model.UserStatusSuspended:
+		return common.NewCustomError(common.ErrAccountSuspended, "account is suspended")
+	case model.UserStatusLocked:
+		return common.NewCustomError(common.ErrAccountLocked, "account is locked")
+	case model.UserStatusDeleted:
+		return common.NewCustomError(common.ErrAccountDeleted, "account is deleted")
+	default:
+		return common.NewCustomError(common.ErrUnexpectedError, fmt.Sprintf("user has unknown status %q", status))
+	}
+}
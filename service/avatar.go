@@ -0,0 +1,102 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/SawitProRecruitment/UserService/imaging"
+	"github.com/SawitProRecruitment/UserService/logging"
+	"github.com/SawitProRecruitment/UserService/storage"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/google/uuid"
+)
+
+const (
+	MaxAvatarSize = 5 << 20
+	// maxAvatarPixels bounds the memory a decoded upload takes, 40 megapixels are about 160 MiB.
+	maxAvatarPixels = 40_000_000
+
+	avatarContentType = "image/jpeg"
+)
+
+// avatarSizes lists the thumbnails rendered from an uploaded avatar, they are stored as <key>/<name>.jpg.
+var avatarSizes = []struct {
+	name string
+	size int
+}{
+	{name: "small", size: 64},
+	{name: "medium", size: 256},
+	{name: "large", size: 512},
+}
+
+var avatarMediaTypes = []string{"image/jpeg", "image/png", "image/gif"}
+
+// storeAvatar validates the uploaded image and stores its thumbnails under a new key, so the URLs of a
+// replaced avatar never serve the new image from a stale cache.
+func storeAvatar(ctx context.Context, blobStore storage.BlobStore, userID uuid.UUID, content io.Reader) (string, *common.CustomError) {
+	data, err := io.ReadAll(io.LimitReader(content, MaxAvatarSize+1))
+	if err != nil {
+		return "", common.NewCustomError(common.ErrInvalidInput, "avatar could not be read")
+	}
+	if len(data) > MaxAvatarSize {
+		return "", common.NewCustomError(common.ErrPayloadTooLarge, fmt.Sprintf("avatar must be at most %d MiB", MaxAvatarSize>>20))
+	}
+
+	// The declared content type is not trusted, the format is detected from the content itself.
+	if !mimetype.EqualsAny(mimetype.Detect(data).String(), avatarMediaTypes...) {
+		return "", common.NewCustomError(common.ErrUnsupportedMediaType, "avatar must be a JPEG, PNG or GIF image")
+	}
+
+	img, err := imaging.Decode(data, maxAvatarPixels)
+	if err == imaging.ErrTooManyPixels {
+		return "", common.NewCustomError(common.ErrInvalidInput, "invalid request params", "avatar dimensions are too large")
+	}
+	if err != nil {
+		return "", common.NewCustomError(common.ErrInvalidInput, "invalid request params", "avatar image is corrupted")
+	}
+
+	key := fmt.Sprintf("avatars/%s/%s", userID, uuid.New())
+	for _, avatarSize := range avatarSizes {
+		var buffer bytes.Buffer
+		if err := imaging.EncodeJPEG(&buffer, imaging.Thumbnail(img, avatarSize.size)); err != nil {
+			deleteAvatar(ctx, blobStore, key)
+			return "", common.WrapError(common.ErrUnexpectedError, err, "error encoding avatar")
+		}
+
+		if err := blobStore.Put(ctx, avatarBlobKey(key, avatarSize.name), avatarContentType, &buffer); err != nil {
+			deleteAvatar(ctx, blobStore, key)
+			return "", common.WrapError(common.ErrUnexpectedError, err, "error storing avatar")
+		}
+	}
+	return key, nil
+}
+
+// deleteAvatar removes the thumbnails stored under key. Failures only leave unreferenced blobs behind,
+// so they are logged rather than returned.
+func deleteAvatar(ctx context.Context, blobStore storage.BlobStore, key string) {
+	for _, avatarSize := range avatarSizes {
+		if err := blobStore.Delete(ctx, avatarBlobKey(key, avatarSize.name)); err != nil {
+			logging.FromContext(ctx).Warn("error deleting avatar", "key", avatarBlobKey(key, avatarSize.name), "error", err)
+		}
+	}
+}
+
+func avatarURLs(blobStore storage.BlobStore, key *string) *generated.AvatarUrls {
+	if key == nil {
+		return nil
+	}
+
+	return &generated.AvatarUrls{
+		Small:  blobStore.URL(avatarBlobKey(*key, "small")),
+		Medium: blobStore.URL(avatarBlobKey(*key, "medium")),
+		Large:  blobStore.URL(avatarBlobKey(*key, "large")),
+	}
+}
+
+func avatarBlobKey(key, name string) string {
+	return key + "/" + name + ".jpg"
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/SawitProRecruitment/UserService/storage"
+	"github.com/google/uuid"
+)
+
+type ProfileExportSection struct {
+	blobStore storage.BlobStore
+}
+
+type profileExport struct {
+	ID              uuid.UUID             `json:"id"`
+	FullName        string                `json:"full_name"`
+	PhoneNumber     string                `json:"phone_number"`
+	Email           *string               `json:"email"`
+	EmailVerifiedAt *time.Time            `json:"email_verified_at"`
+	DateOfBirth     *string               `json:"date_of_birth"`
+	Locale          *string               `json:"locale"`
+	Timezone        *string               `json:"timezone"`
+	Avatar          *generated.AvatarUrls `json:"avatar"`
+	LastLoginAt     *time.Time            `json:"last_login_at"`
+	Status          model.UserStatus      `json:"status"`
+	StatusChangedAt time.Time             `json:"status_changed_at"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+}
+
+func NewProfileExportSection(blobStore storage.BlobStore) *ProfileExportSection {
+	return &ProfileExportSection{
+		blobStore: blobStore,
+	}
+}
+
+func (s *ProfileExportSection) Name() string {
+	return "profile"
+}
+
+// Export leaves out the password hash, it is a credential rather than personal data.
+func (s *ProfileExportSection) Export(ctx context.Context, user *model.User) (interface{}, *common.CustomError) {
+	var dateOfBirth *string
+	if user.DateOfBirth != nil {
+		formatted := user.DateOfBirth.Format(dateOfBirthLayout)
+		dateOfBirth = &formatted
+	}
+
+	return profileExport{
+		ID:              user.ID,
+		FullName:        user.FullName,
+		PhoneNumber:     user.PhoneNumber,
+		Email:           user.Email,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		DateOfBirth:     dateOfBirth,
+		Locale:          user.Locale,
+		Timezone:        user.Timezone,
+		Avatar:          avatarURLs(s.blobStore, user.AvatarKey),
+		LastLoginAt:     user.LastLoginAt,
+		Status:          user.Status,
+		StatusChangedAt: user.StatusChangedAt,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+	}, nil
+}
+
+type LoginHistoryExportSection struct {
+	loginLogRepository repository.LoginLogRepository
+}
+
+type loginHistoryExport struct {
+	ID      uuid.UUID `json:"id"`
+	LoginAt time.Time `json:"login_at"`
+}
+
+func NewLoginHistoryExportSection(loginLogRepository repository.LoginLogRepository) *LoginHistoryExportSection {
+	return &LoginHistoryExportSection{
+		loginLogRepository: loginLogRepository,
+	}
+}
+
+func (s *LoginHistoryExportSection) Name() string {
+	return "login_history"
+}
+
+func (s *LoginHistoryExportSection) Export(ctx context.Context, user *model.User) (interface{}, *common.CustomError) {
+	logs, err := s.loginLogRepository.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]loginHistoryExport, 0, len(logs))
+	for _, log := range logs {
+		history = append(history, loginHistoryExport{ID: log.ID, LoginAt: log.LoginAt})
+	}
+	return history, nil
+}
+
+type AuditEventsExportSection struct {
+	auditEventRepository repository.AuditEventRepository
+}
+
+func NewAuditEventsExportSection(auditEventRepository repository.AuditEventRepository) *AuditEventsExportSection {
+	return &AuditEventsExportSection{
+		auditEventRepository: auditEventRepository,
+	}
+}
+
+func (s *AuditEventsExportSection) Name() string {
+	return "audit_events"
+}
+
+func (s *AuditEventsExportSection) Export(ctx context.Context, user *model.User) (interface{}, *common.CustomError) {
+	events := []generated.AuditEvent{}
+	filter := model.AuditEventFilter{
+		TargetUserID: &user.ID,
+		Limit:        auditVerificationPageSize,
+	}
+
+	for {
+		page, err := s.auditEventRepository.Find(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range page {
+			events = append(events, toAuditEventResponse(event))
+		}
+
+		if len(page) < filter.Limit {
+			return events, nil
+		}
+		filter.BeforeSequence = page[len(page)-1].Sequence
+	}
+}
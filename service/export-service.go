@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+)
+
+// ExportSection contributes one entry to a personal data export. Every table that stores data about a
+// user registers a section so that the export stays complete as the schema grows.
+type ExportSection interface {
+	Name() string
+	Export(ctx context.Context, user *model.User) (interface{}, *common.CustomError)
+}
+
+type ExportServiceImpl struct {
+	userRepository repository.UserRepository
+	tokenManager   TokenManager
+	sections       []ExportSection
+}
+
+func NewExportServiceImpl(userRepository repository.UserRepository, tokenManager TokenManager, sections ...ExportSection) *ExportServiceImpl {
+	s := &ExportServiceImpl{
+		userRepository: userRepository,
+		tokenManager:   tokenManager,
+	}
+
+	for _, section := range sections {
+		s.Register(section)
+	}
+	return s
+}
+
+// Register adds a section to every export generated afterwards. It panics on duplicate names since
+// that is a wiring mistake.
+func (s *ExportServiceImpl) Register(section ExportSection) {
+	for _, registered := range s.sections {
+		if registered.Name() == section.Name() {
+			panic(fmt.Sprintf("export section %q is already registered", section.Name()))
+		}
+	}
+	s.sections = append(s.sections, section)
+}
+
+func (s *ExportServiceImpl) Export(ctx context.Context) (generated.PersonalDataExport, *common.CustomError) {
+	user, err := authenticateUser(ctx, s.tokenManager, s.userRepository)
+	if err != nil {
+		return generated.PersonalDataExport{}, err
+	}
+
+	export := generated.PersonalDataExport{
+		UserId:     user.ID,
+		ExportedAt: time.Now(),
+		Sections:   make(map[string]interface{}, len(s.sections)),
+	}
+
+	for _, section := range s.sections {
+		data, err := section.Export(ctx, user)
+		if err != nil {
+			return generated.PersonalDataExport{}, err
+		}
+		export.Sections[section.Name()] = data
+	}
+	return export, nil
+}
@@ -0,0 +1,100 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/SawitProRecruitment/UserService/service"
+	"github.com/SawitProRecruitment/UserService/storage"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExportServiceTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	tokenManager       *service.MockTokenManager
+	userRepository     *repository.MockUserRepository
+	loginLogRepository *repository.MockLoginLogRepository
+	blobStore          *storage.MockBlobStore
+	sut                *service.ExportServiceImpl
+}
+
+func (s *ExportServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.tokenManager = service.NewMockTokenManager(s.ctrl)
+	s.userRepository = repository.NewMockUserRepository(s.ctrl)
+	s.loginLogRepository = repository.NewMockLoginLogRepository(s.ctrl)
+	s.blobStore = storage.NewMockBlobStore(s.ctrl)
+	s.sut = service.NewExportServiceImpl(s.userRepository, s.tokenManager,
+		service.NewProfileExportSection(s.blobStore),
+		service.NewLoginHistoryExportSection(s.loginLogRepository),
+	)
+}
+
+func (s *ExportServiceTestSuite) AfterTest(suiteName, testName string) {
+	s.ctrl.Finish()
+}
+
+func TestExportServiceImpl(t *testing.T) {
+	suite.Run(t, new(ExportServiceTestSuite))
+}
+
+func (s *ExportServiceTestSuite) TestExportShouldIncludeEveryRegisteredSection() {
+	accessToken := "access token"
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+	user := model.User{ID: uuid.New(), FullName: "Jasuke", Status: model.UserStatusActive}
+	logs := []model.LoginLog{{ID: uuid.New(), UserID: user.ID, LoginAt: time.Now()}}
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: user.ID}, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(user.ID)).Return(&user, nil)
+	s.loginLogRepository.EXPECT().GetByUserID(gomock.Eq(ctx), gomock.Eq(user.ID)).Return(logs, nil)
+
+	result, err := s.sut.Export(ctx)
+
+	s.Nil(err)
+	s.Equal(user.ID, result.UserId)
+	s.Contains(result.Sections, "profile")
+	s.Contains(result.Sections, "login_history")
+}
+
+func (s *ExportServiceTestSuite) TestExportShouldIncludeAvatar() {
+	accessToken := "access token"
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+	avatarKey := "avatars/jasuke"
+	user := model.User{ID: uuid.New(), FullName: "Jasuke", Status: model.UserStatusActive, AvatarKey: &avatarKey}
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: user.ID}, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(user.ID)).Return(&user, nil)
+	s.loginLogRepository.EXPECT().GetByUserID(gomock.Eq(ctx), gomock.Eq(user.ID)).Return(nil, nil)
+	s.blobStore.EXPECT().URL(gomock.Any()).DoAndReturn(func(key string) string {
+		return "https://cdn.example.com/" + key
+	}).Times(3)
+
+	result, err := s.sut.Export(ctx)
+	s.Require().Nil(err)
+
+	data, errMarshal := json.Marshal(result.Sections["profile"])
+	s.Require().NoError(errMarshal)
+	var profile struct {
+		Avatar map[string]string `json:"avatar"`
+	}
+	s.Require().NoError(json.Unmarshal(data, &profile))
+	s.Equal(map[string]string{
+		"small":  "https://cdn.example.com/avatars/jasuke/small.jpg",
+		"medium": "https://cdn.example.com/avatars/jasuke/medium.jpg",
+		"large":  "https://cdn.example.com/avatars/jasuke/large.jpg",
+	}, profile.Avatar)
+}
+
+func (s *ExportServiceTestSuite) TestRegisterGivenDuplicateSectionShouldPanic() {
+	s.Panics(func() {
+		s.sut.Register(service.NewProfileExportSection(s.blobStore))
+	})
+}
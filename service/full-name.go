@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	minFullNameLength = 3
+	maxFullNameLength = 60
+	// maxFullNameRunes bounds the stored size of a name whose characters carry many combining marks. It
+	// matches the width of users.full_name.
+	maxFullNameRunes = 240
+
+	zeroWidthNonJoiner = '\u200c'
+	zeroWidthJoiner    = '\u200d'
+)
+
+// normalizeFullName returns the NFC form of a full name with surrounding whitespace trimmed and inner
+// whitespace collapsed to single spaces. Its length is counted in user-perceived characters, so names in
+// any script get the same limit.
+func normalizeFullName(fullName string) (string, *common.CustomError) {
+	errDetails := []string{}
+
+	fullName = collapseWhitespace(norm.NFC.String(fullName))
+
+	if length := countGraphemes(fullName); length < minFullNameLength || length > maxFullNameLength {
+		errDetails = append(errDetails, fmt.Sprintf("full name must be between %d and %d characters", minFullNameLength, maxFullNameLength))
+	} else if len([]rune(fullName)) > maxFullNameRunes {
+		errDetails = append(errDetails, "full name has too many combining marks")
+	}
+
+	if hasDisallowedNameCharacter(fullName) {
+		errDetails = append(errDetails, "full name must not contain control, formatting or unassigned characters")
+	}
+
+	if len(errDetails) != 0 {
+		return "", common.NewCustomError(common.ErrInvalidInput, "invalid request params", errDetails...)
+	}
+	return fullName, nil
+}
+
+func collapseWhitespace(s string) string {
+	return strings.Join(strings.FieldsFunc(s, unicode.IsSpace), " ")
+}
+
+// hasDisallowedNameCharacter reports control characters, format characters such as bidi overrides,
+// private use, surrogate and unassigned code points. Zero-width (non-)joiners are format characters that
+// some scripts need inside words, so they are only allowed between two letters or marks.
+func hasDisallowedNameCharacter(s string) bool {
+	runes := []rune(s)
+	for i, r := range runes {
+		if r == zeroWidthJoiner || r == zeroWidthNonJoiner {
+			if i == 0 || i == len(runes)-1 || !isLetterOrMark(runes[i-1]) || !isLetterOrMark(runes[i+1]) {
+				return true
+			}
+			continue
+		}
+
+		if unicode.In(r, unicode.Cc, unicode.Cf, unicode.Co, unicode.Cs) || !unicode.In(r, unicode.L, unicode.M, unicode.N, unicode.P, unicode.S, unicode.Zs) {
+			return true
+		}
+	}
+	return false
+}
+
+func isLetterOrMark(r rune) bool {
+	return unicode.In(r, unicode.L, unicode.M)
+}
+
+// countGraphemes approximates the extended grapheme clusters of UAX #29: combining marks, variation
+// selectors, emoji modifiers and characters joined by a zero-width joiner extend the preceding
+// character, and regional indicators pair up into flags.
+func countGraphemes(s string) int {
+	count := 0
+	joined := false
+	regionalIndicators := 0
+
+	for _, r := range s {
+		isRegionalIndicator := r >= '\U0001F1E6' && r <= '\U0001F1FF'
+		extends := joined || r == zeroWidthJoiner || unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+			(r >= '\ufe00' && r <= '\ufe0f') || (r >= '\U0001F3FB' && r <= '\U0001F3FF') ||
+			(isRegionalIndicator && regionalIndicators%2 == 1)
+		if !extends {
+			count++
+		}
+
+		joined = r == zeroWidthJoiner
+		if isRegionalIndicator {
+			regionalIndicators++
+		} else {
+			regionalIndicators = 0
+		}
+	}
+	return count
+}
@@ -0,0 +1,20 @@
+package service_test
+
+import (
+	"context"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/golang/mock/gomock"
+)
+
+// newPassThroughTransactionManager returns a transaction manager that runs every function directly and
+// hands back its error, which is what the real manager does apart from the database transaction.
+func newPassThroughTransactionManager(ctrl *gomock.Controller) *repository.MockTransactionManager {
+	transactionManager := repository.NewMockTransactionManager(ctrl)
+	transactionManager.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) *common.CustomError) *common.CustomError {
+			return fn(ctx)
+		}).AnyTimes()
+	return transactionManager
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+)
+
+type IdempotencyServiceImpl struct {
+	idempotencyRepository repository.IdempotencyRepository
+	ttl                   time.Duration
+	lease                 time.Duration
+}
+
+// NewIdempotencyServiceImpl keeps responses for ttl. A request holds its key for lease, after which a retry
+// runs it again, in case the first attempt was lost without releasing the key.
+func NewIdempotencyServiceImpl(idempotencyRepository repository.IdempotencyRepository, ttl, lease time.Duration) *IdempotencyServiceImpl {
+	return &IdempotencyServiceImpl{
+		idempotencyRepository: idempotencyRepository,
+		ttl:                   ttl,
+		lease:                 lease,
+	}
+}
+
+// Begin claims the key of record for the request. It returns the stored record when the key has already
+// been used for the same request and its response can be replayed, and nil when the request should run.
+func (s *IdempotencyServiceImpl) Begin(ctx context.Context, record model.IdempotencyRecord) (*model.IdempotencyRecord, *common.CustomError) {
+	now := time.Now()
+	record.ExpiresAt = now.Add(s.lease)
+
+	existing, err := s.idempotencyRepository.Claim(ctx, record, now)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(existing.Fingerprint, record.Fingerprint) {
+		return nil, common.NewCustomError(common.ErrIdempotencyKeyReused, "Idempotency-Key has already been used for a different request")
+	}
+	if existing.StatusCode == 0 {
+		return nil, common.NewCustomError(common.ErrIdempotencyKeyInUse, "a request with this Idempotency-Key is still being handled, please retry later")
+	}
+	return existing, nil
+}
+
+// Complete stores the response to the request that claimed the key.
+func (s *IdempotencyServiceImpl) Complete(ctx context.Context, record model.IdempotencyRecord) *common.CustomError {
+	record.ExpiresAt = time.Now().Add(s.ttl)
+	return s.idempotencyRepository.Complete(ctx, record)
+}
+
+// Abandon releases the key of a request whose response is not stored, so that a retry runs it again.
+func (s *IdempotencyServiceImpl) Abandon(ctx context.Context, record model.IdempotencyRecord) *common.CustomError {
+	return s.idempotencyRepository.Release(ctx, record)
+}
+
+// DeleteExpired deletes up to limit expired records and returns how many it deleted.
+func (s *IdempotencyServiceImpl) DeleteExpired(ctx context.Context, limit int) (int, *common.CustomError) {
+	return s.idempotencyRepository.DeleteExpired(ctx, time.Now(), limit)
+}
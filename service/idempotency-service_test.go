@@ -0,0 +1,121 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/SawitProRecruitment/UserService/service"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+)
+
+type IdempotencyServiceTestSuite struct {
+	suite.Suite
+	ctrl                  *gomock.Controller
+	idempotencyRepository *repository.MockIdempotencyRepository
+	sut                   *service.IdempotencyServiceImpl
+}
+
+func (s *IdempotencyServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.idempotencyRepository = repository.NewMockIdempotencyRepository(s.ctrl)
+	s.sut = service.NewIdempotencyServiceImpl(s.idempotencyRepository, 24*time.Hour, time.Minute)
+}
+
+func (s *IdempotencyServiceTestSuite) AfterTest(suiteName, testName string) {
+	s.ctrl.Finish()
+}
+
+func TestIdempotencyServiceImpl(t *testing.T) {
+	suite.Run(t, new(IdempotencyServiceTestSuite))
+}
+
+func (s *IdempotencyServiceTestSuite) newRecord() model.IdempotencyRecord {
+	return model.IdempotencyRecord{
+		Principal:   "anonymous",
+		Key:         "f0e1d2c3",
+		Fingerprint: []byte("register jasuke"),
+	}
+}
+
+func (s *IdempotencyServiceTestSuite) TestBeginGivenNewKeyShouldClaimItForLease() {
+	ctx := context.Background()
+	record := s.newRecord()
+
+	s.idempotencyRepository.EXPECT().Claim(gomock.Eq(ctx), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, claimed model.IdempotencyRecord, now time.Time) (*model.IdempotencyRecord, *common.CustomError) {
+			s.Equal(record.Fingerprint, claimed.Fingerprint)
+			s.Equal(now.Add(time.Minute), claimed.ExpiresAt)
+			return nil, nil
+		})
+
+	stored, err := s.sut.Begin(ctx, record)
+
+	s.Nil(err)
+	s.Nil(stored)
+}
+
+func (s *IdempotencyServiceTestSuite) TestBeginGivenCompletedSameRequestShouldReturnStoredResponse() {
+	ctx := context.Background()
+	record := s.newRecord()
+	completed := record
+	completed.StatusCode = 201
+	completed.Body = []byte(`{"user_id":"a169451c-8525-4352-b8ca-070dd449a1a5"}`)
+
+	s.idempotencyRepository.EXPECT().Claim(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(&completed, nil)
+
+	stored, err := s.sut.Begin(ctx, record)
+
+	s.Nil(err)
+	s.Equal(&completed, stored)
+}
+
+func (s *IdempotencyServiceTestSuite) TestBeginGivenKeyUsedForDifferentRequestShouldFail() {
+	ctx := context.Background()
+	record := s.newRecord()
+	other := record
+	other.Fingerprint = []byte("register someone else")
+	other.StatusCode = 201
+
+	s.idempotencyRepository.EXPECT().Claim(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(&other, nil)
+
+	stored, err := s.sut.Begin(ctx, record)
+
+	s.Nil(stored)
+	s.Require().NotNil(err)
+	s.Equal(common.ErrIdempotencyKeyReused, err.ErrType)
+}
+
+func (s *IdempotencyServiceTestSuite) TestBeginGivenSameRequestInProgressShouldFail() {
+	ctx := context.Background()
+	record := s.newRecord()
+	inProgress := record
+
+	s.idempotencyRepository.EXPECT().Claim(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(&inProgress, nil)
+
+	stored, err := s.sut.Begin(ctx, record)
+
+	s.Nil(stored)
+	s.Require().NotNil(err)
+	s.Equal(common.ErrIdempotencyKeyInUse, err.ErrType)
+}
+
+func (s *IdempotencyServiceTestSuite) TestCompleteShouldKeepResponseForTTL() {
+	ctx := context.Background()
+	record := s.newRecord()
+	record.StatusCode = 201
+	before := time.Now()
+
+	s.idempotencyRepository.EXPECT().Complete(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, completed model.IdempotencyRecord) *common.CustomError {
+			s.Equal(201, completed.StatusCode)
+			s.WithinRange(completed.ExpiresAt, before.Add(24*time.Hour), time.Now().Add(24*time.Hour))
+			return nil
+		})
+
+	s.Nil(s.sut.Complete(ctx, record))
+}
@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 
 	"github.com/SawitProRecruitment/UserService/common"
 	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/SawitProRecruitment/UserService/model"
 	"github.com/google/uuid"
 )
 
@@ -15,10 +18,52 @@ type AuthService interface {
 
 type ProfileService interface {
 	GetProfile(ctx context.Context) (generated.GetProfileResponse, *common.CustomError)
-	UpdateProfile(ctx context.Context, params generated.UpdateProfileRequest) *common.CustomError
+	// UpdateProfile and PatchProfile only apply while the profile still has expectedVersion, zero makes
+	// them unconditional.
+	UpdateProfile(ctx context.Context, params generated.UpdateProfileRequest, expectedVersion int64) *common.CustomError
+	PatchProfile(ctx context.Context, document map[string]json.RawMessage, expectedVersion int64) (generated.GetProfileResponse, *common.CustomError)
+	UploadAvatar(ctx context.Context, content io.Reader) (generated.GetProfileResponse, *common.CustomError)
+}
+
+type AccountService interface {
+	ChangeStatus(ctx context.Context, userID uuid.UUID, params generated.UpdateUserStatusRequest) *common.CustomError
+	DeleteAccount(ctx context.Context, params generated.DeleteAccountRequest) (generated.DeleteAccountResponse, *common.CustomError)
+	RestoreAccount(ctx context.Context, params generated.RestoreAccountRequest) *common.CustomError
+	AnonymizeDeletedAccounts(ctx context.Context, limit int) (int, *common.CustomError)
+}
+
+type ExportService interface {
+	Export(ctx context.Context) (generated.PersonalDataExport, *common.CustomError)
+}
+
+type AuditService interface {
+	ListEvents(ctx context.Context, params generated.GetApiV1AdminAuditEventsParams) (generated.AuditEventList, *common.CustomError)
+	VerifyChain(ctx context.Context) (generated.AuditChainVerification, *common.CustomError)
+}
+
+type WebhookService interface {
+	ListWebhooks(ctx context.Context) (generated.WebhookSubscriptionList, *common.CustomError)
+	CreateWebhook(ctx context.Context, params generated.CreateWebhookSubscriptionRequest) (generated.WebhookSubscription, *common.CustomError)
+	GetWebhook(ctx context.Context, webhookID uuid.UUID) (generated.WebhookSubscription, *common.CustomError)
+	UpdateWebhook(ctx context.Context, webhookID uuid.UUID, params generated.UpdateWebhookSubscriptionRequest) (generated.WebhookSubscription, *common.CustomError)
+	DeleteWebhook(ctx context.Context, webhookID uuid.UUID) *common.CustomError
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, params generated.GetApiV1AdminWebhooksWebhookIdDeliveriesParams) (generated.WebhookDeliveryList, *common.CustomError)
+	Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (generated.WebhookDelivery, *common.CustomError)
+}
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, record model.IdempotencyRecord) (*model.IdempotencyRecord, *common.CustomError)
+	Complete(ctx context.Context, record model.IdempotencyRecord) *common.CustomError
+	Abandon(ctx context.Context, record model.IdempotencyRecord) *common.CustomError
+	DeleteExpired(ctx context.Context, limit int) (int, *common.CustomError)
+}
+
+// LoginRecorder records successful logins without delaying the login response.
+type LoginRecorder interface {
+	Record(ctx context.Context, loginLog model.LoginLog)
 }
 
 type TokenManager interface {
 	GenerateToken(userID uuid.UUID) (string, *common.CustomError)
-	ValidateToken(accessToken string) (uuid.UUID, *common.CustomError)
+	ValidateToken(accessToken string) (TokenClaims, *common.CustomError)
 }
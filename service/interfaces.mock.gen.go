@@ -6,10 +6,13 @@ package service
 
 import (
 	context "context"
+	json "encoding/json"
+	io "io"
 	reflect "reflect"
 
 	common "github.com/SawitProRecruitment/UserService/common"
 	generated "github.com/SawitProRecruitment/UserService/generated"
+	model "github.com/SawitProRecruitment/UserService/model"
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
 )
@@ -105,18 +108,463 @@ func (mr *MockProfileServiceMockRecorder) GetProfile(ctx interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockProfileService)(nil).GetProfile), ctx)
 }
 
+// PatchProfile mocks base method.
+func (m *MockProfileService) PatchProfile(ctx context.Context, document map[string]json.RawMessage, expectedVersion int64) (generated.GetProfileResponse, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchProfile", ctx, document, expectedVersion)
+	ret0, _ := ret[0].(generated.GetProfileResponse)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// PatchProfile indicates an expected call of PatchProfile.
+func (mr *MockProfileServiceMockRecorder) PatchProfile(ctx, document, expectedVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchProfile", reflect.TypeOf((*MockProfileService)(nil).PatchProfile), ctx, document, expectedVersion)
+}
+
 // UpdateProfile mocks base method.
-func (m *MockProfileService) UpdateProfile(ctx context.Context, params generated.UpdateProfileRequest) *common.CustomError {
+func (m *MockProfileService) UpdateProfile(ctx context.Context, params generated.UpdateProfileRequest, expectedVersion int64) *common.CustomError {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateProfile", ctx, params)
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, params, expectedVersion)
 	ret0, _ := ret[0].(*common.CustomError)
 	return ret0
 }
 
 // UpdateProfile indicates an expected call of UpdateProfile.
-func (mr *MockProfileServiceMockRecorder) UpdateProfile(ctx, params interface{}) *gomock.Call {
+func (mr *MockProfileServiceMockRecorder) UpdateProfile(ctx, params, expectedVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockProfileService)(nil).UpdateProfile), ctx, params, expectedVersion)
+}
+
+// UploadAvatar mocks base method.
+func (m *MockProfileService) UploadAvatar(ctx context.Context, content io.Reader) (generated.GetProfileResponse, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadAvatar", ctx, content)
+	ret0, _ := ret[0].(generated.GetProfileResponse)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// UploadAvatar indicates an expected call of UploadAvatar.
+func (mr *MockProfileServiceMockRecorder) UploadAvatar(ctx, content interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadAvatar", reflect.TypeOf((*MockProfileService)(nil).UploadAvatar), ctx, content)
+}
+
+// MockAccountService is a mock of AccountService interface.
+type MockAccountService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountServiceMockRecorder
+}
+
+// MockAccountServiceMockRecorder is the mock recorder for MockAccountService.
+type MockAccountServiceMockRecorder struct {
+	mock *MockAccountService
+}
+
+// NewMockAccountService creates a new mock instance.
+func NewMockAccountService(ctrl *gomock.Controller) *MockAccountService {
+	mock := &MockAccountService{ctrl: ctrl}
+	mock.recorder = &MockAccountServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountService) EXPECT() *MockAccountServiceMockRecorder {
+	return m.recorder
+}
+
+// AnonymizeDeletedAccounts mocks base method.
+func (m *MockAccountService) AnonymizeDeletedAccounts(ctx context.Context, limit int) (int, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeDeletedAccounts", ctx, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// AnonymizeDeletedAccounts indicates an expected call of AnonymizeDeletedAccounts.
+func (mr *MockAccountServiceMockRecorder) AnonymizeDeletedAccounts(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeDeletedAccounts", reflect.TypeOf((*MockAccountService)(nil).AnonymizeDeletedAccounts), ctx, limit)
+}
+
+// ChangeStatus mocks base method.
+func (m *MockAccountService) ChangeStatus(ctx context.Context, userID uuid.UUID, params generated.UpdateUserStatusRequest) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeStatus", ctx, userID, params)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// ChangeStatus indicates an expected call of ChangeStatus.
+func (mr *MockAccountServiceMockRecorder) ChangeStatus(ctx, userID, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeStatus", reflect.TypeOf((*MockAccountService)(nil).ChangeStatus), ctx, userID, params)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockAccountService) DeleteAccount(ctx context.Context, params generated.DeleteAccountRequest) (generated.DeleteAccountResponse, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", ctx, params)
+	ret0, _ := ret[0].(generated.DeleteAccountResponse)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockAccountServiceMockRecorder) DeleteAccount(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockAccountService)(nil).DeleteAccount), ctx, params)
+}
+
+// RestoreAccount mocks base method.
+func (m *MockAccountService) RestoreAccount(ctx context.Context, params generated.RestoreAccountRequest) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreAccount", ctx, params)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// RestoreAccount indicates an expected call of RestoreAccount.
+func (mr *MockAccountServiceMockRecorder) RestoreAccount(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreAccount", reflect.TypeOf((*MockAccountService)(nil).RestoreAccount), ctx, params)
+}
+
+// MockExportService is a mock of ExportService interface.
+type MockExportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportServiceMockRecorder
+}
+
+// MockExportServiceMockRecorder is the mock recorder for MockExportService.
+type MockExportServiceMockRecorder struct {
+	mock *MockExportService
+}
+
+// NewMockExportService creates a new mock instance.
+func NewMockExportService(ctrl *gomock.Controller) *MockExportService {
+	mock := &MockExportService{ctrl: ctrl}
+	mock.recorder = &MockExportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportService) EXPECT() *MockExportServiceMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockExportService) Export(ctx context.Context) (generated.PersonalDataExport, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx)
+	ret0, _ := ret[0].(generated.PersonalDataExport)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockExportServiceMockRecorder) Export(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockExportService)(nil).Export), ctx)
+}
+
+// MockAuditService is a mock of AuditService interface.
+type MockAuditService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditServiceMockRecorder
+}
+
+// MockAuditServiceMockRecorder is the mock recorder for MockAuditService.
+type MockAuditServiceMockRecorder struct {
+	mock *MockAuditService
+}
+
+// NewMockAuditService creates a new mock instance.
+func NewMockAuditService(ctrl *gomock.Controller) *MockAuditService {
+	mock := &MockAuditService{ctrl: ctrl}
+	mock.recorder = &MockAuditServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditService) EXPECT() *MockAuditServiceMockRecorder {
+	return m.recorder
+}
+
+// ListEvents mocks base method.
+func (m *MockAuditService) ListEvents(ctx context.Context, params generated.GetApiV1AdminAuditEventsParams) (generated.AuditEventList, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", ctx, params)
+	ret0, _ := ret[0].(generated.AuditEventList)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockAuditServiceMockRecorder) ListEvents(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockAuditService)(nil).ListEvents), ctx, params)
+}
+
+// VerifyChain mocks base method.
+func (m *MockAuditService) VerifyChain(ctx context.Context) (generated.AuditChainVerification, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyChain", ctx)
+	ret0, _ := ret[0].(generated.AuditChainVerification)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// VerifyChain indicates an expected call of VerifyChain.
+func (mr *MockAuditServiceMockRecorder) VerifyChain(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyChain", reflect.TypeOf((*MockAuditService)(nil).VerifyChain), ctx)
+}
+
+// MockWebhookService is a mock of WebhookService interface.
+type MockWebhookService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookServiceMockRecorder
+}
+
+// MockWebhookServiceMockRecorder is the mock recorder for MockWebhookService.
+type MockWebhookServiceMockRecorder struct {
+	mock *MockWebhookService
+}
+
+// NewMockWebhookService creates a new mock instance.
+func NewMockWebhookService(ctrl *gomock.Controller) *MockWebhookService {
+	mock := &MockWebhookService{ctrl: ctrl}
+	mock.recorder = &MockWebhookServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookService) EXPECT() *MockWebhookServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhookService) CreateWebhook(ctx context.Context, params generated.CreateWebhookSubscriptionRequest) (generated.WebhookSubscription, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, params)
+	ret0, _ := ret[0].(generated.WebhookSubscription)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookServiceMockRecorder) CreateWebhook(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookService)(nil).CreateWebhook), ctx, params)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookService) DeleteWebhook(ctx context.Context, webhookID uuid.UUID) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, webhookID)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookServiceMockRecorder) DeleteWebhook(ctx, webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookService)(nil).DeleteWebhook), ctx, webhookID)
+}
+
+// GetWebhook mocks base method.
+func (m *MockWebhookService) GetWebhook(ctx context.Context, webhookID uuid.UUID) (generated.WebhookSubscription, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, webhookID)
+	ret0, _ := ret[0].(generated.WebhookSubscription)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockWebhookServiceMockRecorder) GetWebhook(ctx, webhookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockWebhookService)(nil).GetWebhook), ctx, webhookID)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockWebhookService) ListDeliveries(ctx context.Context, webhookID uuid.UUID, params generated.GetApiV1AdminWebhooksWebhookIdDeliveriesParams) (generated.WebhookDeliveryList, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, webhookID, params)
+	ret0, _ := ret[0].(generated.WebhookDeliveryList)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockWebhookServiceMockRecorder) ListDeliveries(ctx, webhookID, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockWebhookService)(nil).ListDeliveries), ctx, webhookID, params)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockWebhookService) ListWebhooks(ctx context.Context) (generated.WebhookSubscriptionList, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].(generated.WebhookSubscriptionList)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockWebhookServiceMockRecorder) ListWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockWebhookService)(nil).ListWebhooks), ctx)
+}
+
+// Redeliver mocks base method.
+func (m *MockWebhookService) Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (generated.WebhookDelivery, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, webhookID, deliveryID)
+	ret0, _ := ret[0].(generated.WebhookDelivery)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockWebhookServiceMockRecorder) Redeliver(ctx, webhookID, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockWebhookService)(nil).Redeliver), ctx, webhookID, deliveryID)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockWebhookService) UpdateWebhook(ctx context.Context, webhookID uuid.UUID, params generated.UpdateWebhookSubscriptionRequest) (generated.WebhookSubscription, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, webhookID, params)
+	ret0, _ := ret[0].(generated.WebhookSubscription)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockWebhookServiceMockRecorder) UpdateWebhook(ctx, webhookID, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockWebhookService)(nil).UpdateWebhook), ctx, webhookID, params)
+}
+
+// MockIdempotencyService is a mock of IdempotencyService interface.
+type MockIdempotencyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyServiceMockRecorder
+}
+
+// MockIdempotencyServiceMockRecorder is the mock recorder for MockIdempotencyService.
+type MockIdempotencyServiceMockRecorder struct {
+	mock *MockIdempotencyService
+}
+
+// NewMockIdempotencyService creates a new mock instance.
+func NewMockIdempotencyService(ctrl *gomock.Controller) *MockIdempotencyService {
+	mock := &MockIdempotencyService{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyService) EXPECT() *MockIdempotencyServiceMockRecorder {
+	return m.recorder
+}
+
+// Abandon mocks base method.
+func (m *MockIdempotencyService) Abandon(ctx context.Context, record model.IdempotencyRecord) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Abandon", ctx, record)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// Abandon indicates an expected call of Abandon.
+func (mr *MockIdempotencyServiceMockRecorder) Abandon(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Abandon", reflect.TypeOf((*MockIdempotencyService)(nil).Abandon), ctx, record)
+}
+
+// Begin mocks base method.
+func (m *MockIdempotencyService) Begin(ctx context.Context, record model.IdempotencyRecord) (*model.IdempotencyRecord, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx, record)
+	ret0, _ := ret[0].(*model.IdempotencyRecord)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockIdempotencyServiceMockRecorder) Begin(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockIdempotencyService)(nil).Begin), ctx, record)
+}
+
+// Complete mocks base method.
+func (m *MockIdempotencyService) Complete(ctx context.Context, record model.IdempotencyRecord) *common.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, record)
+	ret0, _ := ret[0].(*common.CustomError)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyServiceMockRecorder) Complete(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotencyService)(nil).Complete), ctx, record)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockIdempotencyService) DeleteExpired(ctx context.Context, limit int) (int, *common.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(*common.CustomError)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockIdempotencyServiceMockRecorder) DeleteExpired(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockIdempotencyService)(nil).DeleteExpired), ctx, limit)
+}
+
+// MockLoginRecorder is a mock of LoginRecorder interface.
+type MockLoginRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginRecorderMockRecorder
+}
+
+// MockLoginRecorderMockRecorder is the mock recorder for MockLoginRecorder.
+type MockLoginRecorderMockRecorder struct {
+	mock *MockLoginRecorder
+}
+
+// NewMockLoginRecorder creates a new mock instance.
+func NewMockLoginRecorder(ctrl *gomock.Controller) *MockLoginRecorder {
+	mock := &MockLoginRecorder{ctrl: ctrl}
+	mock.recorder = &MockLoginRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginRecorder) EXPECT() *MockLoginRecorderMockRecorder {
+	return m.recorder
+}
+
+// Record mocks base method.
+func (m *MockLoginRecorder) Record(ctx context.Context, loginLog model.LoginLog) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Record", ctx, loginLog)
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockLoginRecorderMockRecorder) Record(ctx, loginLog interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockProfileService)(nil).UpdateProfile), ctx, params)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockLoginRecorder)(nil).Record), ctx, loginLog)
 }
 
 // MockTokenManager is a mock of TokenManager interface.
@@ -158,10 +606,10 @@ func (mr *MockTokenManagerMockRecorder) GenerateToken(userID interface{}) *gomoc
 }
 
 // ValidateToken mocks base method.
-func (m *MockTokenManager) ValidateToken(accessToken string) (uuid.UUID, *common.CustomError) {
+func (m *MockTokenManager) ValidateToken(accessToken string) (TokenClaims, *common.CustomError) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ValidateToken", accessToken)
-	ret0, _ := ret[0].(uuid.UUID)
+	ret0, _ := ret[0].(TokenClaims)
 	ret1, _ := ret[1].(*common.CustomError)
 	return ret0, ret1
 }
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"github.com/SawitProRecruitment/UserService/metrics"
+)
+
+var (
+	loginsTotal = metrics.Default.NewCounter("auth_logins_total",
+		"Login attempts by result and, for failures, the reason.", "result", "reason")
+	registrationsTotal = metrics.Default.NewCounter("auth_registrations_total",
+		"Registration attempts by result.", "result")
+	tokenValidationFailuresTotal = metrics.Default.NewCounter("auth_token_validation_failures_total",
+		"Access tokens rejected by reason.", "reason")
+	passwordHashDuration = metrics.Default.NewHistogram("auth_password_hash_duration_seconds",
+		"Time spent hashing and comparing passwords with bcrypt.",
+		[]float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5}, "operation")
+)
+
+// Reasons of failed logins. Reasons of inactive accounts are "account_" followed by the user status.
+const (
+	loginReasonInvalidPhoneNumber = "invalid_phone_number"
+	loginReasonUnknownUser        = "unknown_user"
+	loginReasonWrongPassword      = "wrong_password"
+	loginReasonError              = "error"
+)
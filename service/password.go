@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/tracing"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// hashPassword hashes password with bcrypt at cost and records how long it took.
+func hashPassword(ctx context.Context, password string, cost int) ([]byte, error) {
+	_, span := tracing.Start(ctx, "bcrypt.GenerateFromPassword")
+	start := time.Now()
+	defer func() {
+		passwordHashDuration.Observe(time.Since(start).Seconds(), "hash")
+		span.End()
+	}()
+	span.SetAttribute("bcrypt.cost", cost)
+
+	return bcrypt.GenerateFromPassword([]byte(password), cost)
+}
+
+// comparePassword checks password against a bcrypt hash and records how long it took. A mismatch is an
+// expected outcome, not a failure of the span.
+func comparePassword(ctx context.Context, hash, password string) error {
+	_, span := tracing.Start(ctx, "bcrypt.CompareHashAndPassword")
+	start := time.Now()
+	defer func() {
+		passwordHashDuration.Observe(time.Since(start).Seconds(), "compare")
+		span.End()
+	}()
+
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+}
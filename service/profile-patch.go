@@ -0,0 +1,154 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/google/uuid"
+)
+
+// profilePatchField describes a property that can appear in a profile merge patch.
+type profilePatchField struct {
+	field model.UserField
+	// nullable fields can be cleared by patching them to null.
+	nullable bool
+	// normalize validates the trimmed value and returns the form it is stored in.
+	normalize func(value string) (string, *common.CustomError)
+	current   func(user *model.User) string
+}
+
+var profilePatchFields = map[string]profilePatchField{
+	"full_name": {
+		field:     model.UserFieldFullName,
+		normalize: normalizeFullName,
+		current:   func(user *model.User) string { return user.FullName },
+	},
+	"phone_number": {
+		field:     model.UserFieldPhoneNumber,
+		normalize: normalizePhoneNumber,
+		current:   func(user *model.User) string { return user.PhoneNumber },
+	},
+	"email": {
+		field:     model.UserFieldEmail,
+		nullable:  true,
+		normalize: normalizeEmail,
+		current:   func(user *model.User) string { return stringValue(user.Email) },
+	},
+	"date_of_birth": {
+		field:    model.UserFieldDateOfBirth,
+		nullable: true,
+		normalize: func(value string) (string, *common.CustomError) {
+			date, err := parseDateOfBirth(value, time.Now())
+			if err != nil {
+				return "", err
+			}
+			return date.Format(dateOfBirthLayout), nil
+		},
+		current: func(user *model.User) string {
+			if user.DateOfBirth == nil {
+				return ""
+			}
+			return user.DateOfBirth.Format(dateOfBirthLayout)
+		},
+	},
+	"locale": {
+		field:     model.UserFieldLocale,
+		nullable:  true,
+		normalize: normalizeLocale,
+		current:   func(user *model.User) string { return stringValue(user.Locale) },
+	},
+	"timezone": {
+		field:     model.UserFieldTimezone,
+		nullable:  true,
+		normalize: validated(validateTimezone),
+		current:   func(user *model.User) string { return stringValue(user.Timezone) },
+	},
+}
+
+// parseProfilePatch turns a JSON Merge Patch document into a user patch. Absent properties are left out of
+// the patch, null properties clear the field and only the supplied properties are validated.
+func parseProfilePatch(userID uuid.UUID, document map[string]json.RawMessage) (model.UserPatch, *common.CustomError) {
+	patch := model.NewUserPatch(userID)
+	errDetails := []string{}
+
+	names := make([]string, 0, len(document))
+	for name := range document {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		spec, ok := profilePatchFields[name]
+		if !ok {
+			errDetails = append(errDetails, fmt.Sprintf("%s cannot be patched", name))
+			continue
+		}
+
+		raw := document[name]
+		if string(raw) == "null" {
+			if !spec.nullable {
+				errDetails = append(errDetails, fmt.Sprintf("%s cannot be null", name))
+				continue
+			}
+			patch.Set(spec.field, nil)
+			continue
+		}
+
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			errDetails = append(errDetails, fmt.Sprintf("%s must be a string", name))
+			continue
+		}
+
+		value, err := spec.normalize(strings.TrimSpace(value))
+		if err != nil {
+			errDetails = append(errDetails, err.Details...)
+			continue
+		}
+		patch.Set(spec.field, value)
+	}
+
+	if len(errDetails) != 0 {
+		return model.UserPatch{}, common.NewCustomError(common.ErrInvalidInput, "invalid request params", errDetails...)
+	}
+	return patch, nil
+}
+
+// profilePatchChanges lists the audit changes a patch makes to user.
+func profilePatchChanges(user *model.User, patch model.UserPatch) map[string]model.AuditChange {
+	changes := map[string]model.AuditChange{}
+	for name, spec := range profilePatchFields {
+		value, ok := patch.Fields[spec.field]
+		if !ok {
+			continue
+		}
+
+		after, _ := value.(string)
+		if before := spec.current(user); before != after {
+			changes[name] = fieldChange(before, after)
+		}
+	}
+	return changes
+}
+
+// validated adapts a validator of values that are stored as given.
+func validated(validate func(value string) *common.CustomError) func(value string) (string, *common.CustomError) {
+	return func(value string) (string, *common.CustomError) {
+		if err := validate(value); err != nil {
+			return "", err
+		}
+		return value, nil
+	}
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -2,98 +2,237 @@ package service
 
 import (
 	"context"
-	"strings"
+	"encoding/json"
+	"io"
 
 	"github.com/SawitProRecruitment/UserService/common"
 	"github.com/SawitProRecruitment/UserService/generated"
 	"github.com/SawitProRecruitment/UserService/model"
 	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/SawitProRecruitment/UserService/storage"
+	"github.com/SawitProRecruitment/UserService/tracing"
+	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
 )
 
 type ProfileServiceImpl struct {
-	userRepository repository.UserRepository
-	tokenManager   TokenManager
+	userRepository       repository.UserRepository
+	auditEventRepository repository.AuditEventRepository
+	transactionManager   repository.TransactionManager
+	tokenManager         TokenManager
+	blobStore            storage.BlobStore
 }
 
-func NewProfileServiceImpl(userRepository repository.UserRepository, tokenManager TokenManager) *ProfileServiceImpl {
+func NewProfileServiceImpl(userRepository repository.UserRepository, auditEventRepository repository.AuditEventRepository, transactionManager repository.TransactionManager, tokenManager TokenManager, blobStore storage.BlobStore) *ProfileServiceImpl {
 	return &ProfileServiceImpl{
-		userRepository: userRepository,
-		tokenManager:   tokenManager,
+		userRepository:       userRepository,
+		auditEventRepository: auditEventRepository,
+		transactionManager:   transactionManager,
+		tokenManager:         tokenManager,
+		blobStore:            blobStore,
 	}
 }
 
-func (s *ProfileServiceImpl) GetProfile(ctx context.Context) (generated.GetProfileResponse, *common.CustomError) {
-	accessToken, ok := ctx.Value(common.KeyAccessToken).(string)
-	if !ok {
-		return generated.GetProfileResponse{}, common.NewCustomError(common.ErrUnauthorized, "invalid access token")
-	}
+func (s *ProfileServiceImpl) GetProfile(ctx context.Context) (response generated.GetProfileResponse, err *common.CustomError) {
+	ctx, span := tracing.Start(ctx, "ProfileService.GetProfile")
+	defer func() { endSpan(span, err) }()
 
-	userID, err := s.tokenManager.ValidateToken(accessToken)
-	if err != nil {
-		return generated.GetProfileResponse{}, common.NewCustomError(common.ErrUnauthorized, err.Message)
-	}
-
-	user, err := s.userRepository.GetByUserID(ctx, userID)
+	user, err := authenticateReader(ctx, s.tokenManager, s.userRepository)
 	if err != nil {
 		return generated.GetProfileResponse{}, err
 	}
 
-	return generated.GetProfileResponse{
-		FullName:    user.FullName,
-		PhoneNumber: user.PhoneNumber,
-	}, nil
+	return s.toProfileResponse(user), nil
 }
 
-func (s *ProfileServiceImpl) UpdateProfile(ctx context.Context, params generated.UpdateProfileRequest) *common.CustomError {
-	accessToken, ok := ctx.Value(common.KeyAccessToken).(string)
-	if !ok {
-		return common.NewCustomError(common.ErrUnauthorized, "invalid access token")
-	}
+func (s *ProfileServiceImpl) UpdateProfile(ctx context.Context, params generated.UpdateProfileRequest, expectedVersion int64) (err *common.CustomError) {
+	ctx, span := tracing.Start(ctx, "ProfileService.UpdateProfile")
+	defer func() { endSpan(span, err) }()
 
-	userID, err := s.tokenManager.ValidateToken(accessToken)
+	currentUser, err := authenticateUser(ctx, s.tokenManager, s.userRepository)
 	if err != nil {
 		return err
 	}
 
-	if err := s.validateUpdateProfileRequest(params); err != nil {
+	params, err = s.validateUpdateProfileRequest(params)
+	if err != nil {
 		return err
 	}
 
+	// The version check happens in the UPDATE itself so that a concurrent update between the read above
+	// and the write below is still detected.
 	user := model.User{
-		ID:          userID,
+		ID:          currentUser.ID,
 		FullName:    params.FullName,
 		PhoneNumber: params.PhoneNumber,
+		Version:     expectedVersion,
 	}
 
-	if err := s.userRepository.Update(ctx, user); err != nil {
-		return err
+	changes := map[string]model.AuditChange{}
+	if user.FullName != "" && user.FullName != currentUser.FullName {
+		changes["full_name"] = fieldChange(currentUser.FullName, user.FullName)
+	}
+	if user.PhoneNumber != "" && user.PhoneNumber != currentUser.PhoneNumber {
+		changes["phone_number"] = fieldChange(currentUser.PhoneNumber, user.PhoneNumber)
+	}
+
+	event := newAuditEvent(ctx, model.AuditActorUser, &currentUser.ID, currentUser.ID, model.AuditActionProfileUpdated, changes)
+
+	return s.transactionManager.WithinTransaction(ctx, func(ctx context.Context) *common.CustomError {
+		if err := s.userRepository.Update(ctx, user); err != nil {
+			return err
+		}
+
+		if _, err := s.auditEventRepository.Append(ctx, event); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// PatchProfile applies a JSON Merge Patch document to the profile of the authenticated user and returns
+// the profile as stored afterwards.
+func (s *ProfileServiceImpl) PatchProfile(ctx context.Context, document map[string]json.RawMessage, expectedVersion int64) (response generated.GetProfileResponse, err *common.CustomError) {
+	ctx, span := tracing.Start(ctx, "ProfileService.PatchProfile")
+	defer func() { endSpan(span, err) }()
+
+	currentUser, err := authenticateUser(ctx, s.tokenManager, s.userRepository)
+	if err != nil {
+		return generated.GetProfileResponse{}, err
+	}
+
+	patch, err := parseProfilePatch(currentUser.ID, document)
+	if err != nil {
+		return generated.GetProfileResponse{}, err
+	}
+
+	if len(patch.Fields) == 0 && expectedVersion != 0 && expectedVersion != currentUser.Version {
+		return generated.GetProfileResponse{}, common.NewCustomError(common.ErrPreconditionFailed, "profile has been changed by another request")
+	}
+	patch.Version = expectedVersion
+
+	if len(patch.Fields) == 0 {
+		return s.toProfileResponse(currentUser), nil
+	}
+
+	event := newAuditEvent(ctx, model.AuditActorUser, &currentUser.ID, currentUser.ID, model.AuditActionProfileUpdated, profilePatchChanges(currentUser, patch))
+
+	err = s.transactionManager.WithinTransaction(ctx, func(ctx context.Context) *common.CustomError {
+		if err := s.userRepository.Patch(ctx, patch); err != nil {
+			return err
+		}
+
+		if _, err := s.auditEventRepository.Append(ctx, event); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return generated.GetProfileResponse{}, err
 	}
-	return nil
+
+	user, err := s.userRepository.GetByUserID(ctx, currentUser.ID)
+	if err != nil {
+		return generated.GetProfileResponse{}, err
+	}
+	return s.toProfileResponse(user), nil
 }
 
-func (s *ProfileServiceImpl) validateUpdateProfileRequest(params generated.UpdateProfileRequest) *common.CustomError {
+// UploadAvatar replaces the avatar of the authenticated user with thumbnails of the uploaded image.
+func (s *ProfileServiceImpl) UploadAvatar(ctx context.Context, content io.Reader) (response generated.GetProfileResponse, err *common.CustomError) {
+	ctx, span := tracing.Start(ctx, "ProfileService.UploadAvatar")
+	defer func() { endSpan(span, err) }()
+
+	currentUser, err := authenticateUser(ctx, s.tokenManager, s.userRepository)
+	if err != nil {
+		return generated.GetProfileResponse{}, err
+	}
+
+	key, err := storeAvatar(ctx, s.blobStore, currentUser.ID, content)
+	if err != nil {
+		return generated.GetProfileResponse{}, err
+	}
+
+	patch := model.NewUserPatch(currentUser.ID)
+	patch.Set(model.UserFieldAvatarKey, key)
+
+	changes := map[string]model.AuditChange{
+		"avatar": fieldChange(stringValue(currentUser.AvatarKey), key),
+	}
+	event := newAuditEvent(ctx, model.AuditActorUser, &currentUser.ID, currentUser.ID, model.AuditActionProfileUpdated, changes)
+
+	err = s.transactionManager.WithinTransaction(ctx, func(ctx context.Context) *common.CustomError {
+		if err := s.userRepository.Patch(ctx, patch); err != nil {
+			return err
+		}
+
+		if _, err := s.auditEventRepository.Append(ctx, event); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		deleteAvatar(ctx, s.blobStore, key)
+		return generated.GetProfileResponse{}, err
+	}
+
+	if currentUser.AvatarKey != nil {
+		deleteAvatar(ctx, s.blobStore, *currentUser.AvatarKey)
+	}
+
+	user, err := s.userRepository.GetByUserID(ctx, currentUser.ID)
+	if err != nil {
+		return generated.GetProfileResponse{}, err
+	}
+	return s.toProfileResponse(user), nil
+}
+
+// validateUpdateProfileRequest returns params with the full name and the phone number normalized.
+func (s *ProfileServiceImpl) validateUpdateProfileRequest(params generated.UpdateProfileRequest) (generated.UpdateProfileRequest, *common.CustomError) {
 	errDetails := []string{}
 
 	if params.PhoneNumber == "" && params.FullName == "" {
-		return common.NewCustomError(common.ErrInvalidInput, "at least one phone number or full name is required")
+		return generated.UpdateProfileRequest{}, common.NewCustomError(common.ErrInvalidInput, "at least one phone number or full name is required")
 	}
 
 	if params.PhoneNumber != "" {
-		params.PhoneNumber = strings.TrimSpace(params.PhoneNumber)
-		if err := validatePhoneNumber(params.PhoneNumber); err != nil {
+		phoneNumber, err := normalizePhoneNumber(params.PhoneNumber)
+		if err != nil {
 			errDetails = append(errDetails, err.Details...)
 		}
+		params.PhoneNumber = phoneNumber
 	}
 
 	if params.FullName != "" {
-		params.FullName = strings.TrimSpace(params.FullName)
-		if err := validateFullName(params.FullName); err != nil {
+		fullName, err := normalizeFullName(params.FullName)
+		if err != nil {
 			errDetails = append(errDetails, err.Details...)
 		}
+		params.FullName = fullName
 	}
 
 	if len(errDetails) != 0 {
-		return common.NewCustomError(common.ErrInvalidInput, "invalid request params", errDetails...)
+		return generated.UpdateProfileRequest{}, common.NewCustomError(common.ErrInvalidInput, "invalid request params", errDetails...)
+	}
+	return params, nil
+}
+
+func (s *ProfileServiceImpl) toProfileResponse(user *model.User) generated.GetProfileResponse {
+	response := generated.GetProfileResponse{
+		FullName:        user.FullName,
+		PhoneNumber:     user.PhoneNumber,
+		Email:           (*openapi_types.Email)(user.Email),
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		Locale:          user.Locale,
+		Timezone:        user.Timezone,
+		LastLoginAt:     user.LastLoginAt,
+		AvatarUrls:      avatarURLs(s.blobStore, user.AvatarKey),
+		Version:         user.Version,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+	}
+
+	if user.DateOfBirth != nil {
+		response.DateOfBirth = &openapi_types.Date{Time: *user.DateOfBirth}
 	}
-	return nil
+	return response
 }
@@ -1,14 +1,24 @@
 package service_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/SawitProRecruitment/UserService/common"
 	"github.com/SawitProRecruitment/UserService/generated"
 	"github.com/SawitProRecruitment/UserService/model"
 	"github.com/SawitProRecruitment/UserService/repository"
 	"github.com/SawitProRecruitment/UserService/service"
+	"github.com/SawitProRecruitment/UserService/storage"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
@@ -16,17 +26,23 @@ import (
 
 type ProfileServiceTestSuite struct {
 	suite.Suite
-	ctrl           *gomock.Controller
-	tokenManager   *service.MockTokenManager
-	userRepository *repository.MockUserRepository
-	sut            *service.ProfileServiceImpl
+	ctrl                 *gomock.Controller
+	tokenManager         *service.MockTokenManager
+	userRepository       *repository.MockUserRepository
+	auditEventRepository *repository.MockAuditEventRepository
+	transactionManager   *repository.MockTransactionManager
+	blobStore            *storage.MockBlobStore
+	sut                  *service.ProfileServiceImpl
 }
 
 func (s *ProfileServiceTestSuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.tokenManager = service.NewMockTokenManager(s.ctrl)
 	s.userRepository = repository.NewMockUserRepository(s.ctrl)
-	s.sut = service.NewProfileServiceImpl(s.userRepository, s.tokenManager)
+	s.auditEventRepository = repository.NewMockAuditEventRepository(s.ctrl)
+	s.transactionManager = newPassThroughTransactionManager(s.ctrl)
+	s.blobStore = storage.NewMockBlobStore(s.ctrl)
+	s.sut = service.NewProfileServiceImpl(s.userRepository, s.auditEventRepository, s.transactionManager, s.tokenManager, s.blobStore)
 }
 
 func (s *ProfileServiceTestSuite) AfterTest(suiteName, testName string) {
@@ -50,7 +66,7 @@ func (s *ProfileServiceTestSuite) TestGetProfileOnTokenValidationErrorShouldRetu
 
 	validateTokenErr := common.NewCustomError(common.ErrUnauthorized, "invalid token")
 
-	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(uuid.Nil, validateTokenErr)
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{}, validateTokenErr)
 
 	result, err := s.sut.GetProfile(ctx)
 
@@ -65,7 +81,8 @@ func (s *ProfileServiceTestSuite) TestGetProfileOnGetUserErrorShouldReturnError(
 
 	repoErr := common.NewCustomError(common.ErrUnexpectedError, "database error")
 
-	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(userID, nil)
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: userID}, nil)
+	s.userRepository.EXPECT().GetAccessState(gomock.Eq(ctx), gomock.Eq(userID)).Return(model.UserAccessState{Status: model.UserStatusActive}, nil)
 	s.userRepository.EXPECT().GetByUserID(gomock.Eq(ctx), gomock.Eq(userID)).Return(nil, repoErr)
 
 	result, err := s.sut.GetProfile(ctx)
@@ -84,14 +101,18 @@ func (s *ProfileServiceTestSuite) TestGetProfileShouldReturnProfileFromRepositor
 		PhoneNumber:  "+628",
 		FullName:     "full",
 		PasswordHash: "hash",
+		Status:       model.UserStatusActive,
+		Version:      4,
 	}
 
 	expectedResult := generated.GetProfileResponse{
 		FullName:    user.FullName,
 		PhoneNumber: user.PhoneNumber,
+		Version:     user.Version,
 	}
 
-	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(userID, nil)
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: userID}, nil)
+	s.userRepository.EXPECT().GetAccessState(gomock.Eq(ctx), gomock.Eq(userID)).Return(model.UserAccessState{Status: model.UserStatusActive}, nil)
 	s.userRepository.EXPECT().GetByUserID(gomock.Eq(ctx), gomock.Eq(userID)).Return(&user, nil)
 
 	result, err := s.sut.GetProfile(ctx)
@@ -99,3 +120,375 @@ func (s *ProfileServiceTestSuite) TestGetProfileShouldReturnProfileFromRepositor
 	s.Nil(err)
 	s.Equal(expectedResult, result)
 }
+
+func (s *ProfileServiceTestSuite) TestGetProfileGivenUserMissingFromReplicaShouldReadPrimary() {
+	accessToken := "access token"
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+	user := model.User{ID: userID, FullName: "full", Status: model.UserStatusActive}
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: userID}, nil)
+	s.userRepository.EXPECT().GetAccessState(gomock.Eq(ctx), gomock.Eq(userID)).Return(model.UserAccessState{Status: model.UserStatusActive}, nil)
+	gomock.InOrder(
+		s.userRepository.EXPECT().GetByUserID(gomock.Eq(ctx), gomock.Eq(userID)).
+			Return(nil, common.NewCustomError(common.ErrEntityNotFound, "user does not exist in database")),
+		s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(userID)).Return(&user, nil),
+	)
+
+	result, err := s.sut.GetProfile(ctx)
+
+	s.Nil(err)
+	s.Equal(user.FullName, result.FullName)
+}
+
+func (s *ProfileServiceTestSuite) TestGetProfileGivenSuspendedUserShouldReturnAccountSuspendedError() {
+	accessToken := "access token"
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: userID}, nil)
+	s.userRepository.EXPECT().GetAccessState(gomock.Eq(ctx), gomock.Eq(userID)).Return(model.UserAccessState{Status: model.UserStatusSuspended}, nil)
+
+	result, err := s.sut.GetProfile(ctx)
+
+	s.Equal(common.ErrAccountSuspended, err.ErrType)
+	s.Equal(generated.GetProfileResponse{}, result)
+}
+
+func (s *ProfileServiceTestSuite) TestGetProfileGivenRevokedTokenShouldReturnUnauthorizedError() {
+	accessToken := "access token"
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+	revokedAt := time.Now()
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).
+		Return(service.TokenClaims{UserID: userID, IssuedAt: revokedAt.Add(-time.Minute)}, nil)
+	s.userRepository.EXPECT().GetAccessState(gomock.Eq(ctx), gomock.Eq(userID)).
+		Return(model.UserAccessState{Status: model.UserStatusActive, TokensRevokedAt: &revokedAt}, nil)
+
+	_, err := s.sut.GetProfile(ctx)
+
+	s.Require().NotNil(err)
+	s.Equal(common.ErrUnauthorized, err.ErrType)
+}
+
+func (s *ProfileServiceTestSuite) TestUpdateProfileGivenExpectedVersionShouldUpdateConditionally() {
+	accessToken := "access token"
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+
+	user := model.User{
+		ID:          userID,
+		PhoneNumber: "+6281234567",
+		FullName:    "full",
+		Status:      model.UserStatusActive,
+		Version:     5,
+	}
+	precondition := common.NewCustomError(common.ErrPreconditionFailed, "profile has been changed by another request")
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: userID}, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(userID)).Return(&user, nil)
+	s.userRepository.EXPECT().Update(gomock.Eq(ctx), gomock.Eq(model.User{ID: userID, FullName: "new full name", Version: 4})).Return(precondition)
+
+	err := s.sut.UpdateProfile(ctx, generated.UpdateProfileRequest{FullName: "new full name"}, 4)
+
+	s.Equal(precondition, err)
+}
+
+func (s *ProfileServiceTestSuite) TestUpdateProfileShouldStorePhoneNumberInE164() {
+	accessToken := "access token"
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+	user := model.User{ID: userID, PhoneNumber: "+6281234567", FullName: "full", Status: model.UserStatusActive}
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: userID}, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(userID)).Return(&user, nil)
+	s.userRepository.EXPECT().Update(gomock.Eq(ctx), gomock.Eq(model.User{ID: userID, PhoneNumber: "+6281234567890"})).Return(nil)
+	s.auditEventRepository.EXPECT().Append(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+		func(_ context.Context, event model.AuditEvent) (model.AuditEvent, *common.CustomError) {
+			// The phone number is personal data, only the fact that it changed is audited.
+			s.Equal(map[string]model.AuditChange{"phone_number": {Redacted: true}}, event.Changes)
+			return event, nil
+		})
+
+	err := s.sut.UpdateProfile(ctx, generated.UpdateProfileRequest{PhoneNumber: "0812-3456-7890"}, 0)
+
+	s.Nil(err)
+}
+
+func (s *ProfileServiceTestSuite) TestPatchProfileShouldOnlyWriteSuppliedFields() {
+	accessToken := "access token"
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+
+	user := model.User{ID: userID, PhoneNumber: "+6281234567", FullName: "full", Status: model.UserStatusActive, Version: 2}
+	patched := user
+	patched.FullName = "new full name"
+	patched.Version = 3
+
+	expectedPatch := model.NewUserPatch(userID)
+	expectedPatch.Set(model.UserFieldFullName, "new full name")
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: userID}, nil)
+	gomock.InOrder(
+		s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(userID)).Return(&user, nil),
+		s.userRepository.EXPECT().Patch(gomock.Eq(ctx), gomock.Eq(expectedPatch)).Return(nil),
+		s.userRepository.EXPECT().GetByUserID(gomock.Eq(ctx), gomock.Eq(userID)).Return(&patched, nil),
+	)
+	s.auditEventRepository.EXPECT().Append(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+		func(_ context.Context, event model.AuditEvent) (model.AuditEvent, *common.CustomError) {
+			s.Equal(map[string]model.AuditChange{"full_name": {Redacted: true}}, event.Changes)
+			return event, nil
+		})
+
+	result, err := s.sut.PatchProfile(ctx, map[string]json.RawMessage{"full_name": json.RawMessage(`" new full name "`)}, 0)
+
+	s.Nil(err)
+	s.Equal(generated.GetProfileResponse{FullName: "new full name", PhoneNumber: user.PhoneNumber, Version: 3}, result)
+}
+
+func (s *ProfileServiceTestSuite) TestPatchProfileGivenEmptyPatchAndStaleVersionShouldReturnPreconditionFailed() {
+	accessToken := "access token"
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+
+	user := model.User{ID: userID, PhoneNumber: "+6281234567", FullName: "full", Status: model.UserStatusActive, Version: 2}
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: userID}, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(userID)).Return(&user, nil)
+
+	_, err := s.sut.PatchProfile(ctx, map[string]json.RawMessage{}, 1)
+
+	s.Require().NotNil(err)
+	s.Equal(common.ErrPreconditionFailed, err.ErrType)
+}
+
+func (s *ProfileServiceTestSuite) TestPatchProfileGivenInvalidFieldsShouldReturnAllErrors() {
+	accessToken := "access token"
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+	user := model.User{ID: userID, Status: model.UserStatusActive}
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: userID}, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(userID)).Return(&user, nil)
+
+	_, err := s.sut.PatchProfile(ctx, map[string]json.RawMessage{
+		"full_name":     json.RawMessage(`null`),
+		"phone_number":  json.RawMessage(`12345`),
+		"password_hash": json.RawMessage(`"hash"`),
+	}, 0)
+
+	s.Require().NotNil(err)
+	s.Equal(common.ErrInvalidInput, err.ErrType)
+	s.Equal([]string{"full_name cannot be null", "password_hash cannot be patched", "phone_number must be a string"}, err.Details)
+}
+
+func (s *ProfileServiceTestSuite) TestPatchProfileShouldNormalizeAndClearOptionalFields() {
+	accessToken := "access token"
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+	locale := "en-US"
+	user := model.User{ID: userID, Status: model.UserStatusActive, Locale: &locale}
+
+	expectedPatch := model.NewUserPatch(userID)
+	expectedPatch.Set(model.UserFieldEmail, "Jasuke@example.com")
+	expectedPatch.Set(model.UserFieldDateOfBirth, "1990-02-01")
+	expectedPatch.Set(model.UserFieldLocale, nil)
+	expectedPatch.Set(model.UserFieldTimezone, "Asia/Jakarta")
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: userID}, nil)
+	gomock.InOrder(
+		s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(userID)).Return(&user, nil),
+		s.userRepository.EXPECT().Patch(gomock.Eq(ctx), gomock.Eq(expectedPatch)).Return(nil),
+		s.userRepository.EXPECT().GetByUserID(gomock.Eq(ctx), gomock.Eq(userID)).Return(&user, nil),
+	)
+	s.auditEventRepository.EXPECT().Append(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+		func(_ context.Context, event model.AuditEvent) (model.AuditEvent, *common.CustomError) {
+			timezone := "Asia/Jakarta"
+			s.Equal(map[string]model.AuditChange{
+				"email":         {Redacted: true},
+				"date_of_birth": {Redacted: true},
+				"locale":        {Before: &locale},
+				"timezone":      {After: &timezone},
+			}, event.Changes)
+			return event, nil
+		})
+
+	_, err := s.sut.PatchProfile(ctx, map[string]json.RawMessage{
+		"email":         json.RawMessage(`"Jasuke@EXAMPLE.com"`),
+		"date_of_birth": json.RawMessage(`"1990-02-01"`),
+		"locale":        json.RawMessage(`null`),
+		"timezone":      json.RawMessage(`" Asia/Jakarta "`),
+	}, 0)
+
+	s.Nil(err)
+}
+
+func (s *ProfileServiceTestSuite) TestPatchProfileGivenInvalidOptionalFieldsShouldReturnAllErrors() {
+	accessToken := "access token"
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+	user := model.User{ID: userID, Status: model.UserStatusActive}
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: userID}, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(userID)).Return(&user, nil)
+
+	_, err := s.sut.PatchProfile(ctx, map[string]json.RawMessage{
+		"email":         json.RawMessage(`"Jasuke <jasuke@example.com>"`),
+		"date_of_birth": json.RawMessage(`"` + time.Now().AddDate(0, 0, 2).Format("2006-01-02") + `"`),
+		"locale":        json.RawMessage(`"not a locale"`),
+		"timezone":      json.RawMessage(`"Local"`),
+	}, 0)
+
+	s.Require().NotNil(err)
+	s.Equal(common.ErrInvalidInput, err.ErrType)
+	s.Equal([]string{
+		"date of birth cannot be in the future",
+		"email must be a valid email address",
+		"locale must be a valid BCP 47 language tag",
+		"timezone must be a valid IANA time zone",
+	}, err.Details)
+}
+
+func (s *ProfileServiceTestSuite) TestUploadAvatarShouldStoreThumbnailsAndReplaceOldAvatar() {
+	accessToken := "access token"
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+	oldKey := "avatars/" + userID.String() + "/old"
+	user := model.User{ID: userID, Status: model.UserStatusActive, AvatarKey: &oldKey}
+
+	var newKey string
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: userID}, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(userID)).Return(&user, nil)
+	s.blobStore.EXPECT().Put(gomock.Eq(ctx), gomock.Any(), gomock.Eq("image/jpeg"), gomock.Any()).Times(3).DoAndReturn(
+		func(_ context.Context, key string, _ string, content io.Reader) error {
+			img, err := jpeg.Decode(content)
+			s.Require().NoError(err)
+			s.Equal(img.Bounds().Dx(), img.Bounds().Dy())
+			s.True(strings.HasPrefix(key, "avatars/"+userID.String()+"/"))
+			return nil
+		})
+	s.userRepository.EXPECT().Patch(gomock.Eq(ctx), gomock.Any()).DoAndReturn(func(_ context.Context, patch model.UserPatch) *common.CustomError {
+		newKey, _ = patch.StringValue(model.UserFieldAvatarKey)
+		s.NotEqual(oldKey, newKey)
+		return nil
+	})
+	s.auditEventRepository.EXPECT().Append(gomock.Eq(ctx), gomock.Any()).Return(model.AuditEvent{}, nil)
+	for _, name := range []string{"small", "medium", "large"} {
+		s.blobStore.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(oldKey+"/"+name+".jpg")).Return(nil)
+	}
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(ctx), gomock.Eq(userID)).DoAndReturn(func(_ context.Context, _ uuid.UUID) (*model.User, *common.CustomError) {
+		updated := user
+		updated.AvatarKey = &newKey
+		return &updated, nil
+	})
+	s.blobStore.EXPECT().URL(gomock.Any()).Times(3).DoAndReturn(func(key string) string { return "/blobs/" + key })
+
+	result, err := s.sut.UploadAvatar(ctx, bytes.NewReader(s.newPNG(300, 200)))
+
+	s.Nil(err)
+	s.Require().NotNil(result.AvatarUrls)
+	s.Equal("/blobs/"+newKey+"/small.jpg", result.AvatarUrls.Small)
+}
+
+func (s *ProfileServiceTestSuite) TestUploadAvatarGivenNonImageShouldReturnUnsupportedMediaTypeError() {
+	accessToken := "access token"
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+	user := model.User{ID: userID, Status: model.UserStatusActive}
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: userID}, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(userID)).Return(&user, nil)
+
+	_, err := s.sut.UploadAvatar(ctx, strings.NewReader("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+
+	s.Require().NotNil(err)
+	s.Equal(common.ErrUnsupportedMediaType, err.ErrType)
+}
+
+func (s *ProfileServiceTestSuite) TestUploadAvatarGivenOversizedFileShouldReturnPayloadTooLargeError() {
+	accessToken := "access token"
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+	user := model.User{ID: userID, Status: model.UserStatusActive}
+
+	s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: userID}, nil)
+	s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(userID)).Return(&user, nil)
+
+	_, err := s.sut.UploadAvatar(ctx, bytes.NewReader(make([]byte, service.MaxAvatarSize+1)))
+
+	s.Require().NotNil(err)
+	s.Equal(common.ErrPayloadTooLarge, err.ErrType)
+}
+
+func (s *ProfileServiceTestSuite) newPNG(width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, height/2, color.RGBA{R: 255, A: 255})
+	}
+
+	var buffer bytes.Buffer
+	s.Require().NoError(png.Encode(&buffer, img))
+	return buffer.Bytes()
+}
+
+func (s *ProfileServiceTestSuite) TestPatchProfileShouldNormalizeFullName() {
+	testCases := map[string]string{
+		"  Jose\u0301 \t Marti\u0301nez ": "José Martínez",
+		strings.Repeat("王", 60):           strings.Repeat("王", 60),
+		"नमस्ते दुनिया":                   "नमस्ते दुनिया",
+		"Mi\u200clad Ahmadi":              "Mi\u200clad Ahmadi",
+	}
+
+	for input, expected := range testCases {
+		accessToken := "access token"
+		userID := uuid.New()
+		ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+		user := model.User{ID: userID, Status: model.UserStatusActive}
+
+		expectedPatch := model.NewUserPatch(userID)
+		expectedPatch.Set(model.UserFieldFullName, expected)
+
+		document, err := json.Marshal(input)
+		s.Require().NoError(err)
+
+		s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: userID}, nil)
+		s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(userID)).Return(&user, nil)
+		s.userRepository.EXPECT().GetByUserID(gomock.Eq(ctx), gomock.Eq(userID)).Return(&user, nil)
+		s.userRepository.EXPECT().Patch(gomock.Eq(ctx), gomock.Eq(expectedPatch)).Return(nil)
+		s.auditEventRepository.EXPECT().Append(gomock.Eq(ctx), gomock.Any()).Return(model.AuditEvent{}, nil)
+
+		_, errPatch := s.sut.PatchProfile(ctx, map[string]json.RawMessage{"full_name": document}, 0)
+
+		s.Nil(errPatch, input)
+	}
+}
+
+func (s *ProfileServiceTestSuite) TestPatchProfileGivenInvalidFullNameShouldReturnInvalidInputError() {
+	testCases := map[string]string{
+		"Jo":                                  "full name must be between 3 and 60 characters",
+		strings.Repeat("王", 61):               "full name must be between 3 and 60 characters",
+		"Jasuke\u202egnp.exe":                 "full name must not contain control, formatting or unassigned characters",
+		"\u200dJasuke":                        "full name must not contain control, formatting or unassigned characters",
+		"Jas\u0000uke":                        "full name must not contain control, formatting or unassigned characters",
+		"Jas" + strings.Repeat("\u0301", 300): "full name has too many combining marks",
+	}
+
+	for input, expected := range testCases {
+		accessToken := "access token"
+		userID := uuid.New()
+		ctx := context.WithValue(context.Background(), common.KeyAccessToken, accessToken)
+		user := model.User{ID: userID, Status: model.UserStatusActive}
+
+		document, err := json.Marshal(input)
+		s.Require().NoError(err)
+
+		s.tokenManager.EXPECT().ValidateToken(gomock.Eq(accessToken)).Return(service.TokenClaims{UserID: userID}, nil)
+		s.userRepository.EXPECT().GetByUserID(gomock.Eq(common.WithPrimaryReads(ctx)), gomock.Eq(userID)).Return(&user, nil)
+
+		_, errPatch := s.sut.PatchProfile(ctx, map[string]json.RawMessage{"full_name": document}, 0)
+
+		s.Require().NotNil(errPatch, input)
+		s.Equal([]string{expected}, errPatch.Details, input)
+	}
+}
@@ -1,8 +1,9 @@
 package service
 
 import (
+	"context"
 	"crypto/rsa"
-	"log"
+	"fmt"
 	"os"
 	"time"
 
@@ -11,61 +12,80 @@ import (
 	"github.com/google/uuid"
 )
 
+type TokenClaims struct {
+	UserID   uuid.UUID
+	IssuedAt time.Time
+}
+
 type JWTManager struct {
 	publicKey  *rsa.PublicKey
 	privateKey *rsa.PrivateKey
+	tokenTTL   time.Duration
 }
 
-func loadPrivateKey(path string) *rsa.PrivateKey {
+func loadPrivateKey(path string) (*rsa.PrivateKey, error) {
 	privateKeyBytes, err := os.ReadFile(path)
 	if err != nil {
-		log.Fatal(err.Error())
+		return nil, fmt.Errorf("reading private key: %w", err)
 	}
 
 	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privateKeyBytes)
 	if err != nil {
-		log.Fatal(err.Error())
+		return nil, fmt.Errorf("parsing private key %s: %w", path, err)
 	}
-
-	return privateKey
+	return privateKey, nil
 }
 
-func loadPublicKey(path string) *rsa.PublicKey {
+func loadPublicKey(path string) (*rsa.PublicKey, error) {
 	publicKeyBytes, err := os.ReadFile(path)
 	if err != nil {
-		log.Fatal(err.Error())
+		return nil, fmt.Errorf("reading public key: %w", err)
 	}
 
 	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicKeyBytes)
 	if err != nil {
-		log.Fatal(err.Error())
+		return nil, fmt.Errorf("parsing public key %s: %w", path, err)
 	}
-	return publicKey
+	return publicKey, nil
 }
 
-func NewJWTManager(publicKeyPath, privateKeyPath string) *JWTManager {
-	return &JWTManager{
-		privateKey: loadPrivateKey(privateKeyPath),
-		publicKey:  loadPublicKey(publicKeyPath),
+// NewJWTManager loads the RSA key pair signing and verifying access tokens, which are valid for tokenTTL.
+func NewJWTManager(publicKeyPath, privateKeyPath string, tokenTTL time.Duration) (*JWTManager, error) {
+	privateKey, err := loadPrivateKey(privateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := loadPublicKey(publicKeyPath)
+	if err != nil {
+		return nil, err
 	}
+
+	return &JWTManager{
+		privateKey: privateKey,
+		publicKey:  publicKey,
+		tokenTTL:   tokenTTL,
+	}, nil
 }
 
 func (s *JWTManager) GenerateToken(userID uuid.UUID) (string, *common.CustomError) {
+	now := time.Now()
+
 	claims := jwt.MapClaims{}
 	claims["user_id"] = userID.String()
-	claims["exp"] = time.Now().Add(time.Hour * 1).Unix()
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(s.tokenTTL).Unix()
 
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
 
 	tokenString, err := token.SignedString(s.privateKey)
 	if err != nil {
-		log.Fatal(err)
-		return "", common.NewCustomError(common.ErrUnexpectedError, "internal server error")
+		return "", common.WrapError(common.ErrUnexpectedError, err, "error signing token")
 	}
 	return tokenString, nil
 }
 
-func (s *JWTManager) ValidateToken(accessToken string) (uuid.UUID, *common.CustomError) {
+func (s *JWTManager) ValidateToken(accessToken string) (TokenClaims, *common.CustomError) {
 	token, err := jwt.Parse(accessToken, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
 			return nil, common.NewCustomError(common.ErrInvalidInput, "unexpected signing method")
@@ -74,12 +94,59 @@ func (s *JWTManager) ValidateToken(accessToken string) (uuid.UUID, *common.Custo
 	})
 
 	if err != nil || !token.Valid {
-		return uuid.Nil, common.NewCustomError(common.ErrUnauthorized, "invalid access token")
+		tokenValidationFailuresTotal.Inc(tokenFailureReason(err))
+		return TokenClaims{}, common.NewCustomError(common.ErrUnauthorized, "invalid access token")
+	}
+
+	mapClaims := token.Claims.(jwt.MapClaims)
+
+	rawUserID, _ := mapClaims["user_id"].(string)
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		tokenValidationFailuresTotal.Inc("invalid_claims")
+		return TokenClaims{}, common.NewCustomError(common.ErrUnauthorized, "invalid access token")
+	}
+
+	// Tokens issued before iat was introduced keep a zero IssuedAt, which makes them revocable.
+	claims := TokenClaims{UserID: userID}
+	if iat, ok := mapClaims["iat"].(float64); ok {
+		claims.IssuedAt = time.Unix(int64(iat), 0)
+	}
+	return claims, nil
+}
+
+// tokenFailureReason classifies why jwt.Parse rejected a token for the token validation metrics.
+func tokenFailureReason(err error) string {
+	validationErr, ok := err.(*jwt.ValidationError)
+	if !ok {
+		return "invalid"
+	}
+
+	switch {
+	case validationErr.Errors&jwt.ValidationErrorMalformed != 0:
+		return "malformed"
+	case validationErr.Errors&jwt.ValidationErrorUnverifiable != 0:
+		return "unverifiable"
+	case validationErr.Errors&jwt.ValidationErrorSignatureInvalid != 0:
+		return "invalid_signature"
+	case validationErr.Errors&jwt.ValidationErrorExpired != 0:
+		return "expired"
+	case validationErr.Errors&(jwt.ValidationErrorNotValidYet|jwt.ValidationErrorIssuedAt) != 0:
+		return "not_valid_yet"
+	default:
+		return "invalid_claims"
 	}
+}
 
-	userID, err := uuid.Parse(token.Claims.(jwt.MapClaims)["user_id"].(string))
+// CheckSigningKey signs a token and verifies it again, which fails when the key pair cannot be used or the
+// public key does not belong to the private key.
+func (s *JWTManager) CheckSigningKey(ctx context.Context) error {
+	token, err := s.GenerateToken(uuid.Nil)
 	if err != nil {
-		return uuid.Nil, common.NewCustomError(common.ErrUnauthorized, "invalid access token")
+		return fmt.Errorf("signing token: %w", err)
+	}
+	if _, err := s.ValidateToken(token); err != nil {
+		return fmt.Errorf("verifying token: %w", err)
 	}
-	return userID, nil
+	return nil
 }
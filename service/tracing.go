@@ -0,0 +1,14 @@
+package service
+
+import (
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/tracing"
+)
+
+// endSpan ends span, marking it failed when the traced operation returned err.
+func endSpan(span *tracing.Span, err *common.CustomError) {
+	if err != nil {
+		span.SetError(err.Message)
+	}
+	span.End()
+}
@@ -1,43 +1,44 @@
 package service
 
 import (
+	"fmt"
+	"net/mail"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/phonenumber"
+	"golang.org/x/text/language"
 )
 
-func validateFullName(fullName string) *common.CustomError {
-	errDetails := []string{}
+const (
+	maxEmailLength = 254
+	maxAgeInYears  = 150
 
-	if len(fullName) < 3 || len(fullName) > 60 {
-		errDetails = append(errDetails, "full name must be between 3 and 60 characters")
-	}
+	dateOfBirthLayout = "2006-01-02"
+)
 
-	if len(errDetails) != 0 {
-		return common.NewCustomError(common.ErrInvalidInput, "invalid request params", errDetails...)
+// normalizePhoneNumber parses a phone number written in any common notation and returns its E.164 form,
+// which is the only form stored. Numbers without an international prefix are read as Indonesian.
+func normalizePhoneNumber(phoneNumber string) (string, *common.CustomError) {
+	parsed, err := phonenumber.Parse(phoneNumber, phonenumber.DefaultRegion)
+	if err != nil {
+		return "", common.NewCustomError(common.ErrInvalidInput, "invalid request params", err.Error())
 	}
-	return nil
+	return parsed.E164(), nil
 }
 
-func validatePhoneNumber(phoneNumber string) *common.CustomError {
-	errDetails := []string{}
-
-	if len(phoneNumber) < 10 || len(phoneNumber) > 13 {
-		errDetails = append(errDetails, "phone number must be between 10 and 13 characters")
-	}
-
-	if !strings.HasPrefix(phoneNumber, "+62") {
-		errDetails = append(errDetails, "phone number must start with +62")
-	}
-
-	if !containsOnlyNumber(phoneNumber[1:]) { // skip the +
-		errDetails = append(errDetails, "phone number must only contain number")
+// phoneNumberLookupKey returns the form a user is looked up by for phoneNumber: its E.164 form, or the number
+// as written when it cannot be parsed, since stored numbers the re-encryption job could not parse are kept
+// as they were registered. parsed reports which of the two was returned.
+func phoneNumberLookupKey(phoneNumber string) (key string, parsed bool) {
+	normalized, err := normalizePhoneNumber(phoneNumber)
+	if err != nil {
+		return phoneNumber, false
 	}
-
-	if len(errDetails) != 0 {
-		return common.NewCustomError(common.ErrInvalidInput, "invalid request params", errDetails...)
-	}
-	return nil
+	return normalized, true
 }
 
 func validatePassword(password string) *common.CustomError {
@@ -86,11 +87,104 @@ func hasCapitalLetter(s string) bool {
 	return false
 }
 
-func containsOnlyNumber(s string) bool {
-	for _, c := range s {
-		if c < '0' || c > '9' {
-			return false
+func validateWebhookURL(rawURL string) *common.CustomError {
+	errDetails := []string{}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		errDetails = append(errDetails, "url must be an absolute http or https url")
+	}
+
+	if len(errDetails) != 0 {
+		return common.NewCustomError(common.ErrInvalidInput, "invalid request params", errDetails...)
+	}
+	return nil
+}
+
+func validateWebhookSecret(secret string) *common.CustomError {
+	errDetails := []string{}
+
+	if len(secret) < minWebhookSecretLength {
+		errDetails = append(errDetails, fmt.Sprintf("secret must be at least %d characters", minWebhookSecretLength))
+	}
+
+	if len(errDetails) != 0 {
+		return common.NewCustomError(common.ErrInvalidInput, "invalid request params", errDetails...)
+	}
+	return nil
+}
+
+func validateEventTypes(eventTypes []model.OutboxEventType) *common.CustomError {
+	errDetails := []string{}
+
+	for _, eventType := range eventTypes {
+		if !eventType.IsValid() {
+			errDetails = append(errDetails, fmt.Sprintf("event type %q is not supported", eventType))
 		}
 	}
-	return true
+
+	if len(errDetails) != 0 {
+		return common.NewCustomError(common.ErrInvalidInput, "invalid request params", errDetails...)
+	}
+	return nil
+}
+
+// normalizeEmail accepts a bare address and lower-cases its domain, which is case-insensitive. The local
+// part is kept as given because mail servers may treat it case-sensitively.
+func normalizeEmail(email string) (string, *common.CustomError) {
+	errDetails := []string{}
+
+	if len(email) > maxEmailLength {
+		errDetails = append(errDetails, fmt.Sprintf("email must be at most %d characters", maxEmailLength))
+	}
+
+	address, err := mail.ParseAddress(email)
+	at := strings.LastIndex(email, "@")
+	if err != nil || address.Name != "" || address.Address != email || at < 1 || !strings.Contains(email[at:], ".") {
+		errDetails = append(errDetails, "email must be a valid email address")
+	}
+
+	if len(errDetails) != 0 {
+		return "", common.NewCustomError(common.ErrInvalidInput, "invalid request params", errDetails...)
+	}
+	return email[:at] + strings.ToLower(email[at:]), nil
+}
+
+func parseDateOfBirth(dateOfBirth string, now time.Time) (time.Time, *common.CustomError) {
+	errDetails := []string{}
+
+	date, err := time.Parse(dateOfBirthLayout, dateOfBirth)
+	if err != nil {
+		errDetails = append(errDetails, "date of birth must be a date formatted as YYYY-MM-DD")
+	} else if date.After(now) {
+		errDetails = append(errDetails, "date of birth cannot be in the future")
+	} else if date.Before(now.AddDate(-maxAgeInYears, 0, 0)) {
+		errDetails = append(errDetails, fmt.Sprintf("date of birth cannot be more than %d years ago", maxAgeInYears))
+	}
+
+	if len(errDetails) != 0 {
+		return time.Time{}, common.NewCustomError(common.ErrInvalidInput, "invalid request params", errDetails...)
+	}
+	return date, nil
+}
+
+// normalizeLocale returns the canonical form of a BCP 47 language tag, e.g. "en-us" becomes "en-US".
+func normalizeLocale(locale string) (string, *common.CustomError) {
+	tag, err := language.Parse(locale)
+	if err != nil || tag == language.Und {
+		return "", common.NewCustomError(common.ErrInvalidInput, "invalid request params", "locale must be a valid BCP 47 language tag")
+	}
+	return tag.String(), nil
+}
+
+func validateTimezone(timezone string) *common.CustomError {
+	// LoadLocation also accepts "" and "Local", which name the server's zone rather than an IANA zone.
+	if timezone == "" || timezone == "Local" {
+		return common.NewCustomError(common.ErrInvalidInput, "invalid request params", "timezone must be a valid IANA time zone")
+	}
+
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return common.NewCustomError(common.ErrInvalidInput, "invalid request params", "timezone must be a valid IANA time zone")
+	}
+	return nil
 }
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	minWebhookSecretLength       = 16
+	generatedWebhookSecretLength = 32
+	defaultWebhookDeliveryPage   = 50
+	maxWebhookDeliveryPage       = 100
+)
+
+type WebhookServiceImpl struct {
+	webhookRepository repository.WebhookRepository
+}
+
+func NewWebhookServiceImpl(webhookRepository repository.WebhookRepository) *WebhookServiceImpl {
+	return &WebhookServiceImpl{
+		webhookRepository: webhookRepository,
+	}
+}
+
+func (s *WebhookServiceImpl) ListWebhooks(ctx context.Context) (generated.WebhookSubscriptionList, *common.CustomError) {
+	subscriptions, err := s.webhookRepository.GetSubscriptions(ctx)
+	if err != nil {
+		return generated.WebhookSubscriptionList{}, err
+	}
+
+	result := generated.WebhookSubscriptionList{
+		Webhooks: make([]generated.WebhookSubscription, 0, len(subscriptions)),
+	}
+	for _, subscription := range subscriptions {
+		result.Webhooks = append(result.Webhooks, toWebhookSubscriptionResponse(subscription))
+	}
+	return result, nil
+}
+
+// CreateWebhook registers a subscription. The secret is returned only here, a random one is generated
+// when the request does not carry one.
+func (s *WebhookServiceImpl) CreateWebhook(ctx context.Context, params generated.CreateWebhookSubscriptionRequest) (generated.WebhookSubscription, *common.CustomError) {
+	now := time.Now()
+	subscription := model.WebhookSubscription{
+		URL:        strings.TrimSpace(params.Url),
+		EventTypes: []model.OutboxEventType{},
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if params.Secret != nil {
+		subscription.Secret = *params.Secret
+	} else {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return generated.WebhookSubscription{}, err
+		}
+		subscription.Secret = secret
+	}
+
+	if params.EventTypes != nil {
+		for _, eventType := range *params.EventTypes {
+			subscription.EventTypes = append(subscription.EventTypes, model.OutboxEventType(eventType))
+		}
+	}
+
+	if err := validateWebhookSubscription(subscription); err != nil {
+		return generated.WebhookSubscription{}, err
+	}
+
+	subscriptionID, err := s.webhookRepository.SaveSubscription(ctx, subscription)
+	if err != nil {
+		return generated.WebhookSubscription{}, err
+	}
+	subscription.ID = subscriptionID
+
+	result := toWebhookSubscriptionResponse(subscription)
+	result.Secret = &subscription.Secret
+	return result, nil
+}
+
+func (s *WebhookServiceImpl) GetWebhook(ctx context.Context, webhookID uuid.UUID) (generated.WebhookSubscription, *common.CustomError) {
+	subscription, err := s.webhookRepository.GetSubscription(ctx, webhookID)
+	if err != nil {
+		return generated.WebhookSubscription{}, err
+	}
+	return toWebhookSubscriptionResponse(*subscription), nil
+}
+
+func (s *WebhookServiceImpl) UpdateWebhook(ctx context.Context, webhookID uuid.UUID, params generated.UpdateWebhookSubscriptionRequest) (generated.WebhookSubscription, *common.CustomError) {
+	subscription, err := s.webhookRepository.GetSubscription(ctx, webhookID)
+	if err != nil {
+		return generated.WebhookSubscription{}, err
+	}
+
+	if params.Url != nil {
+		subscription.URL = strings.TrimSpace(*params.Url)
+	}
+
+	if params.Secret != nil {
+		subscription.Secret = *params.Secret
+	}
+
+	if params.EventTypes != nil {
+		subscription.EventTypes = []model.OutboxEventType{}
+		for _, eventType := range *params.EventTypes {
+			subscription.EventTypes = append(subscription.EventTypes, model.OutboxEventType(eventType))
+		}
+	}
+
+	if params.Active != nil {
+		subscription.Active = *params.Active
+	}
+
+	if err := validateWebhookSubscription(*subscription); err != nil {
+		return generated.WebhookSubscription{}, err
+	}
+
+	subscription.UpdatedAt = time.Now()
+	if err := s.webhookRepository.UpdateSubscription(ctx, *subscription); err != nil {
+		return generated.WebhookSubscription{}, err
+	}
+	return toWebhookSubscriptionResponse(*subscription), nil
+}
+
+func (s *WebhookServiceImpl) DeleteWebhook(ctx context.Context, webhookID uuid.UUID) *common.CustomError {
+	return s.webhookRepository.DeleteSubscription(ctx, webhookID)
+}
+
+func (s *WebhookServiceImpl) ListDeliveries(ctx context.Context, webhookID uuid.UUID, params generated.GetApiV1AdminWebhooksWebhookIdDeliveriesParams) (generated.WebhookDeliveryList, *common.CustomError) {
+	if _, err := s.webhookRepository.GetSubscription(ctx, webhookID); err != nil {
+		return generated.WebhookDeliveryList{}, err
+	}
+
+	filter := model.WebhookDeliveryFilter{
+		SubscriptionID: webhookID,
+		Before:         params.Before,
+		Limit:          defaultWebhookDeliveryPage,
+	}
+
+	if params.Status != nil {
+		status := model.WebhookDeliveryStatus(*params.Status)
+		filter.Status = &status
+	}
+
+	if params.Limit != nil {
+		if *params.Limit < 1 || *params.Limit > maxWebhookDeliveryPage {
+			return generated.WebhookDeliveryList{}, common.NewCustomError(common.ErrInvalidInput, "invalid request params", "limit must be between 1 and 100")
+		}
+		filter.Limit = *params.Limit
+	}
+
+	deliveries, err := s.webhookRepository.GetDeliveries(ctx, filter)
+	if err != nil {
+		return generated.WebhookDeliveryList{}, err
+	}
+
+	result := generated.WebhookDeliveryList{
+		Deliveries: make([]generated.WebhookDelivery, 0, len(deliveries)),
+	}
+	for _, delivery := range deliveries {
+		result.Deliveries = append(result.Deliveries, toWebhookDeliveryResponse(delivery))
+	}
+
+	if len(deliveries) == filter.Limit {
+		result.NextBefore = &deliveries[len(deliveries)-1].CreatedAt
+	}
+	return result, nil
+}
+
+// Redeliver queues a delivery again, typically after it was dead-lettered and the subscriber has been fixed.
+func (s *WebhookServiceImpl) Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (generated.WebhookDelivery, *common.CustomError) {
+	delivery, err := s.webhookRepository.GetDelivery(ctx, webhookID, deliveryID)
+	if err != nil {
+		return generated.WebhookDelivery{}, err
+	}
+
+	now := time.Now()
+	if err := s.webhookRepository.Redeliver(ctx, deliveryID, now); err != nil {
+		return generated.WebhookDelivery{}, err
+	}
+
+	delivery.Status = model.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = now
+	delivery.DeliveredAt = nil
+	return toWebhookDeliveryResponse(*delivery), nil
+}
+
+func validateWebhookSubscription(subscription model.WebhookSubscription) *common.CustomError {
+	errDetails := []string{}
+
+	if err := validateWebhookURL(subscription.URL); err != nil {
+		errDetails = append(errDetails, err.Details...)
+	}
+
+	if err := validateWebhookSecret(subscription.Secret); err != nil {
+		errDetails = append(errDetails, err.Details...)
+	}
+
+	if err := validateEventTypes(subscription.EventTypes); err != nil {
+		errDetails = append(errDetails, err.Details...)
+	}
+
+	if len(errDetails) != 0 {
+		return common.NewCustomError(common.ErrInvalidInput, "invalid request params", errDetails...)
+	}
+	return nil
+}
+
+func generateWebhookSecret() (string, *common.CustomError) {
+	secret := make([]byte, generatedWebhookSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", common.WrapError(common.ErrUnexpectedError, err, "error generating webhook secret")
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func toWebhookSubscriptionResponse(subscription model.WebhookSubscription) generated.WebhookSubscription {
+	eventTypes := make([]generated.WebhookSubscriptionEventTypes, 0, len(subscription.EventTypes))
+	for _, eventType := range subscription.EventTypes {
+		eventTypes = append(eventTypes, generated.WebhookSubscriptionEventTypes(eventType))
+	}
+
+	return generated.WebhookSubscription{
+		Id:         subscription.ID,
+		Url:        subscription.URL,
+		EventTypes: eventTypes,
+		Active:     subscription.Active,
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryResponse(delivery model.WebhookDelivery) generated.WebhookDelivery {
+	return generated.WebhookDelivery{
+		Id:             delivery.ID,
+		WebhookId:      delivery.SubscriptionID,
+		EventId:        delivery.EventID,
+		EventType:      string(delivery.EventType),
+		Status:         generated.WebhookDeliveryStatus(delivery.Status),
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+	}
+}
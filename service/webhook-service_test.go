@@ -0,0 +1,97 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/SawitProRecruitment/UserService/service"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookServiceTestSuite struct {
+	suite.Suite
+	ctrl              *gomock.Controller
+	webhookRepository *repository.MockWebhookRepository
+	sut               *service.WebhookServiceImpl
+}
+
+func (s *WebhookServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.webhookRepository = repository.NewMockWebhookRepository(s.ctrl)
+	s.sut = service.NewWebhookServiceImpl(s.webhookRepository)
+}
+
+func (s *WebhookServiceTestSuite) AfterTest(suiteName, testName string) {
+	s.ctrl.Finish()
+}
+
+func TestWebhookServiceImpl(t *testing.T) {
+	suite.Run(t, new(WebhookServiceTestSuite))
+}
+
+func (s *WebhookServiceTestSuite) TestCreateWebhookGivenNoSecretShouldGenerateAndReturnIt() {
+	ctx := context.Background()
+	webhookID := uuid.New()
+	eventTypes := []generated.CreateWebhookSubscriptionRequestEventTypes{generated.CreateWebhookSubscriptionRequestEventTypesUserRegistered}
+
+	var saved model.WebhookSubscription
+	s.webhookRepository.EXPECT().SaveSubscription(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+		func(_ context.Context, subscription model.WebhookSubscription) (uuid.UUID, *common.CustomError) {
+			saved = subscription
+			return webhookID, nil
+		})
+
+	result, err := s.sut.CreateWebhook(ctx, generated.CreateWebhookSubscriptionRequest{Url: "https://partner.example.com/hooks", EventTypes: &eventTypes})
+
+	s.Nil(err)
+	s.Equal(webhookID, result.Id)
+	s.Require().NotNil(result.Secret)
+	s.Len(*result.Secret, 64)
+	s.Equal(saved.Secret, *result.Secret)
+	s.Equal([]model.OutboxEventType{model.OutboxEventUserRegistered}, saved.EventTypes)
+	s.True(result.Active)
+}
+
+func (s *WebhookServiceTestSuite) TestCreateWebhookGivenInvalidParamsShouldReturnAllErrors() {
+	ctx := context.Background()
+	secret := "short"
+	eventTypes := []generated.CreateWebhookSubscriptionRequestEventTypes{"user.unknown"}
+
+	_, err := s.sut.CreateWebhook(ctx, generated.CreateWebhookSubscriptionRequest{Url: "ftp://partner.example.com", Secret: &secret, EventTypes: &eventTypes})
+
+	s.Require().NotNil(err)
+	s.Equal(common.ErrInvalidInput, err.ErrType)
+	s.Len(err.Details, 3)
+}
+
+func (s *WebhookServiceTestSuite) TestGetWebhookShouldNotExposeSecret() {
+	ctx := context.Background()
+	subscription := model.WebhookSubscription{ID: uuid.New(), URL: "https://partner.example.com/hooks", Secret: "0123456789abcdef", Active: true}
+
+	s.webhookRepository.EXPECT().GetSubscription(gomock.Eq(ctx), gomock.Eq(subscription.ID)).Return(&subscription, nil)
+
+	result, err := s.sut.GetWebhook(ctx, subscription.ID)
+
+	s.Nil(err)
+	s.Nil(result.Secret)
+}
+
+func (s *WebhookServiceTestSuite) TestRedeliverGivenDeadLetteredDeliveryShouldQueueItAgain() {
+	ctx := context.Background()
+	delivery := model.WebhookDelivery{ID: uuid.New(), SubscriptionID: uuid.New(), Status: model.WebhookDeliveryDeadLettered, Attempts: 10}
+
+	s.webhookRepository.EXPECT().GetDelivery(gomock.Eq(ctx), gomock.Eq(delivery.SubscriptionID), gomock.Eq(delivery.ID)).Return(&delivery, nil)
+	s.webhookRepository.EXPECT().Redeliver(gomock.Eq(ctx), gomock.Eq(delivery.ID), gomock.Any()).Return(nil)
+
+	result, err := s.sut.Redeliver(ctx, delivery.SubscriptionID, delivery.ID)
+
+	s.Nil(err)
+	s.Equal(generated.WebhookDeliveryStatusPending, result.Status)
+	s.Equal(0, result.Attempts)
+}
@@ -0,0 +1,16 @@
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// BlobStore keeps binary objects such as avatar images under slash separated keys.
+type BlobStore interface {
+	// Put stores content under key, replacing any object already stored there.
+	Put(ctx context.Context, key string, contentType string, content io.Reader) error
+	// Delete removes the object under key. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns the address clients use to download the object under key.
+	URL(key string) string
+}
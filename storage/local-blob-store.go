@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type LocalBlobStoreOptions struct {
+	// Dir is the directory objects are written to.
+	Dir string
+	// BaseURL is prefixed to the key of an object to build its URL. The directory has to be served under
+	// it, e.g. by a static file handler.
+	BaseURL string
+}
+
+// LocalBlobStore stores objects as files on the local filesystem. It suits single instance deployments,
+// replicas need a shared store.
+type LocalBlobStore struct {
+	opts *LocalBlobStoreOptions
+}
+
+func NewLocalBlobStore(opts LocalBlobStoreOptions) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	opts.BaseURL = strings.TrimSuffix(opts.BaseURL, "/")
+	return &LocalBlobStore{
+		opts: &opts,
+	}, nil
+}
+
+// Put writes the content to a temporary file first, so readers never see a partially written object.
+func (s *LocalBlobStore) Put(ctx context.Context, key string, contentType string, content io.Reader) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), filePath)
+}
+
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalBlobStore) URL(key string) string {
+	return s.opts.BaseURL + "/" + key
+}
+
+// filePath maps a key into Dir, rejecting keys that would escape it.
+func (s *LocalBlobStore) filePath(key string) (string, error) {
+	if key == "" || path.Clean("/"+key) != "/"+key {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.opts.Dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,69 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/storage"
+	"github.com/stretchr/testify/suite"
+)
+
+type LocalBlobStoreTestSuite struct {
+	suite.Suite
+	dir string
+	sut *storage.LocalBlobStore
+}
+
+func (s *LocalBlobStoreTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+
+	var err error
+	s.sut, err = storage.NewLocalBlobStore(storage.LocalBlobStoreOptions{
+		Dir:     s.dir,
+		BaseURL: "https://example.com/blobs/",
+	})
+	s.Require().NoError(err)
+}
+
+func TestLocalBlobStore(t *testing.T) {
+	suite.Run(t, new(LocalBlobStoreTestSuite))
+}
+
+func (s *LocalBlobStoreTestSuite) TestPutThenDeleteShouldRoundTrip() {
+	ctx := context.Background()
+	key := "avatars/user/small.jpg"
+
+	s.Require().NoError(s.sut.Put(ctx, key, "image/jpeg", bytes.NewBufferString("first")))
+	s.Require().NoError(s.sut.Put(ctx, key, "image/jpeg", bytes.NewBufferString("second")))
+
+	content, err := os.ReadFile(filepath.Join(s.dir, "avatars", "user", "small.jpg"))
+	s.Require().NoError(err)
+	s.Equal("second", string(content))
+
+	entries, err := os.ReadDir(filepath.Join(s.dir, "avatars", "user"))
+	s.Require().NoError(err)
+	s.Len(entries, 1, "no temporary files are left behind")
+
+	s.Require().NoError(s.sut.Delete(ctx, key))
+	_, err = os.Stat(filepath.Join(s.dir, "avatars", "user", "small.jpg"))
+	s.True(os.IsNotExist(err))
+}
+
+func (s *LocalBlobStoreTestSuite) TestDeleteGivenMissingObjectShouldSucceed() {
+	s.NoError(s.sut.Delete(context.Background(), "avatars/missing.jpg"))
+}
+
+func (s *LocalBlobStoreTestSuite) TestPutGivenKeyEscapingDirShouldFail() {
+	for _, key := range []string{"", "../outside.jpg", "avatars/../../outside.jpg", "/absolute.jpg"} {
+		s.Error(s.sut.Put(context.Background(), key, "image/jpeg", bytes.NewBufferString("content")), key)
+	}
+	_, err := os.Stat(filepath.Join(filepath.Dir(s.dir), "outside.jpg"))
+	s.True(os.IsNotExist(err))
+}
+
+func (s *LocalBlobStoreTestSuite) TestURLShouldPrefixBaseURL() {
+	s.Equal("https://example.com/blobs/avatars/user/small.jpg", s.sut.URL("avatars/user/small.jpg"))
+}
@@ -0,0 +1,212 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// instrumentationScope names the code that created the spans in exported data.
+const instrumentationScope = "github.com/SawitProRecruitment/UserService"
+
+// Exporter sends batches of ended spans to a tracing backend.
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+}
+
+// WriterExporter writes every batch as one line of OTLP/JSON, the format the OpenTelemetry Collector file
+// exporter writes and its otlpjsonfile receiver reads.
+type WriterExporter struct {
+	serviceName string
+	mu          sync.Mutex
+	w           io.Writer
+}
+
+func NewWriterExporter(w io.Writer, serviceName string) *WriterExporter {
+	return &WriterExporter{
+		serviceName: serviceName,
+		w:           w,
+	}
+}
+
+func (e *WriterExporter) Export(ctx context.Context, spans []SpanData) error {
+	data, err := json.Marshal(newExportRequest(e.serviceName, spans))
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err = e.w.Write(append(data, '\n'))
+	return err
+}
+
+type OTLPHTTPExporterOptions struct {
+	// URL is the traces endpoint of an OTLP/HTTP receiver, e.g. http://localhost:4318/v1/traces.
+	URL         string
+	ServiceName string
+	Timeout     time.Duration
+}
+
+// OTLPHTTPExporter posts batches as OTLP/JSON to an OTLP/HTTP receiver such as the OpenTelemetry Collector.
+type OTLPHTTPExporter struct {
+	opts   *OTLPHTTPExporterOptions
+	client *http.Client
+}
+
+func NewOTLPHTTPExporter(opts OTLPHTTPExporterOptions) *OTLPHTTPExporter {
+	return &OTLPHTTPExporter{
+		opts:   &opts,
+		client: &http.Client{Timeout: opts.Timeout},
+	}
+}
+
+func (e *OTLPHTTPExporter) Export(ctx context.Context, spans []SpanData) error {
+	data, err := json.Marshal(newExportRequest(e.opts.ServiceName, spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.opts.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("OTLP receiver responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// The types below are the OTLP/JSON encoding of an ExportTraceServiceRequest. IDs are hex encoded and 64 bit
+// integers are strings, as the protobuf JSON mapping requires.
+type otlpExportRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              SpanKind       `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+// OTLP status codes.
+const (
+	otlpStatusUnset = 0
+	otlpStatusError = 2
+)
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func newExportRequest(serviceName string, spans []SpanData) otlpExportRequest {
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		otlpSpans = append(otlpSpans, newOTLPSpan(span))
+	}
+
+	return otlpExportRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: []otlpKeyValue{newOTLPKeyValue(Attribute{Key: "service.name", Value: serviceName})},
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: instrumentationScope},
+				Spans: otlpSpans,
+			}},
+		}},
+	}
+}
+
+func newOTLPSpan(span SpanData) otlpSpan {
+	result := otlpSpan{
+		TraceID:           span.SpanContext.TraceID.String(),
+		SpanID:            span.SpanContext.SpanID.String(),
+		Name:              span.Name,
+		Kind:              span.Kind,
+		StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+		Status:            otlpStatus{Code: otlpStatusUnset},
+	}
+	if span.ParentSpanID.IsValid() {
+		result.ParentSpanID = span.ParentSpanID.String()
+	}
+	if span.Err {
+		result.Status = otlpStatus{Code: otlpStatusError, Message: span.StatusMessage}
+	}
+	for _, attribute := range span.Attributes {
+		result.Attributes = append(result.Attributes, newOTLPKeyValue(attribute))
+	}
+	return result
+}
+
+func newOTLPKeyValue(attribute Attribute) otlpKeyValue {
+	var value otlpAnyValue
+	switch v := attribute.Value.(type) {
+	case string:
+		value.StringValue = &v
+	case bool:
+		value.BoolValue = &v
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		value.IntValue = &s
+	case float64:
+		value.DoubleValue = &v
+	default:
+		s := fmt.Sprint(v)
+		value.StringValue = &s
+	}
+	return otlpKeyValue{Key: attribute.Key, Value: value}
+}
@@ -0,0 +1,221 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type TraceID [16]byte
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+type SpanID [8]byte
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// SpanContext identifies a span across process boundaries.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Traceparent formats sc as a W3C traceparent header value.
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+// ParseTraceparent parses a W3C traceparent header value. ok is false for malformed values and for the
+// all-zero trace and span IDs, which both mean the caller has to start a new trace.
+func ParseTraceparent(value string) (sc SpanContext, ok bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return SpanContext{}, false
+	}
+
+	// Version ff is invalid. Version 00 has exactly four fields, later versions may append more.
+	var version [1]byte
+	if !decodeHex(version[:], parts[0]) || version[0] == 0xff || version[0] == 0 && len(parts) != 4 {
+		return SpanContext{}, false
+	}
+	if !decodeHex(sc.TraceID[:], parts[1]) || !decodeHex(sc.SpanID[:], parts[2]) {
+		return SpanContext{}, false
+	}
+
+	var flags [1]byte
+	if !decodeHex(flags[:], parts[3]) {
+		return SpanContext{}, false
+	}
+	sc.Sampled = flags[0]&0x01 != 0
+
+	return sc, sc.IsValid()
+}
+
+// decodeHex decodes lower case hex of exactly len(dst) bytes, as the traceparent format requires.
+func decodeHex(dst []byte, s string) bool {
+	if len(s) != 2*len(dst) || strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+type SpanKind int
+
+// Span kinds use the values of the OTLP protocol.
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// Attribute is a key value pair describing a span. Value is a string, bool, int64 or float64.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// SpanData is the immutable record of an ended span handed to exporters.
+type SpanData struct {
+	SpanContext   SpanContext
+	ParentSpanID  SpanID
+	Name          string
+	Kind          SpanKind
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    []Attribute
+	Err           bool
+	StatusMessage string
+}
+
+// Span is an operation being traced. A nil *Span is a span that is not recorded, all its methods are safe
+// to call.
+type Span struct {
+	tracer *Tracer
+	mu     sync.Mutex
+	data   SpanData
+	ended  bool
+}
+
+// SpanContext returns the identity of s, which is invalid for a span that is not recorded.
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.data.SpanContext
+}
+
+// SetAttribute adds an attribute. value must be a string, bool, int, int64 or float64.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	if v, ok := value.(int); ok {
+		value = int64(v)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Attributes = append(s.data.Attributes, Attribute{Key: key, Value: value})
+}
+
+// SetError marks the span as failed with message.
+func (s *Span) SetError(message string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Err = true
+	s.data.StatusMessage = message
+}
+
+// End records the end time and hands the span to the exporter. Calls after the first are ignored.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	s.tracer.enqueue(data)
+}
+
+type contextKey struct{}
+
+// remoteContextKey holds the span context of a caller in another process.
+type remoteContextKey struct{}
+
+// SpanFromContext returns the span stored in ctx, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(contextKey{}).(*Span)
+	return span
+}
+
+// ContextWithRemoteSpanContext makes spans started from ctx children of a span in another process.
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteContextKey{}, sc)
+}
+
+// parentSpanContext returns the span context new spans started from ctx descend from.
+func parentSpanContext(ctx context.Context) SpanContext {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.SpanContext()
+	}
+	sc, _ := ctx.Value(remoteContextKey{}).(SpanContext)
+	return sc
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		if _, err := rand.Read(id[:]); err != nil {
+			panic(err)
+		}
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		if _, err := rand.Read(id[:]); err != nil {
+			panic(err)
+		}
+	}
+	return id
+}
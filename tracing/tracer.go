@@ -0,0 +1,201 @@
+// Package tracing records spans of the work done for a request and exports them in the OTLP format.
+// Trace context is propagated between services with the W3C traceparent header.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/logging"
+)
+
+// TraceparentHeader is the W3C trace context header.
+const TraceparentHeader = "traceparent"
+
+type TracerOptions struct {
+	// ServiceName is reported as the service.name resource attribute.
+	ServiceName string
+	Exporter    Exporter
+	// QueueSize is the number of ended spans waiting for export. Spans ending while the queue is full are
+	// dropped.
+	QueueSize int
+	// BatchSize is the maximum number of spans per export.
+	BatchSize int
+	// Interval is how long an incomplete batch waits before it is exported.
+	Interval time.Duration
+	// Logger defaults to logging.Default().
+	Logger *logging.Logger
+}
+
+// Tracer starts spans and exports them in batches from Run.
+type Tracer struct {
+	opts    *TracerOptions
+	spans   chan SpanData
+	mu      sync.RWMutex
+	stopped bool
+	dropped atomic.Int64
+}
+
+func NewTracer(opts TracerOptions) *Tracer {
+	if opts.Logger == nil {
+		opts.Logger = logging.Default()
+	}
+	return &Tracer{
+		opts:  &opts,
+		spans: make(chan SpanData, opts.QueueSize),
+	}
+}
+
+var defaultTracer atomic.Pointer[Tracer]
+
+// SetDefault makes Start record spans with tracer. Until it is called, Start records nothing.
+func SetDefault(tracer *Tracer) {
+	defaultTracer.Store(tracer)
+}
+
+type startConfig struct {
+	kind      SpanKind
+	childOnly bool
+}
+
+type StartOption func(cfg *startConfig)
+
+func WithSpanKind(kind SpanKind) StartOption {
+	return func(cfg *startConfig) {
+		cfg.kind = kind
+	}
+}
+
+// AsChildOnly only records the span when ctx already carries a recorded span, for operations like database
+// queries that are too frequent to be worth a trace of their own, e.g. when a worker polls.
+func AsChildOnly() StartOption {
+	return func(cfg *startConfig) {
+		cfg.childOnly = true
+	}
+}
+
+// Start starts a span with the default tracer. The span is a child of the span in ctx, or of the remote span
+// stored with ContextWithRemoteSpanContext. The returned context carries the new span.
+func Start(ctx context.Context, name string, opts ...StartOption) (context.Context, *Span) {
+	tracer := defaultTracer.Load()
+	if tracer == nil {
+		return ctx, nil
+	}
+	return tracer.Start(ctx, name, opts...)
+}
+
+// Start starts a span, see the package level Start. Children of unsampled spans are not recorded.
+func (t *Tracer) Start(ctx context.Context, name string, opts ...StartOption) (context.Context, *Span) {
+	cfg := startConfig{kind: SpanKindInternal}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.childOnly && SpanFromContext(ctx) == nil {
+		return ctx, nil
+	}
+
+	parent := parentSpanContext(ctx)
+	if parent.IsValid() && !parent.Sampled {
+		return ctx, nil
+	}
+
+	traceID := parent.TraceID
+	if !traceID.IsValid() {
+		traceID = newTraceID()
+	}
+
+	span := &Span{
+		tracer: t,
+		data: SpanData{
+			SpanContext:  SpanContext{TraceID: traceID, SpanID: newSpanID(), Sampled: true},
+			ParentSpanID: parent.SpanID,
+			Name:         name,
+			Kind:         cfg.kind,
+			StartTime:    time.Now(),
+		},
+	}
+	return context.WithValue(ctx, contextKey{}, span), span
+}
+
+// Inject writes the traceparent of the span in ctx to header, so that the receiver continues the trace.
+func Inject(ctx context.Context, header http.Header) {
+	if sc := parentSpanContext(ctx); sc.IsValid() {
+		header.Set(TraceparentHeader, sc.Traceparent())
+	}
+}
+
+// Extract returns ctx with the remote span context of a valid traceparent in header.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	if sc, ok := ParseTraceparent(header.Get(TraceparentHeader)); ok {
+		return ContextWithRemoteSpanContext(ctx, sc)
+	}
+	return ctx
+}
+
+func (t *Tracer) enqueue(span SpanData) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if !t.stopped {
+		select {
+		case t.spans <- span:
+			return
+		default:
+		}
+	}
+	t.dropped.Add(1)
+}
+
+// Run exports ended spans until ctx is cancelled, then exports the spans still queued. Spans ending after
+// Run returned are dropped.
+func (t *Tracer) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.opts.Interval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, t.opts.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := t.opts.Exporter.Export(context.Background(), batch); err != nil {
+			t.opts.Logger.Error("error exporting spans", "spans", len(batch), "error", err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case span := <-t.spans:
+			batch = append(batch, span)
+			if len(batch) >= t.opts.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-ctx.Done():
+			t.mu.Lock()
+			t.stopped = true
+			t.mu.Unlock()
+
+			for {
+				select {
+				case span := <-t.spans:
+					batch = append(batch, span)
+					if len(batch) >= t.opts.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					if dropped := t.dropped.Load(); dropped > 0 {
+						t.opts.Logger.Warn("dropped spans that could not be queued for export", "spans", dropped)
+					}
+					return
+				}
+			}
+		}
+	}
+}
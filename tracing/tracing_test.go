@@ -0,0 +1,195 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/tracing"
+	"github.com/stretchr/testify/suite"
+)
+
+type recordingExporter struct {
+	mu      sync.Mutex
+	batches [][]tracing.SpanData
+}
+
+func (e *recordingExporter) Export(ctx context.Context, spans []tracing.SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.batches = append(e.batches, append([]tracing.SpanData(nil), spans...))
+	return nil
+}
+
+func (e *recordingExporter) spans() []tracing.SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var spans []tracing.SpanData
+	for _, batch := range e.batches {
+		spans = append(spans, batch...)
+	}
+	return spans
+}
+
+type TracingTestSuite struct {
+	suite.Suite
+	exporter *recordingExporter
+	sut      *tracing.Tracer
+}
+
+func (s *TracingTestSuite) SetupTest() {
+	s.exporter = &recordingExporter{}
+	s.sut = tracing.NewTracer(tracing.TracerOptions{
+		ServiceName: "user-service",
+		Exporter:    s.exporter,
+		QueueSize:   10,
+		BatchSize:   2,
+		Interval:    time.Hour,
+	})
+}
+
+func TestTracing(t *testing.T) {
+	suite.Run(t, new(TracingTestSuite))
+}
+
+// stop runs the tracer until its queue is drained, which exports every ended span.
+func (s *TracingTestSuite) stop() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.sut.Run(ctx)
+}
+
+func (s *TracingTestSuite) TestParseTraceparentGivenValidValueShouldReturnSpanContext() {
+	sc, ok := tracing.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	s.True(ok)
+	s.Equal("4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+	s.Equal("00f067aa0ba902b7", sc.SpanID.String())
+	s.True(sc.Sampled)
+	s.Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sc.Traceparent())
+}
+
+func (s *TracingTestSuite) TestParseTraceparentGivenInvalidValueShouldFail() {
+	for _, value := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+	} {
+		_, ok := tracing.ParseTraceparent(value)
+		s.False(ok, value)
+	}
+}
+
+func (s *TracingTestSuite) TestParseTraceparentGivenFutureVersionShouldIgnoreExtraFields() {
+	sc, ok := tracing.ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra")
+
+	s.True(ok)
+	s.False(sc.Sampled)
+}
+
+func (s *TracingTestSuite) TestStartShouldContinueTraceOfRemoteParent() {
+	header := http.Header{}
+	header.Set(tracing.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := tracing.Extract(context.Background(), header)
+
+	ctx, parent := s.sut.Start(ctx, "GET /api/v1/users/profile", tracing.WithSpanKind(tracing.SpanKindServer))
+	_, child := s.sut.Start(ctx, "ProfileService.GetProfile")
+	child.End()
+	parent.End()
+	s.stop()
+
+	spans := s.exporter.spans()
+	s.Require().Len(spans, 2)
+	s.Equal("ProfileService.GetProfile", spans[0].Name)
+	s.Equal("4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID.String())
+	s.Equal(parent.SpanContext().SpanID, spans[0].ParentSpanID)
+	s.Equal("00f067aa0ba902b7", spans[1].ParentSpanID.String())
+	s.Equal(tracing.SpanKindServer, spans[1].Kind)
+}
+
+func (s *TracingTestSuite) TestStartGivenUnsampledParentShouldNotRecord() {
+	header := http.Header{}
+	header.Set(tracing.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	ctx := tracing.Extract(context.Background(), header)
+
+	_, span := s.sut.Start(ctx, "GET /api/v1/users/profile")
+	span.SetAttribute("http.method", "GET")
+	span.End()
+	s.stop()
+
+	s.Nil(span)
+	s.Empty(s.exporter.spans())
+}
+
+func (s *TracingTestSuite) TestStartAsChildOnlyWithoutParentShouldNotRecord() {
+	_, span := s.sut.Start(context.Background(), "db SELECT", tracing.AsChildOnly())
+
+	s.Nil(span)
+}
+
+func (s *TracingTestSuite) TestInjectShouldWriteTraceparentOfCurrentSpan() {
+	ctx, span := s.sut.Start(context.Background(), "webhook delivery")
+	header := http.Header{}
+
+	tracing.Inject(ctx, header)
+
+	s.Equal(span.SpanContext().Traceparent(), header.Get(tracing.TraceparentHeader))
+}
+
+func (s *TracingTestSuite) TestRunShouldExportInBatches() {
+	for i := 0; i < 5; i++ {
+		_, span := s.sut.Start(context.Background(), "operation")
+		span.End()
+	}
+	s.stop()
+
+	s.Len(s.exporter.batches, 3)
+	s.Len(s.exporter.spans(), 5)
+}
+
+func (s *TracingTestSuite) TestWriterExporterShouldWriteOTLPJSON() {
+	var buf bytes.Buffer
+	exporter := tracing.NewWriterExporter(&buf, "user-service")
+	_, span := s.sut.Start(context.Background(), "db SELECT")
+	span.SetAttribute("db.system", "postgresql")
+	span.SetAttribute("http.status_code", 500)
+	span.SetError("connection refused")
+	span.End()
+	s.stop()
+
+	s.Require().NoError(exporter.Export(context.Background(), s.exporter.spans()))
+
+	var request struct {
+		ResourceSpans []struct {
+			Resource struct {
+				Attributes []map[string]interface{} `json:"attributes"`
+			} `json:"resource"`
+			ScopeSpans []struct {
+				Spans []map[string]interface{} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	s.Require().NoError(json.Unmarshal(buf.Bytes(), &request))
+	s.Equal(map[string]interface{}{"key": "service.name", "value": map[string]interface{}{"stringValue": "user-service"}}, request.ResourceSpans[0].Resource.Attributes[0])
+
+	exported := request.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	s.Equal(span.SpanContext().TraceID.String(), exported["traceId"])
+	s.Equal("db SELECT", exported["name"])
+	s.Equal(float64(tracing.SpanKindInternal), exported["kind"])
+	s.IsType("", exported["startTimeUnixNano"])
+	s.Equal(map[string]interface{}{"code": float64(2), "message": "connection refused"}, exported["status"])
+	s.Equal([]interface{}{
+		map[string]interface{}{"key": "db.system", "value": map[string]interface{}{"stringValue": "postgresql"}},
+		map[string]interface{}{"key": "http.status_code", "value": map[string]interface{}{"intValue": "500"}},
+	}, exported["attributes"])
+	s.NotContains(exported, "parentSpanId")
+}
@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/model"
+)
+
+// maxErrorBodySize caps how much of a failed response is kept in the delivery log.
+const maxErrorBodySize = 512
+
+type SenderOptions struct {
+	// Timeout bounds a single delivery attempt.
+	Timeout time.Duration
+}
+
+// Sender POSTs signed deliveries to subscriber URLs.
+type Sender struct {
+	client *http.Client
+}
+
+func NewSender(opts SenderOptions) *Sender {
+	return &Sender{
+		client: &http.Client{Timeout: opts.Timeout},
+	}
+}
+
+// Send makes one delivery attempt. Any 2xx response counts as delivered, everything else is reported
+// as a failure in the returned result.
+func (s *Sender) Send(ctx context.Context, subscription model.WebhookSubscription, delivery model.WebhookDelivery) (model.WebhookDeliveryResult, bool) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return model.WebhookDeliveryResult{Error: err.Error()}, false
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDeliveryID, delivery.ID.String())
+	req.Header.Set(HeaderEventType, string(delivery.EventType))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return model.WebhookDeliveryResult{Error: err.Error()}, false
+	}
+	defer resp.Body.Close()
+
+	statusCode := resp.StatusCode
+	result := model.WebhookDeliveryResult{StatusCode: &statusCode}
+	if statusCode >= 200 && statusCode <= 299 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return result, true
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	result.Error = "subscriber responded with status " + strconv.Itoa(statusCode)
+	if len(body) > 0 {
+		result.Error += ": " + string(body)
+	}
+	return result, false
+}
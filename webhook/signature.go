@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderDeliveryID = "X-Webhook-ID"
+	HeaderEventType  = "X-Webhook-Event"
+	HeaderTimestamp  = "X-Webhook-Timestamp"
+	HeaderSignature  = "X-Webhook-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+var (
+	ErrInvalidTimestamp = errors.New("webhook timestamp is invalid or outside the tolerance")
+	ErrInvalidSignature = errors.New("webhook signature does not match")
+)
+
+// Sign returns the signature header value for body sent at timestamp. The timestamp is part of the signed
+// content so that a captured request cannot be replayed later with a fresh timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the timestamp and signature headers of a received webhook. Receivers should reject requests
+// whose timestamp is further than tolerance away from now.
+func Verify(secret, timestampHeader, signatureHeader string, body []byte, tolerance time.Duration, now time.Time) error {
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+
+	if diff := now.Sub(time.Unix(timestamp, 0)); diff > tolerance || diff < -tolerance {
+		return ErrInvalidTimestamp
+	}
+
+	if !strings.HasPrefix(signatureHeader, signaturePrefix) || !hmac.Equal([]byte(signatureHeader), []byte(Sign(secret, timestamp, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/logging"
+	"github.com/SawitProRecruitment/UserService/service"
+)
+
+type AccountAnonymizerOptions struct {
+	AccountService service.AccountService
+	// Interval is the time between two anonymization runs.
+	Interval time.Duration
+	// BatchSize caps the number of accounts anonymized per run.
+	BatchSize int
+	// Logger defaults to logging.Default().
+	Logger *logging.Logger
+}
+
+// AccountAnonymizer periodically anonymizes accounts whose deletion grace period has ended.
+type AccountAnonymizer struct {
+	opts *AccountAnonymizerOptions
+}
+
+func NewAccountAnonymizer(opts AccountAnonymizerOptions) *AccountAnonymizer {
+	if opts.Logger == nil {
+		opts.Logger = logging.Default()
+	}
+	return &AccountAnonymizer{
+		opts: &opts,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (w *AccountAnonymizer) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *AccountAnonymizer) runOnce(ctx context.Context) {
+	// Keep going while full batches come back so that a backlog is cleared within one tick.
+	for ctx.Err() == nil {
+		count, err := w.opts.AccountService.AnonymizeDeletedAccounts(ctx, w.opts.BatchSize)
+		if err != nil {
+			w.opts.Logger.Error("error anonymizing deleted accounts", "error", err)
+			return
+		}
+
+		if count < w.opts.BatchSize {
+			return
+		}
+	}
+}
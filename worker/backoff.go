@@ -0,0 +1,15 @@
+package worker
+
+import "time"
+
+// exponentialBackoff doubles base for every previous attempt, capped at max.
+func exponentialBackoff(base, max time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 0; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
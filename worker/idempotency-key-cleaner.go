@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/logging"
+	"github.com/SawitProRecruitment/UserService/service"
+)
+
+type IdempotencyKeyCleanerOptions struct {
+	IdempotencyService service.IdempotencyService
+	// Interval is the time between two runs.
+	Interval time.Duration
+	// BatchSize caps the number of keys deleted per query.
+	BatchSize int
+	// Logger defaults to logging.Default().
+	Logger *logging.Logger
+}
+
+// IdempotencyKeyCleaner periodically deletes expired idempotency keys and the responses stored with them.
+type IdempotencyKeyCleaner struct {
+	opts *IdempotencyKeyCleanerOptions
+}
+
+func NewIdempotencyKeyCleaner(opts IdempotencyKeyCleanerOptions) *IdempotencyKeyCleaner {
+	if opts.Logger == nil {
+		opts.Logger = logging.Default()
+	}
+	return &IdempotencyKeyCleaner{
+		opts: &opts,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (w *IdempotencyKeyCleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *IdempotencyKeyCleaner) runOnce(ctx context.Context) {
+	// Keep going while full batches come back so that a backlog is cleared within one tick.
+	for ctx.Err() == nil {
+		count, err := w.opts.IdempotencyService.DeleteExpired(ctx, w.opts.BatchSize)
+		if err != nil {
+			w.opts.Logger.Error("error deleting expired idempotency keys", "error", err)
+			return
+		}
+
+		if count < w.opts.BatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/SawitProRecruitment/UserService/logging"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+)
+
+type LoginLogRecorderOptions struct {
+	LoginLogRepository repository.LoginLogRepository
+	// BufferSize is the number of logins queued before Record saves synchronously.
+	BufferSize int
+	// Logger defaults to logging.Default().
+	Logger *logging.Logger
+}
+
+// LoginLogRecorder saves login logs in the background so that logging in does not wait for the insert.
+type LoginLogRecorder struct {
+	opts    *LoginLogRecorderOptions
+	logs    chan model.LoginLog
+	mu      sync.RWMutex
+	stopped bool
+}
+
+func NewLoginLogRecorder(opts LoginLogRecorderOptions) *LoginLogRecorder {
+	if opts.Logger == nil {
+		opts.Logger = logging.Default()
+	}
+	return &LoginLogRecorder{
+		opts: &opts,
+		logs: make(chan model.LoginLog, opts.BufferSize),
+	}
+}
+
+// Record queues the log for Run. When the queue is full or Run has stopped, the log is saved before Record
+// returns instead, so no login is lost.
+func (w *LoginLogRecorder) Record(ctx context.Context, loginLog model.LoginLog) {
+	w.mu.RLock()
+	if !w.stopped {
+		select {
+		case w.logs <- loginLog:
+			w.mu.RUnlock()
+			return
+		default:
+		}
+	}
+	w.mu.RUnlock()
+
+	w.save(ctx, loginLog)
+}
+
+// QueueDepth returns the number of logs waiting to be saved.
+func (w *LoginLogRecorder) QueueDepth() int {
+	return len(w.logs)
+}
+
+// Run saves queued logs until ctx is cancelled, then saves the logs still queued before returning.
+// Queued logs are saved without a deadline of their own, they are bounded by the shutdown timeout.
+func (w *LoginLogRecorder) Run(ctx context.Context) {
+	for {
+		select {
+		case loginLog := <-w.logs:
+			w.save(context.Background(), loginLog)
+		case <-ctx.Done():
+			w.mu.Lock()
+			w.stopped = true
+			w.mu.Unlock()
+
+			// Record no longer queues, so the queue only shrinks from here.
+			for {
+				select {
+				case loginLog := <-w.logs:
+					w.save(context.Background(), loginLog)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (w *LoginLogRecorder) save(ctx context.Context, loginLog model.LoginLog) {
+	if _, err := w.opts.LoginLogRepository.Save(ctx, loginLog); err != nil {
+		w.opts.Logger.Error("error saving login log", "user_id", loginLog.UserID, "error", err)
+	}
+}
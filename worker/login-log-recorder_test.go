@@ -0,0 +1,84 @@
+package worker_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/SawitProRecruitment/UserService/worker"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoginLogRecorderTestSuite struct {
+	suite.Suite
+	ctrl               *gomock.Controller
+	loginLogRepository *repository.MockLoginLogRepository
+	mu                 sync.Mutex
+	saved              []uuid.UUID
+}
+
+func (s *LoginLogRecorderTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.loginLogRepository = repository.NewMockLoginLogRepository(s.ctrl)
+	s.saved = nil
+	s.loginLogRepository.EXPECT().Save(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, loginLog model.LoginLog) (uuid.UUID, *common.CustomError) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.saved = append(s.saved, loginLog.UserID)
+			return uuid.New(), nil
+		})
+}
+
+func (s *LoginLogRecorderTestSuite) AfterTest(suiteName, testName string) {
+	s.ctrl.Finish()
+}
+
+func TestLoginLogRecorder(t *testing.T) {
+	suite.Run(t, new(LoginLogRecorderTestSuite))
+}
+
+func (s *LoginLogRecorderTestSuite) TestRunShouldSaveLogsQueuedBeforeStopping() {
+	sut := worker.NewLoginLogRecorder(worker.LoginLogRecorderOptions{LoginLogRepository: s.loginLogRepository, BufferSize: 10})
+	userIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+	// Queue everything before Run starts, so Run only sees the logs while stopping.
+	for _, userID := range userIDs {
+		sut.Record(context.Background(), model.LoginLog{UserID: userID, LoginAt: time.Now()})
+	}
+	s.Empty(s.saved)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sut.Run(ctx)
+
+	s.ElementsMatch(userIDs, s.saved)
+}
+
+func (s *LoginLogRecorderTestSuite) TestRecordGivenFullQueueShouldSaveSynchronously() {
+	sut := worker.NewLoginLogRecorder(worker.LoginLogRecorderOptions{LoginLogRepository: s.loginLogRepository, BufferSize: 1})
+	queued, overflow := uuid.New(), uuid.New()
+
+	sut.Record(context.Background(), model.LoginLog{UserID: queued})
+	sut.Record(context.Background(), model.LoginLog{UserID: overflow})
+
+	s.Equal([]uuid.UUID{overflow}, s.saved)
+}
+
+func (s *LoginLogRecorderTestSuite) TestRecordAfterStopShouldSaveSynchronously() {
+	sut := worker.NewLoginLogRecorder(worker.LoginLogRecorderOptions{LoginLogRepository: s.loginLogRepository, BufferSize: 10})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sut.Run(ctx)
+
+	userID := uuid.New()
+	sut.Record(context.Background(), model.LoginLog{UserID: userID})
+
+	s.Equal([]uuid.UUID{userID}, s.saved)
+}
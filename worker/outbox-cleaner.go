@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/logging"
+	"github.com/SawitProRecruitment/UserService/repository"
+)
+
+type OutboxCleanerOptions struct {
+	OutboxRepository repository.OutboxRepository
+	// Retention is how long published events are kept.
+	Retention time.Duration
+	// Interval is the time between two runs.
+	Interval time.Duration
+	// BatchSize caps the number of events deleted per query.
+	BatchSize int
+	// Logger defaults to logging.Default().
+	Logger *logging.Logger
+}
+
+// OutboxCleaner periodically deletes the outbox events published longer than the retention ago. Events that
+// have not been published yet are kept however old they are.
+type OutboxCleaner struct {
+	opts *OutboxCleanerOptions
+}
+
+func NewOutboxCleaner(opts OutboxCleanerOptions) *OutboxCleaner {
+	if opts.Logger == nil {
+		opts.Logger = logging.Default()
+	}
+	return &OutboxCleaner{
+		opts: &opts,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (w *OutboxCleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *OutboxCleaner) runOnce(ctx context.Context) {
+	before := time.Now().Add(-w.opts.Retention)
+
+	// Keep going while full batches come back so that a backlog is cleared within one tick.
+	for ctx.Err() == nil {
+		count, err := w.opts.OutboxRepository.DeletePublishedBefore(ctx, before, w.opts.BatchSize)
+		if err != nil {
+			w.opts.Logger.Error("error deleting published outbox events", "error", err)
+			return
+		}
+
+		if count < w.opts.BatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package worker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/SawitProRecruitment/UserService/worker"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+)
+
+type OutboxCleanerTestSuite struct {
+	suite.Suite
+	ctrl             *gomock.Controller
+	outboxRepository *repository.MockOutboxRepository
+	sut              *worker.OutboxCleaner
+}
+
+func (s *OutboxCleanerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.outboxRepository = repository.NewMockOutboxRepository(s.ctrl)
+	s.sut = worker.NewOutboxCleaner(worker.OutboxCleanerOptions{
+		OutboxRepository: s.outboxRepository,
+		Retention:        24 * time.Hour,
+		Interval:         time.Hour,
+		BatchSize:        10,
+	})
+}
+
+func (s *OutboxCleanerTestSuite) AfterTest(suiteName, testName string) {
+	s.ctrl.Finish()
+}
+
+func TestOutboxCleaner(t *testing.T) {
+	suite.Run(t, new(OutboxCleanerTestSuite))
+}
+
+func (s *OutboxCleanerTestSuite) TestRunShouldDeleteEventsPublishedBeforeRetentionWhileBatchesAreFull() {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := time.Now()
+
+	checkRetention := func(_ context.Context, before time.Time, _ int) {
+		s.WithinRange(before, started.Add(-24*time.Hour), time.Now().Add(-24*time.Hour))
+	}
+	gomock.InOrder(
+		s.outboxRepository.EXPECT().DeletePublishedBefore(gomock.Any(), gomock.Any(), 10).Do(checkRetention).Return(10, nil),
+		s.outboxRepository.EXPECT().DeletePublishedBefore(gomock.Any(), gomock.Any(), 10).DoAndReturn(
+			func(ctx context.Context, before time.Time, limit int) (int, *common.CustomError) {
+				checkRetention(ctx, before, limit)
+				cancel()
+				return 4, nil
+			}),
+	)
+
+	s.sut.Run(ctx)
+}
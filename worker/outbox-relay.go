@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/logging"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/publisher"
+	"github.com/SawitProRecruitment/UserService/repository"
+)
+
+type OutboxRelayOptions struct {
+	OutboxRepository repository.OutboxRepository
+	Publisher        publisher.Publisher
+	// Interval is the time between two polls of the outbox.
+	Interval time.Duration
+	// BatchSize caps the number of events claimed per poll.
+	BatchSize int
+	// Lease is how long a claimed event is hidden from other relays while it is being published.
+	Lease time.Duration
+	// MaxBackoff caps the delay between two attempts of a failing event.
+	MaxBackoff time.Duration
+	// Logger defaults to logging.Default().
+	Logger *logging.Logger
+}
+
+// OutboxRelay delivers outbox events through the publisher. An event is only marked as published after
+// the publisher accepted it, so it may be delivered more than once but is never lost.
+type OutboxRelay struct {
+	opts *OutboxRelayOptions
+}
+
+func NewOutboxRelay(opts OutboxRelayOptions) *OutboxRelay {
+	if opts.Logger == nil {
+		opts.Logger = logging.Default()
+	}
+	return &OutboxRelay{
+		opts: &opts,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (w *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *OutboxRelay) runOnce(ctx context.Context) {
+	for ctx.Err() == nil {
+		events, err := w.opts.OutboxRepository.ClaimPending(ctx, w.opts.BatchSize, w.opts.Lease)
+		if err != nil {
+			w.opts.Logger.Error("error claiming outbox events", "error", err)
+			return
+		}
+
+		for _, event := range events {
+			w.publish(ctx, event)
+		}
+
+		if len(events) < w.opts.BatchSize {
+			return
+		}
+	}
+}
+
+func (w *OutboxRelay) publish(ctx context.Context, event model.OutboxEvent) {
+	if err := w.opts.Publisher.Publish(ctx, event); err != nil {
+		w.opts.Logger.Warn("error publishing outbox event", "event_id", event.ID, "error", err)
+
+		if err := w.opts.OutboxRepository.MarkFailed(ctx, event.ID, err.Error(), time.Now().Add(exponentialBackoff(w.opts.Interval, w.opts.MaxBackoff, event.Attempts))); err != nil {
+			w.opts.Logger.Error("error marking outbox event as failed", "event_id", event.ID, "error", err)
+		}
+		return
+	}
+
+	if err := w.opts.OutboxRepository.MarkPublished(ctx, event.ID, time.Now()); err != nil {
+		w.opts.Logger.Error("error marking outbox event as published", "event_id", event.ID, "error", err)
+	}
+}
@@ -0,0 +1,94 @@
+package worker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/SawitProRecruitment/UserService/worker"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type fakePublisher struct {
+	err       error
+	published []model.OutboxEvent
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, event)
+	return nil
+}
+
+type OutboxRelayTestSuite struct {
+	suite.Suite
+	ctrl             *gomock.Controller
+	outboxRepository *repository.MockOutboxRepository
+	publisher        *fakePublisher
+	sut              *worker.OutboxRelay
+}
+
+func (s *OutboxRelayTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.outboxRepository = repository.NewMockOutboxRepository(s.ctrl)
+	s.publisher = &fakePublisher{}
+	s.sut = worker.NewOutboxRelay(worker.OutboxRelayOptions{
+		OutboxRepository: s.outboxRepository,
+		Publisher:        s.publisher,
+		Interval:         time.Second,
+		BatchSize:        10,
+		Lease:            time.Minute,
+		MaxBackoff:       time.Hour,
+	})
+}
+
+func (s *OutboxRelayTestSuite) AfterTest(suiteName, testName string) {
+	s.ctrl.Finish()
+}
+
+func TestOutboxRelay(t *testing.T) {
+	suite.Run(t, new(OutboxRelayTestSuite))
+}
+
+func (s *OutboxRelayTestSuite) TestRunGivenPublishedEventShouldMarkItPublished() {
+	ctx, cancel := context.WithCancel(context.Background())
+	event := model.OutboxEvent{ID: uuid.New(), EventType: model.OutboxEventUserRegistered, AggregateID: uuid.New()}
+
+	s.outboxRepository.EXPECT().ClaimPending(gomock.Any(), 10, time.Minute).DoAndReturn(
+		func(context.Context, int, time.Duration) ([]model.OutboxEvent, *common.CustomError) {
+			cancel()
+			return []model.OutboxEvent{event}, nil
+		})
+	s.outboxRepository.EXPECT().MarkPublished(gomock.Any(), event.ID, gomock.Any()).Return(nil)
+
+	s.sut.Run(ctx)
+
+	s.Equal([]model.OutboxEvent{event}, s.publisher.published)
+}
+
+func (s *OutboxRelayTestSuite) TestRunGivenPublishFailureShouldRetryLaterWithBackoff() {
+	ctx, cancel := context.WithCancel(context.Background())
+	event := model.OutboxEvent{ID: uuid.New(), EventType: model.OutboxEventUserRegistered, AggregateID: uuid.New(), Attempts: 3}
+	s.publisher.err = errors.New("connection refused")
+
+	s.outboxRepository.EXPECT().ClaimPending(gomock.Any(), 10, time.Minute).DoAndReturn(
+		func(context.Context, int, time.Duration) ([]model.OutboxEvent, *common.CustomError) {
+			cancel()
+			return []model.OutboxEvent{event}, nil
+		})
+	s.outboxRepository.EXPECT().MarkFailed(gomock.Any(), event.ID, "connection refused", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ uuid.UUID, _ string, nextAttemptAt time.Time) *common.CustomError {
+			s.InDelta(8*time.Second, time.Until(nextAttemptAt), float64(time.Second))
+			return nil
+		})
+
+	s.sut.Run(ctx)
+}
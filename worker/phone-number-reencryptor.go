@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/logging"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/google/uuid"
+)
+
+type PhoneNumberReencryptorOptions struct {
+	UserRepository repository.UserRepository
+	// Interval is the time between two runs.
+	Interval time.Duration
+	// BatchSize caps the number of phone numbers encrypted per transaction.
+	BatchSize int
+	// Logger defaults to logging.Default().
+	Logger *logging.Logger
+}
+
+// PhoneNumberReencryptor moves phone numbers that are still stored in plaintext, under a retired key or in a
+// notation other than E.164 onto the active key, in E.164. After a key rotation the previous key can be
+// removed once it has encrypted them all.
+type PhoneNumberReencryptor struct {
+	opts *PhoneNumberReencryptorOptions
+}
+
+func NewPhoneNumberReencryptor(opts PhoneNumberReencryptorOptions) *PhoneNumberReencryptor {
+	if opts.Logger == nil {
+		opts.Logger = logging.Default()
+	}
+	return &PhoneNumberReencryptor{
+		opts: &opts,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (w *PhoneNumberReencryptor) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce goes through the users in ID order, so that numbers left as they are because of a conflict are
+// passed over instead of filling every batch.
+func (w *PhoneNumberReencryptor) runOnce(ctx context.Context) {
+	total := 0
+	after := uuid.Nil
+	for ctx.Err() == nil {
+		batch, err := w.opts.UserRepository.ReencryptPhoneNumbers(ctx, after, w.opts.BatchSize)
+		if err != nil {
+			w.opts.Logger.Error("error re-encrypting phone numbers", "error", err, "reencrypted", total)
+			return
+		}
+		total += batch.Reencrypted
+
+		if len(batch.Conflicts) > 0 {
+			w.opts.Logger.Error("phone numbers already used by another user are left as they are", "user_ids", batch.Conflicts)
+		}
+		if len(batch.Invalid) > 0 {
+			w.opts.Logger.Error("phone numbers that cannot be parsed are kept as stored and flagged", "user_ids", batch.Invalid)
+		}
+
+		if batch.Size < w.opts.BatchSize {
+			break
+		}
+		after = batch.LastUserID
+	}
+
+	if total > 0 {
+		w.opts.Logger.Info("re-encrypted phone numbers", "reencrypted", total)
+	}
+}
@@ -0,0 +1,93 @@
+package worker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/SawitProRecruitment/UserService/worker"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type PhoneNumberReencryptorTestSuite struct {
+	suite.Suite
+	ctrl           *gomock.Controller
+	userRepository *repository.MockUserRepository
+	sut            *worker.PhoneNumberReencryptor
+}
+
+func (s *PhoneNumberReencryptorTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.userRepository = repository.NewMockUserRepository(s.ctrl)
+	s.sut = worker.NewPhoneNumberReencryptor(worker.PhoneNumberReencryptorOptions{
+		UserRepository: s.userRepository,
+		Interval:       time.Hour,
+		BatchSize:      10,
+	})
+}
+
+func (s *PhoneNumberReencryptorTestSuite) AfterTest(suiteName, testName string) {
+	s.ctrl.Finish()
+}
+
+func TestPhoneNumberReencryptor(t *testing.T) {
+	suite.Run(t, new(PhoneNumberReencryptorTestSuite))
+}
+
+func (s *PhoneNumberReencryptorTestSuite) TestRunShouldContinueAfterLastUserWhileBatchesAreFull() {
+	ctx, cancel := context.WithCancel(context.Background())
+	first := uuid.New()
+	second := uuid.New()
+
+	gomock.InOrder(
+		s.userRepository.EXPECT().ReencryptPhoneNumbers(gomock.Any(), uuid.Nil, 10).
+			Return(model.PhoneNumberReencryption{Size: 10, LastUserID: first, Reencrypted: 10}, nil),
+		s.userRepository.EXPECT().ReencryptPhoneNumbers(gomock.Any(), first, 10).
+			Return(model.PhoneNumberReencryption{Size: 10, LastUserID: second, Reencrypted: 10}, nil),
+		s.userRepository.EXPECT().ReencryptPhoneNumbers(gomock.Any(), second, 10).DoAndReturn(
+			func(context.Context, uuid.UUID, int) (model.PhoneNumberReencryption, *common.CustomError) {
+				cancel()
+				return model.PhoneNumberReencryption{Size: 3, Reencrypted: 3}, nil
+			}),
+	)
+
+	s.sut.Run(ctx)
+}
+
+func (s *PhoneNumberReencryptorTestSuite) TestRunGivenConflictsShouldMovePastThem() {
+	ctx, cancel := context.WithCancel(context.Background())
+	last := uuid.New()
+	conflicts := make([]uuid.UUID, 10)
+	for i := range conflicts {
+		conflicts[i] = uuid.New()
+	}
+
+	gomock.InOrder(
+		s.userRepository.EXPECT().ReencryptPhoneNumbers(gomock.Any(), uuid.Nil, 10).
+			Return(model.PhoneNumberReencryption{Size: 10, LastUserID: last, Conflicts: conflicts}, nil),
+		s.userRepository.EXPECT().ReencryptPhoneNumbers(gomock.Any(), last, 10).DoAndReturn(
+			func(context.Context, uuid.UUID, int) (model.PhoneNumberReencryption, *common.CustomError) {
+				cancel()
+				return model.PhoneNumberReencryption{}, nil
+			}),
+	)
+
+	s.sut.Run(ctx)
+}
+
+func (s *PhoneNumberReencryptorTestSuite) TestRunGivenErrorShouldWaitForNextRun() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.userRepository.EXPECT().ReencryptPhoneNumbers(gomock.Any(), uuid.Nil, 10).DoAndReturn(
+		func(context.Context, uuid.UUID, int) (model.PhoneNumberReencryption, *common.CustomError) {
+			cancel()
+			return model.PhoneNumberReencryption{}, common.NewCustomError(common.ErrUnexpectedError, "error decrypting phone number")
+		}).Times(1)
+
+	s.sut.Run(ctx)
+}
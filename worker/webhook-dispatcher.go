@@ -0,0 +1,113 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/logging"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/SawitProRecruitment/UserService/webhook"
+	"github.com/google/uuid"
+)
+
+type WebhookDispatcherOptions struct {
+	WebhookRepository repository.WebhookRepository
+	Sender            *webhook.Sender
+	// Interval is the time between two polls of the delivery queue.
+	Interval time.Duration
+	// BatchSize caps the number of deliveries claimed per poll.
+	BatchSize int
+	// Lease is how long a claimed delivery is hidden from other dispatchers while it is being sent.
+	Lease time.Duration
+	// RetryBackoff is the delay after the first failed attempt, it doubles with every further failure.
+	RetryBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration
+	// MaxAttempts is the number of failed attempts after which a delivery is dead-lettered.
+	MaxAttempts int
+	// Logger defaults to logging.Default().
+	Logger *logging.Logger
+}
+
+// WebhookDispatcher sends queued webhook deliveries to their subscribers.
+type WebhookDispatcher struct {
+	opts *WebhookDispatcherOptions
+}
+
+func NewWebhookDispatcher(opts WebhookDispatcherOptions) *WebhookDispatcher {
+	if opts.Logger == nil {
+		opts.Logger = logging.Default()
+	}
+	return &WebhookDispatcher{
+		opts: &opts,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (w *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *WebhookDispatcher) runOnce(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := w.opts.WebhookRepository.ClaimPendingDeliveries(ctx, w.opts.BatchSize, w.opts.Lease)
+		if err != nil {
+			w.opts.Logger.Error("error claiming webhook deliveries", "error", err)
+			return
+		}
+
+		subscriptions := map[uuid.UUID]*model.WebhookSubscription{}
+		for _, delivery := range deliveries {
+			subscription, ok := subscriptions[delivery.SubscriptionID]
+			if !ok {
+				if subscription, err = w.opts.WebhookRepository.GetSubscription(ctx, delivery.SubscriptionID); err != nil {
+					// The lease expires on its own, the delivery is picked up again on a later poll.
+					w.opts.Logger.Error("error loading webhook subscription", "subscription_id", delivery.SubscriptionID, "error", err)
+					continue
+				}
+				subscriptions[delivery.SubscriptionID] = subscription
+			}
+
+			w.dispatch(ctx, *subscription, delivery)
+		}
+
+		if len(deliveries) < w.opts.BatchSize {
+			return
+		}
+	}
+}
+
+func (w *WebhookDispatcher) dispatch(ctx context.Context, subscription model.WebhookSubscription, delivery model.WebhookDelivery) {
+	result, ok := w.opts.Sender.Send(ctx, subscription, delivery)
+	if ok {
+		if err := w.opts.WebhookRepository.MarkDelivered(ctx, delivery.ID, *result.StatusCode, time.Now()); err != nil {
+			w.opts.Logger.Error("error marking webhook delivery as delivered", "delivery_id", delivery.ID, "error", err)
+		}
+		return
+	}
+
+	if delivery.Attempts+1 >= w.opts.MaxAttempts {
+		w.opts.Logger.Warn("webhook delivery dead-lettered", "delivery_id", delivery.ID, "attempts", delivery.Attempts+1, "error", result.Error)
+		if err := w.opts.WebhookRepository.MarkDeadLettered(ctx, delivery.ID, result); err != nil {
+			w.opts.Logger.Error("error dead-lettering webhook delivery", "delivery_id", delivery.ID, "error", err)
+		}
+		return
+	}
+
+	nextAttemptAt := time.Now().Add(exponentialBackoff(w.opts.RetryBackoff, w.opts.MaxBackoff, delivery.Attempts))
+	if err := w.opts.WebhookRepository.MarkFailed(ctx, delivery.ID, result, nextAttemptAt); err != nil {
+		w.opts.Logger.Error("error marking webhook delivery as failed", "delivery_id", delivery.ID, "error", err)
+	}
+}
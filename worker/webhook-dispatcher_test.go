@@ -0,0 +1,147 @@
+package worker_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/common"
+	"github.com/SawitProRecruitment/UserService/model"
+	"github.com/SawitProRecruitment/UserService/repository"
+	"github.com/SawitProRecruitment/UserService/webhook"
+	"github.com/SawitProRecruitment/UserService/worker"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+const testWebhookSecret = "0123456789abcdef0123456789abcdef"
+
+type WebhookDispatcherTestSuite struct {
+	suite.Suite
+	ctrl              *gomock.Controller
+	webhookRepository *repository.MockWebhookRepository
+	receiver          *httptest.Server
+	statusCode        int
+	received          []*http.Request
+	receivedBodies    [][]byte
+	sut               *worker.WebhookDispatcher
+}
+
+func (s *WebhookDispatcherTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.webhookRepository = repository.NewMockWebhookRepository(s.ctrl)
+	s.statusCode = http.StatusNoContent
+	s.received = nil
+	s.receivedBodies = nil
+	s.receiver = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.received = append(s.received, r)
+		s.receivedBodies = append(s.receivedBodies, body)
+		w.WriteHeader(s.statusCode)
+	}))
+	s.sut = worker.NewWebhookDispatcher(worker.WebhookDispatcherOptions{
+		WebhookRepository: s.webhookRepository,
+		Sender:            webhook.NewSender(webhook.SenderOptions{Timeout: time.Second}),
+		Interval:          time.Second,
+		BatchSize:         10,
+		Lease:             time.Minute,
+		RetryBackoff:      time.Minute,
+		MaxBackoff:        time.Hour,
+		MaxAttempts:       3,
+	})
+}
+
+func (s *WebhookDispatcherTestSuite) AfterTest(suiteName, testName string) {
+	s.receiver.Close()
+	s.ctrl.Finish()
+}
+
+func TestWebhookDispatcher(t *testing.T) {
+	suite.Run(t, new(WebhookDispatcherTestSuite))
+}
+
+func (s *WebhookDispatcherTestSuite) TestRunGivenAcceptingReceiverShouldSendSignedPayloadAndMarkDelivered() {
+	ctx, cancel := context.WithCancel(context.Background())
+	subscription, delivery := s.newDelivery(0)
+
+	s.expectClaim(delivery)
+	s.webhookRepository.EXPECT().GetSubscription(gomock.Any(), subscription.ID).Return(&subscription, nil)
+	s.webhookRepository.EXPECT().MarkDelivered(gomock.Any(), delivery.ID, http.StatusNoContent, gomock.Any()).Do(
+		func(context.Context, uuid.UUID, int, time.Time) { cancel() })
+
+	s.sut.Run(ctx)
+
+	s.Require().Len(s.received, 1)
+	req := s.received[0]
+	s.Equal(delivery.ID.String(), req.Header.Get(webhook.HeaderDeliveryID))
+	s.Equal(string(delivery.EventType), req.Header.Get(webhook.HeaderEventType))
+	s.JSONEq(string(delivery.Payload), string(s.receivedBodies[0]))
+	s.NoError(webhook.Verify(testWebhookSecret, req.Header.Get(webhook.HeaderTimestamp), req.Header.Get(webhook.HeaderSignature),
+		s.receivedBodies[0], 5*time.Minute, time.Now()))
+	s.ErrorIs(webhook.Verify("another secret", req.Header.Get(webhook.HeaderTimestamp), req.Header.Get(webhook.HeaderSignature),
+		s.receivedBodies[0], 5*time.Minute, time.Now()), webhook.ErrInvalidSignature)
+}
+
+func (s *WebhookDispatcherTestSuite) TestRunGivenFailingReceiverShouldScheduleRetryWithBackoff() {
+	ctx, cancel := context.WithCancel(context.Background())
+	subscription, delivery := s.newDelivery(1)
+	s.statusCode = http.StatusInternalServerError
+
+	s.expectClaim(delivery)
+	s.webhookRepository.EXPECT().GetSubscription(gomock.Any(), subscription.ID).Return(&subscription, nil)
+	s.webhookRepository.EXPECT().MarkFailed(gomock.Any(), delivery.ID, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ uuid.UUID, result model.WebhookDeliveryResult, nextAttemptAt time.Time) *common.CustomError {
+			s.Require().NotNil(result.StatusCode)
+			s.Equal(http.StatusInternalServerError, *result.StatusCode)
+			s.InDelta(2*time.Minute, time.Until(nextAttemptAt), float64(time.Second))
+			cancel()
+			return nil
+		})
+
+	s.sut.Run(ctx)
+}
+
+func (s *WebhookDispatcherTestSuite) TestRunGivenLastAttemptFailsShouldDeadLetterDelivery() {
+	ctx, cancel := context.WithCancel(context.Background())
+	subscription, delivery := s.newDelivery(2)
+	s.statusCode = http.StatusGone
+
+	s.expectClaim(delivery)
+	s.webhookRepository.EXPECT().GetSubscription(gomock.Any(), subscription.ID).Return(&subscription, nil)
+	s.webhookRepository.EXPECT().MarkDeadLettered(gomock.Any(), delivery.ID, gomock.Any()).Do(
+		func(context.Context, uuid.UUID, model.WebhookDeliveryResult) { cancel() })
+
+	s.sut.Run(ctx)
+}
+
+// expectClaim hands out the deliveries once. The test cancels ctx from the expectation of the final repository
+// call, cancelling earlier would abort the request to the receiver.
+func (s *WebhookDispatcherTestSuite) expectClaim(deliveries ...model.WebhookDelivery) {
+	s.webhookRepository.EXPECT().ClaimPendingDeliveries(gomock.Any(), 10, time.Minute).Return(deliveries, nil)
+}
+
+func (s *WebhookDispatcherTestSuite) newDelivery(attempts int) (model.WebhookSubscription, model.WebhookDelivery) {
+	subscription := model.WebhookSubscription{
+		ID:     uuid.New(),
+		URL:    s.receiver.URL,
+		Secret: testWebhookSecret,
+		Active: true,
+	}
+
+	payload, _ := json.Marshal(map[string]string{"type": string(model.OutboxEventUserRegistered)})
+	delivery := model.WebhookDelivery{
+		ID:             uuid.New(),
+		SubscriptionID: subscription.ID,
+		EventID:        uuid.New(),
+		EventType:      model.OutboxEventUserRegistered,
+		Payload:        payload,
+		Status:         model.WebhookDeliveryPending,
+		Attempts:       attempts,
+	}
+	return subscription, delivery
+}